@@ -1,6 +1,14 @@
 package tview
 
-import "github.com/nowakf/pixel/pixelgl"
+import (
+	"image"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/pixel/text"
+	"golang.org/x/image/font"
+)
 
 type Config struct {
 	//ubcell config
@@ -8,8 +16,22 @@ type Config struct {
 	FontPath         string
 	AdjustX, AdjustY float64
 	DPI              float64
+	//fonts consulted, in order, for glyphs missing from FontPath
+	Fallbacks []Font
+	//runes added to the glyph atlas, DefaultRuneSets if empty
+	RuneSets [][]rune
+	//optional directory of color glyphs, see LoadColorGlyphs
+	ColorGlyphPath string
 	//pixel config
 	WindowConfig pixelgl.WindowConfig
+
+	// The glyphs loaded by loadGlyphs(), and the error which occurred while
+	// loading them.
+	glyphsOnce  sync.Once
+	face        font.Face
+	atlas       *text.Atlas
+	colorGlyphs map[rune]image.Image
+	glyphsErr   error
 }
 
 func (c *Config) GetFontSize() float64 {
@@ -20,6 +42,70 @@ func (c *Config) GetFontPath() string {
 	return c.FontPath
 }
 
+// GetFontFace returns the face from which the glyph atlas is built. Each glyph
+// is taken from the first font of the chain (FontPath, then Fallbacks) which
+// contains it.
+func (c *Config) GetFontFace() (font.Face, error) {
+	c.loadGlyphs()
+	return c.face, c.glyphsErr
+}
+
+// GetAtlas returns the glyph atlas built from GetFontFace() with the runes of
+// GetRuneSets(). As the face resolves each glyph individually, the atlas
+// contains the glyphs of all fonts in the chain. The atlas is built once, when
+// the application starts (see Application.Run()), and then reused.
+//
+// The screen draws the glyphs of the primary font (FontPath) itself. The glyphs
+// which it lacks are drawn from this atlas by the application, over the cells
+// the screen has drawn.
+func (c *Config) GetAtlas() (*text.Atlas, error) {
+	c.loadGlyphs()
+	return c.atlas, c.glyphsErr
+}
+
+// GetRuneSets returns the runes which are added to the glyph atlas.
+func (c *Config) GetRuneSets() [][]rune {
+	if len(c.RuneSets) == 0 {
+		return DefaultRuneSets
+	}
+	return c.RuneSets
+}
+
+// GetColorGlyphs returns the color bitmaps which the application draws instead
+// of the font's glyphs, or nil if ColorGlyphPath is not set. Files which could
+// not be loaded are skipped (see LoadColorGlyphs()).
+func (c *Config) GetColorGlyphs() (map[rune]image.Image, error) {
+	c.loadGlyphs()
+	return c.colorGlyphs, c.glyphsErr
+}
+
+// loadGlyphs loads the font chain and the color glyphs and builds the glyph
+// atlas, the first time it is called.
+func (c *Config) loadGlyphs() {
+	c.glyphsOnce.Do(func() {
+		primary := FontGoMono
+		if c.FontPath != "" {
+			primary = Font{Path: c.FontPath}
+		}
+		c.face, c.glyphsErr = newFallbackFace(append([]Font{primary}, c.Fallbacks...), &truetype.Options{
+			Size:    c.FontSize,
+			DPI:     c.DPI,
+			Hinting: font.HintingFull,
+		})
+		if c.glyphsErr != nil {
+			return
+		}
+		c.atlas = text.NewAtlas(c.face, c.GetRuneSets()...)
+		if c.ColorGlyphPath != "" {
+			var err error
+			c.colorGlyphs, err = LoadColorGlyphs(c.ColorGlyphPath)
+			if _, skipped := err.(ColorGlyphErrors); err != nil && !skipped {
+				c.glyphsErr = err
+			}
+		}
+	})
+}
+
 func (c *Config) GetAdjustXY() (float64, float64) {
 	return c.AdjustX, c.AdjustY
 }
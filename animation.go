@@ -0,0 +1,489 @@
+package tview
+
+import (
+	"image/color"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nowakf/ubcell"
+)
+
+// The application only draws the screen after events. Primitives which change
+// over time request additional frames with requestFrame() each time they are
+// drawn, causing the application which drew them to draw the screen again after
+// FrameInterval. The application is found through the screen it passes to
+// the primitives, see appScreen.
+// As soon as they stop requesting frames, e.g. because an animation has
+// finished or because the primitive is not drawn anymore, the application goes
+// back to drawing only after events.
+
+// FrameInterval is the time between two frames drawn by the application while
+// animations are running.
+var FrameInterval = time.Second / 60
+
+// EasingFunc maps the linear progress of an animation (between 0 and 1) to the
+// progress which is shown. It must return 0 for 0 and 1 for 1.
+type EasingFunc func(t float64) float64
+
+// Common easing functions.
+var (
+	EaseLinear EasingFunc = func(t float64) float64 {
+		return t
+	}
+	EaseIn EasingFunc = func(t float64) float64 {
+		return t * t * t
+	}
+	EaseOut EasingFunc = func(t float64) float64 {
+		return 1 - math.Pow(1-t, 3)
+	}
+	EaseInOut EasingFunc = func(t float64) float64 {
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		return 1 - math.Pow(-2*t+2, 3)/2
+	}
+)
+
+// requestFrame causes the application which draws on the given screen to draw
+// it again after FrameInterval. Screens not passed by an application are
+// ignored.
+func requestFrame(screen ubcell.Screen) {
+	if s, ok := screen.(*appScreen); ok {
+		s.app.requestFrame()
+	}
+}
+
+// blockInput causes the application which draws on the given screen to
+// discard key and mouse events until the given time, e.g. while a transition
+// is running. Blocking ends at that time even if the primitive which blocked
+// input is not drawn anymore. Screens not passed by an application are ignored.
+func blockInput(screen ubcell.Screen, until time.Time) {
+	if s, ok := screen.(*appScreen); ok {
+		s.app.blockInput(until)
+	}
+}
+
+// requestFrame causes the application to draw the screen again after
+// FrameInterval.
+func (a *Application) requestFrame() {
+	a.Lock()
+	defer a.Unlock()
+	a.frameRequested = true
+}
+
+// takeFrameRequest returns whether a frame was requested and resets the
+// request.
+func (a *Application) takeFrameRequest() bool {
+	a.Lock()
+	defer a.Unlock()
+	requested := a.frameRequested
+	a.frameRequested = false
+	return requested
+}
+
+// blockInput causes the application to discard key and mouse events until the
+// given time. Releasing a mouse button still reaches the primitive which
+// grabbed the mouse.
+func (a *Application) blockInput(until time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	if until.After(a.inputBlockedUntil) {
+		a.inputBlockedUntil = until
+	}
+}
+
+// inputBlocked returns whether user input is currently discarded.
+func (a *Application) inputBlocked() bool {
+	a.RLock()
+	defer a.RUnlock()
+	return time.Now().Before(a.inputBlockedUntil)
+}
+
+// blendColor returns the color between "from" (alpha 0) and "to" (alpha 1).
+func blendColor(from, to color.RGBA, alpha float64) color.RGBA {
+	blend := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*alpha))
+	}
+	return color.RGBA{
+		R: blend(from.R, to.R),
+		G: blend(from.G, to.G),
+		B: blend(from.B, to.B),
+		A: blend(from.A, to.A),
+	}
+}
+
+// screenCell is the content of one cell of the screen.
+type screenCell struct {
+	ch    rune
+	style ubcell.Style
+}
+
+// saveCells returns the content of the given rectangle of the screen, row by
+// row.
+func saveCells(screen ubcell.Screen, x, y, width, height int) []screenCell {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	cells := make([]screenCell, 0, width*height)
+	for cy := y; cy < y+height; cy++ {
+		for cx := x; cx < x+width; cx++ {
+			ch, style := screen.GetContent(cx, cy)
+			cells = append(cells, screenCell{ch: ch, style: style})
+		}
+	}
+	return cells
+}
+
+// drawClipped draws a primitive, leaving the screen outside of the given
+// rectangle unchanged. This is used to draw primitives which are moved partly
+// out of their container.
+func drawClipped(screen ubcell.Screen, p Primitive, clipX, clipY, clipWidth, clipHeight int) {
+	x, y, width, height := p.GetRect()
+	saved := saveCells(screen, x, y, width, height)
+	p.Draw(screen)
+	for index, cell := range saved {
+		cx, cy := x+index%width, y+index/width
+		if cx < clipX || cx >= clipX+clipWidth || cy < clipY || cy >= clipY+clipHeight {
+			screen.SetContent(cx, cy, cell.ch, cell.style)
+		}
+	}
+}
+
+// drawBlended draws a primitive with the given opacity between 0 (invisible)
+// and 1 (fully visible), blending its colors with the content of the screen
+// below it.
+func drawBlended(screen ubcell.Screen, p Primitive, alpha float64) {
+	if alpha >= 1 {
+		p.Draw(screen)
+		return
+	}
+	x, y, width, height := p.GetRect()
+	saved := saveCells(screen, x, y, width, height)
+	p.Draw(screen)
+	for index, below := range saved {
+		cx, cy := x+index%width, y+index/width
+		ch, style := screen.GetContent(cx, cy)
+		fg, bg := style.Decompose()
+		belowFg, belowBg := below.style.Decompose()
+		if alpha < 0.5 {
+			ch = below.ch
+		}
+		style = ubcell.StyleDefault.
+			Foreground(blendColor(belowFg, fg, alpha)).
+			Background(blendColor(belowBg, bg, alpha))
+		screen.SetContent(cx, cy, ch, style)
+	}
+}
+
+// Animator runs animations which change values over time, e.g. the position
+// of a primitive, a color, or a scroll offset. The animations are advanced with
+// Step(). While animations are running, Step() causes the application which
+// drew the given screen to draw another frame after FrameInterval, so an
+// animator which is stepped each time the screen is drawn runs smoothly without
+// redrawing the screen when nothing changes.
+//
+// The application has an animator which is stepped before the screen is drawn,
+// see Application.GetAnimator(). Primitives which animate themselves, e.g.
+// Pages with transitions, step their own animator when they are drawn.
+//
+// The functions which update values are called from Step(), i.e. from the
+// application's event loop for the application's animator. Animations should
+// be started from the event loop, too (see Application.QueueUpdate()).
+type Animator struct {
+	sync.Mutex
+
+	// The animations which were started and have not finished yet.
+	animations []*Animation
+
+	// The screen passed to the last call to Step(), used to request a frame
+	// when an animation is started.
+	screen ubcell.Screen
+}
+
+// NewAnimator returns a new animator without any animations.
+func NewAnimator() *Animator {
+	return &Animator{}
+}
+
+// Animate starts a new animation which lasts for the given duration. Each time
+// the animator is stepped, the update function is called with the animation's
+// progress, which goes from 0 to 1 as mapped by the easing function (EaseInOut
+// if nil). It is called with 1 exactly once, when the animation has finished.
+// Example:
+//
+//   animator.Animate(time.Second, tview.EaseOut, tview.TweenColor(from, to, func(c color.RGBA) {
+//     box.SetBackgroundColor(c)
+//   }))
+func (a *Animator) Animate(duration time.Duration, easing EasingFunc, update func(progress float64)) *Animation {
+	animation := newAnimation(a, duration, easing, update)
+	animation.start = time.Now()
+	a.add(animation)
+	return animation
+}
+
+// Step advances all running animations to the current time, calling their
+// update functions. It returns whether animations are still running after
+// that, in which case another frame is requested from the application which
+// drew the given screen, i.e. the screen passed to the caller's Draw()
+// function.
+func (a *Animator) Step(screen ubcell.Screen) bool {
+	now := time.Now()
+	a.Lock()
+	a.screen = screen
+	animations := append([]*Animation(nil), a.animations...)
+	a.Unlock()
+	for _, animation := range animations {
+		animation.step(now)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	running := a.animations[:0]
+	for _, animation := range a.animations {
+		if !animation.finished && !animation.cancelled {
+			running = append(running, animation)
+		}
+	}
+	for index := len(running); index < len(a.animations); index++ {
+		a.animations[index] = nil
+	}
+	a.animations = running
+	if len(running) > 0 {
+		requestFrame(a.screen)
+		return true
+	}
+	return false
+}
+
+// IsAnimating returns whether any animations are running.
+func (a *Animator) IsAnimating() bool {
+	a.Lock()
+	defer a.Unlock()
+	return len(a.animations) > 0
+}
+
+// CancelAll cancels all running animations.
+func (a *Animator) CancelAll() *Animator {
+	a.Lock()
+	animations := a.animations
+	a.animations = nil
+	a.Unlock()
+	for _, animation := range animations {
+		animation.Cancel()
+	}
+	return a
+}
+
+// add adds an animation to the running animations and requests a frame so the
+// animation gets going, provided the animator was stepped before.
+func (a *Animator) add(animation *Animation) {
+	a.Lock()
+	a.animations = append(a.animations, animation)
+	screen := a.screen
+	a.Unlock()
+	requestFrame(screen)
+}
+
+// Animation is a value change over time run by an Animator, see
+// Animator.Animate().
+type Animation struct {
+	// The animator running this animation.
+	animator *Animator
+
+	// The duration and the easing function of the animation.
+	duration time.Duration
+	easing   EasingFunc
+
+	// The function which receives the progress.
+	update func(progress float64)
+
+	// The time when the animation was started and the delay after which its
+	// values start to change.
+	start time.Time
+	delay time.Duration
+
+	// The animations which run before and after this one, if chained with
+	// Then().
+	previous, next *Animation
+
+	// Whether the animation has finished or was cancelled.
+	finished, cancelled bool
+
+	// An optional function which is called when the animation has finished.
+	done func()
+}
+
+// newAnimation returns a new animation which is not started yet.
+func newAnimation(animator *Animator, duration time.Duration, easing EasingFunc, update func(progress float64)) *Animation {
+	if easing == nil {
+		easing = EaseInOut
+	}
+	return &Animation{
+		animator: animator,
+		duration: duration,
+		easing:   easing,
+		update:   update,
+	}
+}
+
+// SetDelay sets the time to wait before the animation's values start to
+// change. For an animation chained with Then(), the delay starts when the
+// previous animation has finished.
+func (a *Animation) SetDelay(delay time.Duration) *Animation {
+	a.delay = delay
+	return a
+}
+
+// SetDoneFunc sets a handler which is called when the animation has finished.
+// It is not called when the animation is cancelled.
+func (a *Animation) SetDoneFunc(handler func()) *Animation {
+	a.done = handler
+	return a
+}
+
+// Then chains a new animation to this one which starts when this one has
+// finished, and returns it. See Animator.Animate() for the arguments. Example:
+//
+//   animator.Animate(time.Second, nil, tview.TweenRect(box, 10, 0, 20, 5)).
+//     Then(time.Second, nil, tview.TweenRect(box, 10, 10, 20, 5))
+func (a *Animation) Then(duration time.Duration, easing EasingFunc, update func(progress float64)) *Animation {
+	next := newAnimation(a.animator, duration, easing, update)
+	next.previous = a
+	a.next = next
+	return next
+}
+
+// Cancel stops the animation and the animations chained to it with Then(),
+// both before and after it, leaving the values where they are.
+func (a *Animation) Cancel() {
+	for first := a; first != nil; first = first.previous {
+		first.cancelled = true
+	}
+	for next := a.next; next != nil; next = next.next {
+		next.cancelled = true
+	}
+}
+
+// IsRunning returns whether the animation has neither finished nor been
+// cancelled. An animation chained with Then() counts as running while it waits
+// for its predecessors.
+func (a *Animation) IsRunning() bool {
+	return !a.finished && !a.cancelled
+}
+
+// step advances the animation to the given time.
+func (a *Animation) step(now time.Time) {
+	if a.finished || a.cancelled {
+		return
+	}
+	begin := a.start.Add(a.delay)
+	if now.Before(begin) {
+		return
+	}
+	progress := 1.0
+	if a.duration > 0 {
+		progress = float64(now.Sub(begin)) / float64(a.duration)
+	}
+	if progress < 1 {
+		if a.update != nil {
+			a.update(a.easing(progress))
+		}
+		return
+	}
+
+	// The animation has finished.
+	a.finished = true
+	if a.update != nil {
+		a.update(1)
+	}
+	if a.done != nil {
+		a.done()
+	}
+	if next := a.next; next != nil && !next.cancelled {
+		next.start = begin.Add(a.duration)
+		a.animator.add(next)
+		next.step(now)
+	}
+}
+
+// TweenInt returns an update function for Animator.Animate() which calls "set"
+// with the values between "from" and "to".
+func TweenInt(from, to int, set func(value int)) func(progress float64) {
+	return func(progress float64) {
+		set(from + int(math.Round(float64(to-from)*progress)))
+	}
+}
+
+// TweenFloat returns an update function for Animator.Animate() which calls
+// "set" with the values between "from" and "to".
+func TweenFloat(from, to float64, set func(value float64)) func(progress float64) {
+	return func(progress float64) {
+		set(from + (to-from)*progress)
+	}
+}
+
+// TweenColor returns an update function for Animator.Animate() which calls
+// "set" with the colors between "from" and "to".
+func TweenColor(from, to color.RGBA, set func(color color.RGBA)) func(progress float64) {
+	return func(progress float64) {
+		set(blendColor(from, to, progress))
+	}
+}
+
+// TweenRect returns an update function for Animator.Animate() which moves and
+// resizes a primitive from the rectangle it has when the animation starts to
+// the given one.
+func TweenRect(p Primitive, x, y, width, height int) func(progress float64) {
+	var fromX, fromY, fromWidth, fromHeight int
+	started := false
+	return func(progress float64) {
+		if !started {
+			fromX, fromY, fromWidth, fromHeight = p.GetRect()
+			started = true
+		}
+		tween := func(from, to int) int {
+			return from + int(math.Round(float64(to-from)*progress))
+		}
+		p.SetRect(tween(fromX, x), tween(fromY, y), tween(fromWidth, width), tween(fromHeight, height))
+	}
+}
+
+// smoothScroll animates a primitive's scroll offset, e.g. TextView's line
+// offset, towards the offset it is supposed to have.
+type smoothScroll struct {
+	animator *Animator
+
+	// The duration of a scroll animation. 0 turns smooth scrolling off.
+	duration time.Duration
+
+	// The offset which is currently shown and the one it moves towards.
+	shown, target int
+
+	// The running scroll animation, if any.
+	animation *Animation
+}
+
+// offset returns the offset to be drawn on the given screen when the primitive
+// is supposed to be scrolled to the given offset, starting a new animation if
+// that offset has changed.
+func (s *smoothScroll) offset(screen ubcell.Screen, target int) int {
+	if s.duration <= 0 || s.animator == nil {
+		s.shown, s.target = target, target
+		if s.duration > 0 {
+			s.animator = NewAnimator() // The first offset is not animated.
+		}
+		return target
+	}
+	if target != s.target {
+		if s.animation != nil {
+			s.animation.Cancel()
+		}
+		s.target = target
+		s.animation = s.animator.Animate(s.duration, EaseOut, TweenInt(s.shown, target, func(value int) {
+			s.shown = value
+		}))
+	}
+	s.animator.Step(screen)
+	return s.shown
+}
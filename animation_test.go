@@ -0,0 +1,122 @@
+package tview
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// linear is an easing function which does not change the progress.
+func linear(t float64) float64 {
+	return t
+}
+
+// recorder collects the progress values passed to an update function and the
+// calls of a done handler.
+type recorder struct {
+	progress []float64
+	done     int
+}
+
+func (r *recorder) update(progress float64) {
+	r.progress = append(r.progress, progress)
+}
+
+func (r *recorder) finish() {
+	r.done++
+}
+
+// startAt returns an animation which starts at the given time.
+func startAt(start time.Time, animator *Animator, duration time.Duration, r *recorder) *Animation {
+	animation := animator.Animate(duration, linear, r.update).SetDoneFunc(r.finish)
+	animation.start = start
+	return animation
+}
+
+func TestAnimationStep(t *testing.T) {
+	start := time.Now()
+	var r recorder
+	animation := startAt(start, NewAnimator(), time.Second, &r)
+	animation.SetDelay(time.Second)
+
+	for _, elapsed := range []time.Duration{500, 1000, 1500, 2000, 2500, 3000} {
+		animation.step(start.Add(elapsed * time.Millisecond))
+	}
+	if expected := []float64{0, 0.5, 1}; !reflect.DeepEqual(r.progress, expected) {
+		t.Errorf("progress %v, expected %v", r.progress, expected)
+	}
+	if r.done != 1 || animation.IsRunning() {
+		t.Errorf("done called %d times, running: %t", r.done, animation.IsRunning())
+	}
+}
+
+func TestAnimationThen(t *testing.T) {
+	start := time.Now()
+	animator := NewAnimator()
+	var first, second, third recorder
+	animation := startAt(start, animator, time.Second, &first)
+	next := animation.Then(2*time.Second, linear, second.update).SetDoneFunc(second.finish).SetDelay(time.Second)
+	last := next.Then(time.Second, linear, third.update).SetDoneFunc(third.finish)
+	if len(animator.animations) != 1 {
+		t.Fatalf("%d animations running before the first one finished", len(animator.animations))
+	}
+
+	// The chained animation starts when the first one has finished, after its
+	// delay, even if the first one is stepped late.
+	animation.step(start.Add(2500 * time.Millisecond))
+	if len(animator.animations) != 2 || animator.animations[1] != next {
+		t.Fatal("chained animation was not added to the animator")
+	}
+	next.step(start.Add(3 * time.Second))
+	next.step(start.Add(4 * time.Second))
+	last.step(start.Add(4500 * time.Millisecond))
+	if expected := []float64{1}; !reflect.DeepEqual(first.progress, expected) {
+		t.Errorf("first progress %v, expected %v", first.progress, expected)
+	}
+	if expected := []float64{0.25, 0.5, 1}; !reflect.DeepEqual(second.progress, expected) {
+		t.Errorf("second progress %v, expected %v", second.progress, expected)
+	}
+	if expected := []float64{0, 0.5}; !reflect.DeepEqual(third.progress, expected) {
+		t.Errorf("third progress %v, expected %v", third.progress, expected)
+	}
+	if first.done != 1 || second.done != 1 || third.done != 0 {
+		t.Errorf("done called %d, %d, and %d times", first.done, second.done, third.done)
+	}
+}
+
+func TestAnimationCancel(t *testing.T) {
+	start := time.Now()
+	animator := NewAnimator()
+	var first, second, third recorder
+	animation := startAt(start, animator, time.Second, &first)
+	next := animation.Then(time.Second, linear, second.update).SetDoneFunc(second.finish)
+	last := next.Then(time.Second, linear, third.update).SetDoneFunc(third.finish)
+
+	// Cancelling the middle animation cancels its predecessor and successor.
+	animation.step(start.Add(500 * time.Millisecond))
+	next.Cancel()
+	for _, a := range []*Animation{animation, next, last} {
+		if a.IsRunning() {
+			t.Error("animation still running after cancelling the chain")
+		}
+	}
+	animation.step(start.Add(2 * time.Second))
+	if expected := []float64{0.5}; !reflect.DeepEqual(first.progress, expected) {
+		t.Errorf("progress %v after cancelling, expected %v", first.progress, expected)
+	}
+	if len(animator.animations) != 1 || second.progress != nil || third.progress != nil {
+		t.Error("chained animation started after cancelling")
+	}
+	if first.done != 0 || second.done != 0 || third.done != 0 {
+		t.Error("done called after cancelling")
+	}
+
+	// Cancelling the first animation cancels its successors.
+	first = recorder{}
+	animation = startAt(start, animator, time.Second, &first)
+	next = animation.Then(time.Second, linear, second.update)
+	animation.Cancel()
+	if next.IsRunning() {
+		t.Error("successor still running after cancelling the first animation")
+	}
+}
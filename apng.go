@@ -0,0 +1,286 @@
+package tview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// The PNG file signature.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// APNG disposal and blend operations, as defined in the fcTL chunk.
+const (
+	apngDisposeNone       = 0
+	apngDisposeBackground = 1
+	apngDisposePrevious   = 2
+	apngBlendSource       = 0
+	apngBlendOver         = 1
+)
+
+// APNGSource is a FrameSource which plays an animated PNG, honoring its frame
+// delays, disposal and blend operations, and number of plays. PNG files without
+// animation control are played as a single frame.
+type APNGSource struct {
+	frames    []image.Image
+	delays    []time.Duration
+	loopCount int
+}
+
+// apngFrame is a frame as described by an fcTL chunk, along with its
+// compressed image data.
+type apngFrame struct {
+	width, height  int
+	x, y           int
+	delay          time.Duration
+	dispose, blend byte
+	data           []byte
+}
+
+// DecodeAPNG reads an animated PNG from the given reader and composes its
+// frames.
+func DecodeAPNG(reader io.Reader) (*APNGSource, error) {
+	signature := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(reader, signature); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(signature, pngSignature) {
+		return nil, errors.New("not a PNG file")
+	}
+
+	var (
+		header    []byte   // The IHDR chunk's data.
+		ancillary [][]byte // Chunks before the image data which apply to all frames, e.g. PLTE.
+		animated  bool     // Whether an acTL chunk was found.
+		plays     int      // The number of plays from the acTL chunk.
+		frames    []*apngFrame
+		idat      bool   // Whether the IDAT chunks belong to the animation.
+		data      []byte // The default image's data (IDAT chunks).
+		sequence  uint32 // The expected sequence number of the next fcTL or fdAT chunk.
+	)
+
+	// checkSequence checks the sequence number at the start of an fcTL or fdAT
+	// chunk. Chunks out of order would mix up frames.
+	checkSequence := func(chunk []byte) error {
+		if binary.BigEndian.Uint32(chunk) != sequence {
+			return errors.New("APNG chunks out of order")
+		}
+		sequence++
+		return nil
+	}
+chunks:
+	for {
+		var prefix [8]byte
+		if _, err := io.ReadFull(reader, prefix[:]); err != nil {
+			if err == io.EOF {
+				break // Be lenient with missing IEND chunks.
+			}
+			return nil, err
+		}
+		length := binary.BigEndian.Uint32(prefix[:4])
+		kind := string(prefix[4:])
+		if length > 1<<31-1 {
+			return nil, errors.New("invalid PNG chunk length")
+		}
+		// Read the chunk and its CRC without allocating the whole length up
+		// front, which may be bogus in a truncated file.
+		chunk, err := ioutil.ReadAll(io.LimitReader(reader, int64(length)+4))
+		if err != nil {
+			return nil, err
+		}
+		if len(chunk) < int(length)+4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		chunk = chunk[:length]
+		if header == nil && kind != "IHDR" {
+			return nil, errors.New("missing IHDR chunk")
+		}
+
+		switch kind {
+		case "IHDR":
+			if len(chunk) != 13 {
+				return nil, errors.New("invalid IHDR chunk")
+			}
+			header = chunk
+		case "acTL":
+			if len(chunk) != 8 {
+				return nil, errors.New("invalid acTL chunk")
+			}
+			animated = true
+			plays = int(binary.BigEndian.Uint32(chunk[4:]))
+		case "fcTL":
+			if len(chunk) != 26 {
+				return nil, errors.New("invalid fcTL chunk")
+			}
+			if err := checkSequence(chunk); err != nil {
+				return nil, err
+			}
+			numerator := time.Duration(binary.BigEndian.Uint16(chunk[20:]))
+			denominator := time.Duration(binary.BigEndian.Uint16(chunk[22:]))
+			if denominator == 0 {
+				denominator = 100
+			}
+			frames = append(frames, &apngFrame{
+				width:   int(binary.BigEndian.Uint32(chunk[4:])),
+				height:  int(binary.BigEndian.Uint32(chunk[8:])),
+				x:       int(binary.BigEndian.Uint32(chunk[12:])),
+				y:       int(binary.BigEndian.Uint32(chunk[16:])),
+				delay:   numerator * time.Second / denominator,
+				dispose: chunk[24],
+				blend:   chunk[25],
+			})
+			if data == nil {
+				// An fcTL chunk before the IDAT chunks makes the default image
+				// the first frame.
+				idat = true
+			}
+		case "IDAT":
+			data = append(data, chunk...)
+			if idat && len(frames) > 0 {
+				frames[0].data = append(frames[0].data, chunk...)
+			}
+		case "fdAT":
+			if len(chunk) < 4 {
+				return nil, errors.New("invalid fdAT chunk")
+			}
+			if len(frames) == 0 {
+				return nil, errors.New("fdAT chunk without fcTL chunk")
+			}
+			if err := checkSequence(chunk); err != nil {
+				return nil, err
+			}
+			frame := frames[len(frames)-1]
+			frame.data = append(frame.data, chunk[4:]...)
+		case "IEND":
+			break chunks
+		default:
+			if data == nil {
+				ancillary = append(ancillary, encodePNGChunk(kind, chunk))
+			}
+		}
+	}
+	if header == nil {
+		return nil, errors.New("missing IHDR chunk")
+	}
+	width := int(binary.BigEndian.Uint32(header[0:]))
+	height := int(binary.BigEndian.Uint32(header[4:]))
+
+	// decode decodes a frame's image data as a standalone PNG.
+	decode := func(frame *apngFrame) (image.Image, error) {
+		var buffer bytes.Buffer
+		buffer.Write(pngSignature)
+		frameHeader := append([]byte(nil), header...)
+		binary.BigEndian.PutUint32(frameHeader[0:], uint32(frame.width))
+		binary.BigEndian.PutUint32(frameHeader[4:], uint32(frame.height))
+		buffer.Write(encodePNGChunk("IHDR", frameHeader))
+		for _, chunk := range ancillary {
+			buffer.Write(chunk)
+		}
+		buffer.Write(encodePNGChunk("IDAT", frame.data))
+		buffer.Write(encodePNGChunk("IEND", nil))
+		return png.Decode(&buffer)
+	}
+
+	// Without animation control, this is a regular PNG file.
+	if !animated || len(frames) == 0 {
+		img, err := decode(&apngFrame{width: width, height: height, data: data})
+		if err != nil {
+			return nil, err
+		}
+		return &APNGSource{
+			frames:    []image.Image{img},
+			delays:    []time.Duration{0},
+			loopCount: -1,
+		}, nil
+	}
+
+	s := &APNGSource{
+		frames: make([]image.Image, 0, len(frames)),
+		delays: make([]time.Duration, 0, len(frames)),
+	}
+	switch plays {
+	case 0:
+		s.loopCount = 0 // Forever.
+	case 1:
+		s.loopCount = -1 // Once.
+	default:
+		s.loopCount = plays - 1
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	for index, frame := range frames {
+		if len(frame.data) == 0 {
+			continue // The default image is not part of the animation.
+		}
+		img, err := decode(frame)
+		if err != nil {
+			return nil, err
+		}
+		region := image.Rect(frame.x, frame.y, frame.x+frame.width, frame.y+frame.height)
+
+		dispose := frame.dispose
+		if dispose == apngDisposePrevious && index == 0 {
+			dispose = apngDisposeBackground
+		}
+		var previous *image.RGBA
+		if dispose == apngDisposePrevious {
+			previous = copyRGBA(canvas)
+		}
+
+		op := draw.Over
+		if frame.blend == apngBlendSource {
+			op = draw.Src
+		}
+		draw.Draw(canvas, region, img, img.Bounds().Min, op)
+		s.frames = append(s.frames, copyRGBA(canvas))
+		s.delays = append(s.delays, frame.delay)
+
+		switch dispose {
+		case apngDisposeBackground:
+			draw.Draw(canvas, region, image.Transparent, image.ZP, draw.Src)
+		case apngDisposePrevious:
+			canvas = previous
+		}
+	}
+	if len(s.frames) == 0 {
+		return nil, errors.New("APNG file contains no frames")
+	}
+
+	return s, nil
+}
+
+// FrameCount returns the number of frames of the APNG.
+func (s *APNGSource) FrameCount() int {
+	return len(s.frames)
+}
+
+// Frame returns the composed frame with the given index.
+func (s *APNGSource) Frame(index int) (image.Image, time.Duration, error) {
+	if index < 0 || index >= len(s.frames) {
+		return nil, 0, io.EOF
+	}
+	return s.frames[index], s.delays[index], nil
+}
+
+// LoopCount returns the loop count derived from the APNG's number of plays.
+func (s *APNGSource) LoopCount() int {
+	return s.loopCount
+}
+
+// encodePNGChunk returns a PNG chunk of the given type, including its length
+// and CRC.
+func encodePNGChunk(kind string, data []byte) []byte {
+	chunk := make([]byte, 8+len(data)+4)
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], kind)
+	copy(chunk[8:], data)
+	binary.BigEndian.PutUint32(chunk[8+len(data):], crc32.ChecksumIEEE(chunk[4:8+len(data)]))
+	return chunk
+}
@@ -0,0 +1,190 @@
+package tview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+)
+
+// testPNGChunks returns the IHDR data and the IDAT data of a PNG file with a
+// single pixel of the given color.
+func testPNGChunks(t *testing.T, col color.NRGBA) (header, data []byte) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	img.SetNRGBA(0, 0, col)
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatal(err)
+	}
+	file := buffer.Bytes()[len(pngSignature):]
+	for len(file) >= 12 {
+		length := binary.BigEndian.Uint32(file)
+		kind, chunk := string(file[4:8]), file[8:8+length]
+		switch kind {
+		case "IHDR":
+			header = chunk
+		case "IDAT":
+			data = append(data, chunk...)
+		}
+		file = file[12+length:]
+	}
+	return
+}
+
+// testAPNG builds an APNG file from the given chunks, prepending the PNG
+// signature.
+func testAPNG(chunks ...[]byte) []byte {
+	file := append([]byte(nil), pngSignature...)
+	for _, chunk := range chunks {
+		file = append(file, chunk...)
+	}
+	return file
+}
+
+// acTL returns an acTL chunk.
+func acTL(frames, plays uint32) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint32(data, frames)
+	binary.BigEndian.PutUint32(data[4:], plays)
+	return encodePNGChunk("acTL", data)
+}
+
+// fcTL returns an fcTL chunk for a 1x1 frame lasting 1/10 second.
+func fcTL(sequence uint32) []byte {
+	data := make([]byte, 26)
+	binary.BigEndian.PutUint32(data, sequence)
+	binary.BigEndian.PutUint32(data[4:], 1)
+	binary.BigEndian.PutUint32(data[8:], 1)
+	binary.BigEndian.PutUint16(data[20:], 1)
+	binary.BigEndian.PutUint16(data[22:], 10)
+	return encodePNGChunk("fcTL", data)
+}
+
+// fdAT returns an fdAT chunk with the given image data.
+func fdAT(sequence uint32, data []byte) []byte {
+	chunk := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint32(chunk, sequence)
+	return encodePNGChunk("fdAT", append(chunk, data...))
+}
+
+func TestDecodeAPNG(t *testing.T) {
+	header, red := testPNGChunks(t, color.NRGBA{0xff, 0, 0, 0xff})
+	_, blue := testPNGChunks(t, color.NRGBA{0, 0, 0xff, 0xff})
+	file := testAPNG(
+		encodePNGChunk("IHDR", header),
+		acTL(2, 3),
+		fcTL(0),
+		encodePNGChunk("IDAT", red),
+		fcTL(1),
+		fdAT(2, blue),
+		encodePNGChunk("IEND", nil),
+	)
+	source, err := DecodeAPNG(bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.FrameCount() != 2 || source.LoopCount() != 2 {
+		t.Fatalf("got %d frames and %d loops", source.FrameCount(), source.LoopCount())
+	}
+	for index, expected := range []color.RGBA{{0xff, 0, 0, 0xff}, {0, 0, 0xff, 0xff}} {
+		frame, delay, err := source.Frame(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if col := color.RGBAModel.Convert(frame.At(0, 0)); col != expected {
+			t.Errorf("frame %d has color %v, expected %v", index, col, expected)
+		}
+		if delay.Milliseconds() != 100 {
+			t.Errorf("frame %d has delay %s", index, delay)
+		}
+	}
+	if _, _, err := source.Frame(2); err != io.EOF {
+		t.Errorf("got %v for a frame beyond the last one", err)
+	}
+}
+
+func TestDecodeAPNGMalformed(t *testing.T) {
+	header, red := testPNGChunks(t, color.NRGBA{0xff, 0, 0, 0xff})
+	_, blue := testPNGChunks(t, color.NRGBA{0, 0, 0xff, 0xff})
+	valid := testAPNG(
+		encodePNGChunk("IHDR", header),
+		acTL(2, 0),
+		fcTL(0),
+		encodePNGChunk("IDAT", red),
+		fcTL(1),
+		fdAT(2, blue),
+		encodePNGChunk("IEND", nil),
+	)
+	if _, err := DecodeAPNG(bytes.NewReader(valid)); err != nil {
+		t.Fatalf("the valid file failed: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{"empty", nil},
+		{"truncated signature", pngSignature[:4]},
+		{"signature only", pngSignature},
+		{"truncated chunk length", valid[:len(pngSignature)+2]},
+		{"truncated IHDR", valid[:len(pngSignature)+12]},
+		{"truncated fdAT", valid[:len(valid)-len(encodePNGChunk("IEND", nil))-3]},
+		{"missing image data", testAPNG(encodePNGChunk("IHDR", header))},
+		{"bogus chunk length", testAPNG(
+			encodePNGChunk("IHDR", header),
+			[]byte{0x7f, 0xff, 0xff, 0xff, 'I', 'D', 'A', 'T', 0, 0},
+		)},
+		{"IHDR not first", testAPNG(
+			acTL(1, 0),
+			encodePNGChunk("IHDR", header),
+			fcTL(0),
+			encodePNGChunk("IDAT", red),
+		)},
+		{"fdAT before fcTL", testAPNG(
+			encodePNGChunk("IHDR", header),
+			acTL(1, 0),
+			encodePNGChunk("IDAT", red),
+			fdAT(0, blue),
+			fcTL(1),
+		)},
+		{"swapped sequence numbers", testAPNG(
+			encodePNGChunk("IHDR", header),
+			acTL(2, 0),
+			fcTL(0),
+			encodePNGChunk("IDAT", red),
+			fcTL(2),
+			fdAT(1, blue),
+		)},
+		{"skipped sequence number", testAPNG(
+			encodePNGChunk("IHDR", header),
+			acTL(2, 0),
+			fcTL(0),
+			encodePNGChunk("IDAT", red),
+			fcTL(2),
+			fdAT(3, blue),
+		)},
+		{"short fcTL", testAPNG(
+			encodePNGChunk("IHDR", header),
+			acTL(1, 0),
+			encodePNGChunk("fcTL", make([]byte, 25)),
+			encodePNGChunk("IDAT", red),
+		)},
+		{"short fdAT", testAPNG(
+			encodePNGChunk("IHDR", header),
+			acTL(2, 0),
+			fcTL(0),
+			encodePNGChunk("IDAT", red),
+			fcTL(1),
+			encodePNGChunk("fdAT", []byte{0, 0, 0}),
+		)},
+	}
+	for _, test := range tests {
+		if source, err := DecodeAPNG(bytes.NewReader(test.file)); err == nil {
+			t.Errorf("%s: expected an error, got %d frames", test.name, source.FrameCount())
+		}
+	}
+}
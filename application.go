@@ -2,6 +2,7 @@ package tview
 
 import (
 	"sync"
+	"time"
 
 	"github.com/nowakf/pixel/pixelgl"
 	"github.com/nowakf/ubcell"
@@ -45,11 +46,94 @@ type Application struct {
 
 	// If this value is true, the application has entered suspended mode.
 	suspended bool
+
+	// Functions queued with QueueUpdate(), to be executed in the event loop.
+	updates chan func()
+
+	// The animator which is stepped before the screen is drawn.
+	animator *Animator
+
+	// Whether a primitive requested another frame since the last frame was
+	// scheduled, see requestFrame().
+	frameRequested bool
+
+	// User input is discarded until this time, see blockInput().
+	inputBlockedUntil time.Time
+
+	// The dialogs shown over the root primitive (see ShowDialog()), from the
+	// bottom to the top.
+	dialogs []appDialog
+
+	// The primitive which receives all cursor events while the user drags
+	// something with the mouse, see grabMouse().
+	mouseGrab Primitive
+
+	// The primitives drawn above the dialogs, e.g. open menus, from the
+	// bottom to the top. See showOverlay().
+	overlays []Primitive
+
+	// The notifications drawn above everything else.
+	notifications *Notifications
+
+	// The boxes below the mouse cursor, from the outermost to the innermost
+	// one, and the tooltip pending or shown for them.
+	hovered []*Box
+	tooltip appTooltip
+
+	// The boxes which received the cursor event being dispatched, from the
+	// outermost to the innermost one. Only collected while hoverTracking is
+	// true, see trackHover().
+	hoverTracking bool
+	hoverTrail    []*Box
+
+	// What is needed to draw the glyphs the screen cannot draw itself.
+	glyphs appGlyphs
+
+	// Closed when Run() returns.
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// appScreen is the screen passed to the primitives drawn by an application. It
+// lets them reach the application, e.g. to request another frame.
+type appScreen struct {
+	ubcell.Screen
+	app *Application
+}
+
+// screenApp returns the application which draws on the given screen or nil if
+// the screen was not passed by an application.
+func screenApp(screen ubcell.Screen) *Application {
+	if s, ok := screen.(*appScreen); ok {
+		return s.app
+	}
+	return nil
+}
+
+// appDialog is a dialog shown over the root primitive.
+type appDialog struct {
+	// The dialog primitive.
+	dialog Primitive
+
+	// The primitive which had focus before the dialog was shown.
+	focus Primitive
+
+	// Closed when the dialog is closed.
+	closed chan struct{}
 }
 
+// The size of the event and update queues.
+const queueSize = 100
+
 // NewApplication creates and returns a new application.
 func NewApplication(cfg *Config) (*Application, error) {
-	return &Application{cfg: cfg}, nil
+	return &Application{
+		cfg:           cfg,
+		updates:       make(chan func(), queueSize),
+		animator:      NewAnimator(),
+		notifications: NewNotifications(),
+		stopped:       make(chan struct{}),
+	}, nil
 }
 
 func (a *Application) Screen() ubcell.Screen {
@@ -81,10 +165,20 @@ func (a *Application) GetKeyCapture() func(event pixelgl.Event) pixelgl.Event {
 // Run starts the application and thus the event loop. This function returns
 // when Stop() was called.
 func (a *Application) Run() error {
+	defer a.stopOnce.Do(func() {
+		close(a.stopped)
+	})
 
 	var err error
 	a.Lock()
 
+	// Load the fonts and build the glyph atlas now to report errors before the
+	// window opens. The atlas is used by drawGlyphs().
+	if _, err = a.cfg.GetAtlas(); err != nil {
+		a.Unlock()
+		return err
+	}
+
 	// Make a screen.
 	a.screen, err = ubcell.NewScreen(a.cfg)
 
@@ -115,6 +209,15 @@ func (a *Application) Run() error {
 	//post event
 
 	// Start event loop.
+	events := make(chan pixelgl.Event, queueSize)
+	done := make(chan struct{})
+	defer close(done)
+	go a.pollEvents(events, done)
+	var (
+		frame    <-chan time.Time
+		expire   <-chan time.Time // When the next notification expires.
+		expireAt time.Time
+	)
 	for {
 		a.Lock()
 		screen := a.screen
@@ -126,8 +229,35 @@ func (a *Application) Run() error {
 			break
 		}
 
-		// Wait for next event - blocking...
-		event := a.screen.PollEvent()
+		// Draw another frame later if an animation is running.
+		if frame == nil && a.takeFrameRequest() {
+			frame = time.After(FrameInterval)
+		}
+
+		// Dismiss notifications when they expire.
+		if next := a.notifications.nextExpiry(); !next.IsZero() && (expire == nil || next.Before(expireAt)) {
+			expireAt = next
+			expire = time.After(time.Until(next))
+		}
+
+		// Wait for next event or update - blocking...
+		var event pixelgl.Event
+		select {
+		case event = <-events:
+		case update := <-a.updates:
+			update()
+			continue
+		case <-frame:
+			frame = nil
+			a.Draw()
+			continue
+		case <-expire:
+			expire = nil
+			if a.notifications.expire() {
+				a.Draw()
+			}
+			continue
+		}
 		if event == nil {
 			a.Lock()
 			if a.suspended {
@@ -145,16 +275,61 @@ func (a *Application) Run() error {
 		switch event := event.(type) {
 
 		case *pixelgl.CursorEvent, *pixelgl.ScrollEvent:
+			if a.inputBlocked() {
+				// Releasing the button still ends a drag which started
+				// before input was blocked.
+				if ev, ok := event.(*pixelgl.CursorEvent); !ok || ev.Act != pixelgl.RELEASE || a.grabbedMouse() == nil {
+					break
+				}
+			}
+
+			// Clicks on notifications dismiss them.
+			if ev, ok := event.(*pixelgl.CursorEvent); ok && a.grabbedMouse() == nil && a.notifications.handleClick(ev) {
+				a.Draw()
+				break
+			}
+
 			a.RLock()
 			p := a.root
+			if len(a.dialogs) > 0 {
+				// Dialogs are modal: only the top one receives mouse events.
+				p = a.dialogs[len(a.dialogs)-1].dialog
+			}
 			a.RUnlock()
-			if handler := p.MouseHandler(); p != nil {
-				handler(event, func(p Primitive) {
-					a.SetFocus(p)
-				})
+
+			// Open menus receive all mouse events.
+			if overlay := a.topOverlay(); overlay != nil && a.grabbedMouse() == nil {
+				p = overlay
 			}
 
-		//do nothing for now
+			// While the user drags something, cursor events go to the dragging
+			// primitive.
+			if grabbed := a.grabbedMouse(); grabbed != nil {
+				if _, ok := event.(*pixelgl.CursorEvent); ok {
+					p = grabbed
+				}
+			}
+
+			if p != nil {
+				if handler := p.MouseHandler(); handler != nil {
+					// Find the primitives below the cursor, unless it's grabbed.
+					ev, hover := event.(*pixelgl.CursorEvent)
+					hover = hover && a.grabbedMouse() == nil
+					if hover {
+						a.startHover()
+					}
+
+					handler(event, func(p Primitive) {
+						a.SetFocus(p)
+					})
+
+					if hover {
+						a.updateHover(a.finishHover(), ev)
+					}
+
+					a.Draw()
+				}
+			}
 
 		case *pixelgl.KeyEv, *pixelgl.ChaEv:
 
@@ -171,6 +346,30 @@ func (a *Application) Run() error {
 				break
 			}
 
+			if a.inputBlocked() {
+				break
+			}
+
+			// Typing hides the tooltip.
+			a.hideTooltip()
+
+			// The dismiss key removes the newest notification.
+			if ev, ok := event.(*pixelgl.KeyEv); ok && a.notifications.handleKey(ev) {
+				a.Draw()
+				break
+			}
+
+			// Open menus receive all key events.
+			if overlay := a.topOverlay(); overlay != nil {
+				if handler := overlay.KeyHandler(); handler != nil {
+					handler(event, func(p Primitive) {
+						a.SetFocus(p)
+					})
+					a.Draw()
+				}
+				break
+			}
+
 			// Intercept keys.
 			//rename!
 			if a.keyCapture != nil {
@@ -204,6 +403,95 @@ func (a *Application) Run() error {
 	return nil
 }
 
+// pollEvents sends the screen's events to the given channel until the screen
+// is finalized or until "done" is closed.
+func (a *Application) pollEvents(events chan<- pixelgl.Event, done <-chan struct{}) {
+	for {
+		a.RLock()
+		screen := a.screen
+		a.RUnlock()
+		if screen == nil {
+			return
+		}
+		event := screen.PollEvent()
+		select {
+		case events <- event:
+		case <-done:
+			return
+		}
+	}
+}
+
+// GetAnimator returns the application's animator, which is stepped each time
+// the screen is drawn. It can be used to animate any primitive, e.g. to move a
+// box into place:
+//
+//   app.GetAnimator().Animate(time.Second/2, tview.EaseOut, tview.TweenRect(box, 10, 5, 40, 10))
+//
+// See Animator for details.
+func (a *Application) GetAnimator() *Animator {
+	return a.animator
+}
+
+// QueueUpdate queues a function to be executed as part of the event loop. This
+// is how primitives should be modified from goroutines other than the one
+// running the event loop: key and mouse handlers, which are called from the
+// event loop, may modify primitives directly, other goroutines must use this
+// function. It may not be called from the event loop itself if the queue is
+// full, as it blocks until the function could be queued.
+//
+// The screen is not redrawn after the function was executed, see
+// QueueUpdateDraw().
+func (a *Application) QueueUpdate(f func()) *Application {
+	a.updates <- f
+	return a
+}
+
+// queueUnlessStopped queues a function like QueueUpdate() unless the
+// application stops first, in which case the function is dropped and false is
+// returned. Functions queued after Run() has returned are never executed, so
+// goroutines which wait for the application use this instead of QueueUpdate().
+func (a *Application) queueUnlessStopped(f func()) bool {
+	select {
+	case a.updates <- f:
+		return true
+	case <-a.stopped:
+		return false
+	}
+}
+
+// QueueUpdateDraw works like QueueUpdate() except it refreshes the screen
+// immediately after executing f.
+func (a *Application) QueueUpdateDraw(f func()) *Application {
+	a.QueueUpdate(func() {
+		f()
+		a.Draw()
+	})
+	return a
+}
+
+// GetNotifications returns the application's notifications, e.g. to change
+// the corner in which they are shown.
+func (a *Application) GetNotifications() *Notifications {
+	return a.notifications
+}
+
+// Notify shows a notification with the given severity (e.g. NotifyInfo) and
+// text without changing the focus. See Notifications for details. Unlike most
+// functions, it may be called from any goroutine, including the event loop.
+func (a *Application) Notify(severity int, text string) *Toast {
+	toast := a.notifications.Add(severity, text)
+
+	// Redraw unless the update queue is full, which means the event loop is busy
+	// and will redraw anyway.
+	select {
+	case a.updates <- func() { a.Draw() }:
+	default:
+	}
+
+	return toast
+}
+
 // Stop stops the application, causing Run() to return.
 func (a *Application) Stop() {
 
@@ -270,6 +558,7 @@ func (a *Application) Draw() *Application {
 	fullscreen := a.rootFullscreen
 	before := a.beforeDraw
 	after := a.afterDraw
+	dialogs := a.dialogs
 	a.RUnlock()
 
 	// Maybe we're not ready yet or not anymore.
@@ -283,6 +572,12 @@ func (a *Application) Draw() *Application {
 		root.SetRect(0, 0, width, height)
 	}
 
+	// Primitives reach the application through the screen.
+	screen = &appScreen{Screen: screen, app: a}
+
+	// Advance animations.
+	a.animator.Step(screen)
+
 	// Call before handler if there is one.
 	if before != nil {
 		if before(screen) {
@@ -295,12 +590,25 @@ func (a *Application) Draw() *Application {
 	// Draw all primitives.
 
 	root.Draw(screen)
+	for _, d := range dialogs {
+		d.dialog.Draw(screen)
+	}
+	for _, overlay := range a.getOverlays() {
+		overlay.Draw(screen)
+	}
+	a.drawTooltip(screen)
 
 	// Call after handler if there is one.
 	if after != nil {
 		after(screen)
 	}
 
+	// Notifications are drawn above everything else.
+	a.notifications.Draw(screen)
+
+	// Draw the glyphs which are missing from the screen's font.
+	a.drawGlyphs(screen)
+
 	// Sync screen.
 	a.screen.Show()
 
@@ -407,6 +715,58 @@ func (a *Application) SetFocus(p Primitive) *Application {
 	return a
 }
 
+// ShowDialog shows a primitive over the root primitive and gives it focus
+// until it is closed with CloseDialog(), which gives the focus back to the
+// primitive which had it before. While dialogs are shown, mouse events only go
+// to the topmost dialog. Dialogs such as Modal or PromptDialog center
+// themselves on the screen, other primitives need to be positioned with
+// SetRect().
+//
+// Like all functions modifying primitives, this function must be called from
+// the event loop. See Prompt(), Confirm(), and similar functions for blocking
+// helpers which can be called from other goroutines.
+func (a *Application) ShowDialog(dialog Primitive) *Application {
+	a.Lock()
+	a.dialogs = append(a.dialogs, appDialog{dialog: dialog, focus: a.focus, closed: make(chan struct{})})
+	a.Unlock()
+
+	a.SetFocus(dialog)
+
+	return a
+}
+
+// CloseDialog removes a dialog shown with ShowDialog(). If it was the topmost
+// dialog, the focus returns to the primitive which had it when the dialog was
+// shown.
+func (a *Application) CloseDialog(dialog Primitive) *Application {
+
+	a.Lock()
+	var restore Primitive
+	for index, d := range a.dialogs {
+		if d.dialog != dialog {
+			continue
+		}
+		if index == len(a.dialogs)-1 {
+			restore = d.focus
+		} else {
+			// The dialog above this one returns the focus to where this one would
+			// have returned it.
+			a.dialogs[index+1].focus = d.focus
+		}
+		a.dialogs = append(a.dialogs[:index], a.dialogs[index+1:]...)
+		close(d.closed)
+		break
+	}
+	a.Unlock()
+
+	detach(dialog)
+	if restore != nil {
+		a.SetFocus(restore)
+	}
+
+	return a
+}
+
 // GetFocus returns the primitive which has the current focus. If none has it,
 // nil is returned.
 func (a *Application) GetFocus() Primitive {
@@ -66,6 +66,23 @@ type Box struct {
 	//mouse behaviour function:
 	mouseCapture func(event *pixelgl.CursorEvent) *pixelgl.CursorEvent
 
+	// The application which drew the box last, if any. Input handlers reach
+	// the application through it, e.g. to open the context menu.
+	app *Application
+
+	// The menu opened by a right click on the box, if any.
+	contextMenu *Menu
+
+	// Whether or not the mouse cursor is over the box, the cursor's latest
+	// position within the box, and an optional handler which is called when
+	// the cursor enters or leaves the box.
+	hovered        bool
+	hoverX, hoverY int
+	hover          func(entered bool)
+
+	// The text shown when the cursor rests on the box.
+	tooltip string
+
 	// An optional function which is called before the box is drawn.
 	draw func(screen ubcell.Screen, x, y, width, height int) (int, int, int, int)
 }
@@ -166,9 +183,18 @@ func (b *Box) WrapHandler(inputHandler func(pixelgl.Event, func(p Primitive))) f
 		case *pixelgl.CursorEvent, *pixelgl.ScrollEvent:
 			if b.mouseCapture != nil {
 			}
+			if ev, ok := event.(*pixelgl.CursorEvent); ok && b.app != nil && b.InRect(ev.X, ev.Y) {
+				b.app.trackHover(b, ev.X, ev.Y)
+			}
 			if event != nil && inputHandler != nil {
 				inputHandler(event, setFocus)
 			}
+
+			// Open the context menu unless a contained primitive opened its own.
+			if ev, ok := event.(*pixelgl.CursorEvent); ok && b.contextMenu != nil && b.app != nil && b.app.topOverlay() == nil &&
+				ev.Button == pixelgl.MouseButtonRight && ev.Act == pixelgl.PRESS && b.InRect(ev.X, ev.Y) {
+				b.app.ShowContextMenu(b.contextMenu, ev.X, ev.Y)
+			}
 		}
 	}
 }
@@ -194,6 +220,20 @@ func (b *Box) SetInputCapture(capture func(event pixelgl.Event) pixelgl.Event) *
 	return b
 }
 
+// SetContextMenu sets a menu which opens at the mouse pointer when the user
+// presses the right mouse button on the box. If a primitive contained in the
+// box has a context menu, too, the innermost one opens. Set it to nil to
+// remove the context menu.
+func (b *Box) SetContextMenu(menu *Menu) *Box {
+	b.contextMenu = menu
+	return b
+}
+
+// GetContextMenu returns the menu set with SetContextMenu().
+func (b *Box) GetContextMenu() *Menu {
+	return b.contextMenu
+}
+
 // GetInputCapture returns the function installed with SetInputCapture() or nil
 // if no such function has been installed.
 func (b *Box) GetKeyInputCapture() func(event pixelgl.Event) pixelgl.Event {
@@ -240,6 +280,8 @@ func (b *Box) SetTitleAlign(align int) *Box {
 
 // Draw draws this primitive onto the screen.
 func (b *Box) Draw(screen ubcell.Screen) {
+	b.app = screenApp(screen)
+
 	// Don't draw anything if there is no space.
 	if b.width <= 0 || b.height <= 0 {
 		return
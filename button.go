@@ -25,6 +25,9 @@ type Button struct {
 	// The background color when the button is in focus.
 	backgroundColorActivated color.RGBA
 
+	// The background color when the mouse cursor is over the button.
+	backgroundColorHovered color.RGBA
+
 	// An optional function which is called when the button was selected.
 	selected func()
 
@@ -43,6 +46,7 @@ func NewButton(label string) *Button {
 		labelColor:               Styles.PrimaryTextColor,
 		labelColorActivated:      Styles.InverseTextColor,
 		backgroundColorActivated: Styles.PrimaryTextColor,
+		backgroundColorHovered:   Styles.MoreContrastBackgroundColor,
 	}
 }
 
@@ -77,6 +81,13 @@ func (b *Button) SetBackgroundColorActivated(color color.RGBA) *Button {
 	return b
 }
 
+// SetBackgroundColorHovered sets the background color of the button while the
+// mouse cursor is over it and it is not in focus.
+func (b *Button) SetBackgroundColorHovered(color color.RGBA) *Button {
+	b.backgroundColorHovered = color
+	return b
+}
+
 // SetSelectedFunc sets a handler which is called when the button was selected.
 func (b *Button) SetSelectedFunc(handler func()) *Button {
 	b.selected = handler
@@ -106,6 +117,8 @@ func (b *Button) Draw(screen ubcell.Screen) {
 		defer func() {
 			b.borderColor = borderColor
 		}()
+	} else if b.hovered {
+		b.backgroundColor = b.backgroundColorHovered
 	}
 	b.Box.Draw(screen)
 	b.backgroundColor = backgroundColor
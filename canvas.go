@@ -0,0 +1,420 @@
+package tview
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/nowakf/ubcell"
+)
+
+// Canvas resolutions, i.e. how many pixels are mapped onto one screen cell.
+const (
+	CanvasBraille   = iota // 2x4 pixels per cell, drawn with Braille patterns.
+	CanvasQuadrant         // 2x2 pixels per cell, drawn with quadrant block elements.
+	CanvasHalfBlock        // 1x2 pixels per cell, drawn with half blocks.
+)
+
+// The cell dimensions in pixels for each canvas mode.
+var canvasCellSizes = [...]struct{ width, height int }{
+	CanvasBraille:   {2, 4},
+	CanvasQuadrant:  {2, 2},
+	CanvasHalfBlock: {1, 2},
+}
+
+// brailleBits maps pixel positions within a cell (x, y) to the bit of the
+// corresponding Braille dot.
+var brailleBits = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// quadrantRunes maps a combination of quadrants (upper left = 1, upper right =
+// 2, lower left = 4, lower right = 8) to the block element which shows them.
+var quadrantRunes = [16]rune{
+	' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛',
+	'▗', '▚', '▐', '▜', '▄', '▙', '▟', '█',
+}
+
+// halfBlockRunes maps a combination of halves (upper = 1, lower = 2) to the
+// block element which shows them.
+var halfBlockRunes = [4]rune{' ', '▀', '▄', '█'}
+
+// Canvas is a box which provides a pixel-addressable drawing surface. The
+// pixels are mapped onto screen cells using Braille patterns or block elements
+// (see SetMode()) so that plots, sparklines, and shapes can be drawn with text
+// cells only.
+//
+// The surface always has the size of the box's inner rect, multiplied by the
+// number of pixels per cell. Its origin (0, 0) is the top-left corner. Pixels
+// which were never set show the box's background color. When the box is
+// resized, the existing pixels are kept in place.
+//
+// Each cell can only show two colors. If the pixels of a cell have different
+// colors, the most frequent one is used for the cell's foreground. In the
+// block element modes, the second most frequent color is used for the cell's
+// background. Braille patterns always keep the box's background.
+//
+// The drawing functions may be called from any goroutine.
+type Canvas struct {
+	sync.Mutex
+	*Box
+
+	// One of the Canvas mode constants.
+	mode int
+
+	// The size of the pixel buffer.
+	width, height int
+
+	// The pixel buffer, row by row. Pixels with a zero alpha value are not set.
+	pixels []color.RGBA
+}
+
+// NewCanvas returns a new canvas using Braille patterns.
+func NewCanvas() *Canvas {
+	return &Canvas{
+		Box:  NewBox(),
+		mode: CanvasBraille,
+	}
+}
+
+// SetMode sets how pixels are mapped onto screen cells, one of CanvasBraille
+// (the default), CanvasQuadrant, or CanvasHalfBlock. This clears the canvas.
+// Other values are ignored.
+func (c *Canvas) SetMode(mode int) *Canvas {
+	if mode < 0 || mode >= len(canvasCellSizes) {
+		return c
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.mode = mode
+	c.width, c.height, c.pixels = 0, 0, nil
+	return c
+}
+
+// GetResolution returns the size of the drawing surface in pixels, based on
+// the current size of the box.
+func (c *Canvas) GetResolution() (width, height int) {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	return c.width, c.height
+}
+
+// resize adapts the pixel buffer to the box's inner rect, keeping existing
+// pixels in place.
+func (c *Canvas) resize() {
+	_, _, width, height := c.GetInnerRect()
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	cell := canvasCellSizes[c.mode]
+	width *= cell.width
+	height *= cell.height
+	if width == c.width && height == c.height {
+		return
+	}
+	pixels := make([]color.RGBA, width*height)
+	for y := 0; y < height && y < c.height; y++ {
+		for x := 0; x < width && x < c.width; x++ {
+			pixels[y*width+x] = c.pixels[y*c.width+x]
+		}
+	}
+	c.width, c.height, c.pixels = width, height, pixels
+}
+
+// set sets a pixel if it lies on the surface.
+func (c *Canvas) set(x, y int, col color.RGBA) {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return
+	}
+	c.pixels[y*c.width+x] = col
+}
+
+// get returns a pixel's color, a zero color if it is not set or if it lies
+// outside the surface.
+func (c *Canvas) get(x, y int) color.RGBA {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return color.RGBA{}
+	}
+	return c.pixels[y*c.width+x]
+}
+
+// Clear unsets all pixels.
+func (c *Canvas) Clear() *Canvas {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	for index := range c.pixels {
+		c.pixels[index] = color.RGBA{}
+	}
+	return c
+}
+
+// SetPixel sets the pixel at the given position to the given color. Pixels
+// outside the surface are ignored.
+func (c *Canvas) SetPixel(x, y int, col color.RGBA) *Canvas {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	c.set(x, y, col)
+	return c
+}
+
+// ClearPixel unsets the pixel at the given position.
+func (c *Canvas) ClearPixel(x, y int) *Canvas {
+	return c.SetPixel(x, y, color.RGBA{})
+}
+
+// GetPixel returns the color of the pixel at the given position and whether
+// or not it is set.
+func (c *Canvas) GetPixel(x, y int) (color.RGBA, bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	col := c.get(x, y)
+	return col, col.A > 0
+}
+
+// DrawLine draws a line from (x0, y0) to (x1, y1), including both end points.
+func (c *Canvas) DrawLine(x0, y0, x1, y1 int, col color.RGBA) *Canvas {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	c.line(x0, y0, x1, y1, col)
+	return c
+}
+
+// line draws a line using Bresenham's algorithm.
+func (c *Canvas) line(x0, y0, x1, y1 int, col color.RGBA) {
+	dx, sx := x1-x0, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	dy, sy := y1-y0, 1
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+	err := dx - dy
+	for {
+		c.set(x0, y0, col)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x0 += sx
+		}
+		if e2 < dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// DrawRect draws the outline of a rectangle whose top-left corner is at (x, y).
+func (c *Canvas) DrawRect(x, y, width, height int, col color.RGBA) *Canvas {
+	if width <= 0 || height <= 0 {
+		return c
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	c.line(x, y, x+width-1, y, col)
+	c.line(x, y+height-1, x+width-1, y+height-1, col)
+	c.line(x, y, x, y+height-1, col)
+	c.line(x+width-1, y, x+width-1, y+height-1, col)
+	return c
+}
+
+// FillRect draws a filled rectangle whose top-left corner is at (x, y).
+func (c *Canvas) FillRect(x, y, width, height int, col color.RGBA) *Canvas {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+
+	// Only loop over the part of the rectangle on the surface.
+	right, bottom := x+width, y+height
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	if right > c.width {
+		right = c.width
+	}
+	if bottom > c.height {
+		bottom = c.height
+	}
+	for py := y; py < bottom; py++ {
+		for px := x; px < right; px++ {
+			c.pixels[py*c.width+px] = col
+		}
+	}
+	return c
+}
+
+// DrawCircle draws the outline of a circle with center (cx, cy) and the given
+// radius.
+func (c *Canvas) DrawCircle(cx, cy, radius int, col color.RGBA) *Canvas {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	c.circle(cx, cy, radius, func(x0, x1, y int) {
+		c.set(x0, y, col)
+		c.set(x1, y, col)
+	})
+	return c
+}
+
+// FillCircle draws a filled circle with center (cx, cy) and the given radius.
+func (c *Canvas) FillCircle(cx, cy, radius int, col color.RGBA) *Canvas {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	c.circle(cx, cy, radius, func(x0, x1, y int) {
+		for x := x0; x <= x1; x++ {
+			c.set(x, y, col)
+		}
+	})
+	return c
+}
+
+// circle runs the midpoint circle algorithm, calling "span" with the left and
+// right end of the circle for the rows it touches.
+func (c *Canvas) circle(cx, cy, radius int, span func(x0, x1, y int)) {
+	if radius < 0 {
+		return
+	}
+	x, y, err := radius, 0, 1-radius
+	for x >= y {
+		span(cx-x, cx+x, cy+y)
+		span(cx-x, cx+x, cy-y)
+		span(cx-y, cx+y, cy+x)
+		span(cx-y, cx+y, cy-x)
+		y++
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// Fill flood-fills the area around (x, y) which has the same color as the
+// pixel at (x, y), i.e. unset pixels are filled up to the nearest set pixels.
+func (c *Canvas) Fill(x, y int, col color.RGBA) *Canvas {
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return c
+	}
+	target := c.get(x, y)
+	if target == col {
+		return c
+	}
+	stack := [][2]int{{x, y}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		px, py := p[0], p[1]
+		if px < 0 || py < 0 || px >= c.width || py >= c.height || c.get(px, py) != target {
+			continue
+		}
+		c.set(px, py, col)
+		stack = append(stack, [2]int{px + 1, py}, [2]int{px - 1, py}, [2]int{px, py + 1}, [2]int{px, py - 1})
+	}
+	return c
+}
+
+// Draw draws this primitive onto the screen.
+func (c *Canvas) Draw(screen ubcell.Screen) {
+	c.Box.Draw(screen)
+
+	c.Lock()
+	defer c.Unlock()
+	c.resize()
+
+	x, y, width, height := c.GetInnerRect()
+	cell := canvasCellSizes[c.mode]
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			// Count the colors of this cell's pixels.
+			var (
+				colors [8]color.RGBA
+				counts [8]int
+				used   int
+			)
+			for py := 0; py < cell.height; py++ {
+				for px := 0; px < cell.width; px++ {
+					col := c.get(cx*cell.width+px, cy*cell.height+py)
+					if col.A == 0 {
+						continue
+					}
+					index := 0
+					for index < used && colors[index] != col {
+						index++
+					}
+					if index == used {
+						colors[index] = col
+						used++
+					}
+					counts[index]++
+				}
+			}
+			if used == 0 {
+				continue // Nothing to draw.
+			}
+
+			// Pick foreground and background colors.
+			fg, bg := 0, -1
+			for index := 1; index < used; index++ {
+				if counts[index] > counts[fg] {
+					fg, bg = index, fg
+				} else if bg < 0 || counts[index] > counts[bg] {
+					bg = index
+				}
+			}
+			background := c.backgroundColor
+			if bg >= 0 && c.mode != CanvasBraille {
+				background = colors[bg]
+			}
+
+			// Determine the rune.
+			var ch rune
+			for py := 0; py < cell.height; py++ {
+				for px := 0; px < cell.width; px++ {
+					col := c.get(cx*cell.width+px, cy*cell.height+py)
+					if col.A == 0 || c.mode != CanvasBraille && col != colors[fg] {
+						continue
+					}
+					switch c.mode {
+					case CanvasBraille:
+						ch |= brailleBits[px][py]
+					case CanvasQuadrant:
+						ch |= 1 << uint(py*2+px)
+					case CanvasHalfBlock:
+						ch |= 1 << uint(py)
+					}
+				}
+			}
+			switch c.mode {
+			case CanvasBraille:
+				ch += 0x2800
+			case CanvasQuadrant:
+				ch = quadrantRunes[ch]
+			case CanvasHalfBlock:
+				ch = halfBlockRunes[ch]
+			}
+
+			style := ubcell.StyleDefault.Background(background).Foreground(colors[fg])
+			screen.SetContent(x+cx, y+cy, ch, style)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package tview
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/nowakf/ubcell"
+)
+
+// testScreen is a screen which records the runes drawn onto it.
+type testScreen struct {
+	ubcell.Screen
+	width, height int
+	cells         map[[2]int]rune
+}
+
+// newTestScreen returns an empty screen of the given size.
+func newTestScreen(width, height int) *testScreen {
+	return &testScreen{width: width, height: height, cells: make(map[[2]int]rune)}
+}
+
+func (s *testScreen) Size() (int, int) {
+	return s.width, s.height
+}
+
+func (s *testScreen) SetContent(x, y int, ch rune, style ubcell.Style) {
+	s.cells[[2]int{x, y}] = ch
+}
+
+func TestCanvasModes(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	tests := []struct {
+		name          string
+		mode          int
+		columns, rows int      // The size of the canvas in cells.
+		width, height int      // The expected resolution.
+		pixels        [][3]int // The x and y of the pixels set, 1 for blue.
+		cells         []rune   // The expected runes, row by row.
+	}{
+		{"braille", CanvasBraille, 2, 1, 4, 4, [][3]int{{0, 0}, {1, 3}, {2, 1}, {3, 2}}, []rune{'⢁', '⠢'}},
+		{"braille empty", CanvasBraille, 2, 1, 4, 4, [][3]int{{3, 3}}, []rune{' ', '⢀'}},
+		{"quadrant", CanvasQuadrant, 2, 1, 4, 2, [][3]int{{0, 0}, {1, 1}, {3, 0}}, []rune{'▚', '▝'}},
+		{"quadrant majority", CanvasQuadrant, 1, 1, 2, 2, [][3]int{{0, 0}, {1, 0}, {0, 1}, {1, 1, 1}}, []rune{'▛'}},
+		{"half block", CanvasHalfBlock, 1, 2, 1, 4, [][3]int{{0, 0}, {0, 3}}, []rune{'▀', '▄'}},
+		{"half block full", CanvasHalfBlock, 2, 1, 2, 2, [][3]int{{0, 0}, {0, 1}, {1, 1}}, []rune{'█', '▄'}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			canvas := NewCanvas().SetMode(test.mode)
+			canvas.SetRect(0, 0, test.columns, test.rows)
+			if width, height := canvas.GetResolution(); width != test.width || height != test.height {
+				t.Fatalf("resolution %dx%d, expected %dx%d", width, height, test.width, test.height)
+			}
+			for _, pixel := range test.pixels {
+				col := red
+				if pixel[2] == 1 {
+					col = blue
+				}
+				canvas.SetPixel(pixel[0], pixel[1], col)
+			}
+			screen := newTestScreen(test.columns, test.rows)
+			canvas.Draw(screen)
+			for index, expected := range test.cells {
+				x, y := index%test.columns, index/test.columns
+				if ch := screen.cells[[2]int{x, y}]; ch != expected {
+					t.Errorf("cell %d,%d is %q, expected %q", x, y, ch, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestCanvasInvalidMode(t *testing.T) {
+	canvas := NewCanvas().SetMode(CanvasHalfBlock).SetMode(-1).SetMode(3)
+	canvas.SetRect(0, 0, 2, 2)
+	if width, height := canvas.GetResolution(); width != 2 || height != 4 {
+		t.Errorf("resolution %dx%d after setting invalid modes, expected 2x4", width, height)
+	}
+	canvas.Draw(newTestScreen(2, 2))
+}
@@ -29,6 +29,9 @@ type Checkbox struct {
 	// The text color of the input area.
 	fieldTextColor color.RGBA
 
+	// Whether or not the user is prevented from changing the checked state.
+	readOnly bool
+
 	// An optional function which is called when the user changes the checked
 	// state of this checkbox.
 	changed func(checked bool)
@@ -60,6 +63,21 @@ func (c *Checkbox) IsChecked() bool {
 	return c.checked
 }
 
+// SetReadOnly sets whether the user is prevented from changing the checked
+// state. A read-only checkbox can still receive focus. It passes Enter, Escape,
+// Tab, and Backtab on to the handler set with SetDoneFunc(), so forms can be
+// navigated as usual, and ignores all other keys.
+func (c *Checkbox) SetReadOnly(readOnly bool) *Checkbox {
+	c.readOnly = readOnly
+	return c
+}
+
+// IsReadOnly returns whether the user is prevented from changing the checked
+// state.
+func (c *Checkbox) IsReadOnly() bool {
+	return c.readOnly
+}
+
 // SetLabel sets the text to be displayed before the input area.
 func (c *Checkbox) SetLabel(label string) *Checkbox {
 	c.label = label
@@ -104,6 +122,11 @@ func (c *Checkbox) GetFieldWidth() int {
 	return 1
 }
 
+// GetFieldHeight returns this primitive's field height.
+func (c *Checkbox) GetFieldHeight() int {
+	return 1
+}
+
 // SetChangedFunc sets a handler which is called when the checked state of this
 // checkbox was changed by the user. The handler function receives the new
 // state.
@@ -164,6 +187,13 @@ func (c *Checkbox) KeyHandler() func(event pixelgl.Event, setFocus func(p Primit
 		if !ok {
 			return
 		}
+		if c.readOnly {
+			// Read-only checkboxes only let the user move on.
+			if finishingKey(ev) && c.done != nil {
+				c.done(ev)
+			}
+			return
+		}
 		switch key := ev.Key; key {
 		case pixelgl.KeyRune, pixelgl.KeyEnter: // Check.
 			if key == pixelgl.KeyRune && ev.Ch != ' ' {
@@ -2,13 +2,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 
-	"github.com/rivo/tview"
+	"github.com/nowakf/pixel"
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/tview"
 )
 
-func main() {
-	app := tview.NewApplication()
+// A font with CJK glyphs. The primary font (Go Mono) doesn't have any.
+var cjkFont = flag.String("cjk", "/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf", "path to a TrueType font with CJK glyphs")
+
+// The texts shown in this demo, used to build the glyph atlas.
+var texts = []string{"先生", "女士", "博士", "老师", "师傅", "称谓", "姓名", "年龄 18+", "密码", "保存", "退出", "输入一些内容", "保存成功，！", "确定"}
+
+func run() {
+	cfg := &tview.Config{
+		FontSize:  18,
+		DPI:       72,
+		Fallbacks: []tview.Font{{Path: *cjkFont}},
+		RuneSets:  append(tview.DefaultRuneSets, tview.RunesOf(texts...)),
+		WindowConfig: pixelgl.WindowConfig{
+			Title:     "Unicode Demo",
+			Bounds:    pixel.R(0, 0, 1024, 768),
+			Resizable: true,
+		},
+	}
+	app, err := tview.NewApplication(cfg)
+	if err != nil {
+		panic(err)
+	}
 	pages := tview.NewPages()
 
 	form := tview.NewForm()
@@ -33,6 +56,11 @@ func main() {
 	}
 }
 
+func main() {
+	flag.Parse()
+	pixelgl.Run(run)
+}
+
 // alert shows a confirmation dialog.
 func alert(pages *tview.Pages, id string, message string) *tview.Pages {
 	return pages.AddPage(
@@ -0,0 +1,580 @@
+package tview
+
+import (
+	"time"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// What the user is dragging on a desktop.
+const (
+	dragMove = iota
+	dragTopLeft
+	dragTopRight
+	dragBottomLeft
+	dragBottomRight
+)
+
+// Desktop is a container for windows (see Window) which float above an
+// optional backdrop primitive, e.g. an application's main view. The windows
+// are stacked: a window which is raised is drawn above all others and receives
+// focus. Minimized windows are listed in a task bar at the bottom of the
+// desktop.
+//
+// The mouse is used as follows:
+//
+//   - Left click on a window: Raise the window.
+//   - Drag the title bar with the left mouse button: Move the window.
+//   - Drag a corner with the left mouse button: Resize the window.
+//   - Double click on the title bar, click on the "□" button: Maximize the
+//     window or restore its size.
+//   - Click on the "_" button: Minimize the window.
+//   - Click on an entry of the task bar: Restore the window.
+//
+// The following keys control the windows:
+//
+//   - Alt-N: Raise the next window.
+//   - Alt-P: Raise the previous window.
+//   - Alt-M: Maximize the top window or restore its size.
+//   - Alt-H: Minimize the top window.
+//   - Alt-W: Start moving and resizing the top window with the keyboard: the
+//     arrow keys move it, the arrow keys with Shift resize it, and Enter or
+//     Escape end it.
+//
+// As key events are only sent to the primitive which has focus, these keys
+// need to be intercepted before they reach the windows' contents. Install
+// CaptureShortcuts() as the application's key capture (see
+// Application.SetKeyCapture()) to make them work.
+type Desktop struct {
+	*Box
+
+	// The primitive drawn below the windows. May be nil.
+	backdrop Primitive
+
+	// The windows, from the bottom to the top.
+	windows []*Window
+
+	// The area available to windows, relative to the screen, as of the last call
+	// to Draw(). It excludes the task bar.
+	areaX, areaY, areaWidth, areaHeight int
+
+	// The task bar entries of minimized windows as of the last call to Draw().
+	taskbar []desktopTask
+
+	// The window being dragged with the mouse, what is being dragged, and the
+	// cursor position and the window's placement when dragging started.
+	dragging                           *Window
+	dragMode                           int
+	dragX, dragY                       int
+	dragRectX, dragRectY, dragW, dragH int
+
+	// The time and the window of the last click on a title bar, to detect double
+	// clicks.
+	lastClick       time.Time
+	lastClickWindow *Window
+
+	// Whether the top window is moved and resized with the keyboard.
+	windowMode bool
+
+	// We keep a reference to the function which allows us to set the focus to
+	// a newly raised window.
+	setFocus func(p Primitive)
+}
+
+// desktopTask is the task bar entry of a minimized window.
+type desktopTask struct {
+	window   *Window
+	x, width int
+}
+
+// NewDesktop returns a new desktop without windows.
+func NewDesktop() *Desktop {
+	d := &Desktop{
+		Box: NewBox(),
+	}
+	d.focus = d
+	return d
+}
+
+// SetBackdrop sets the primitive drawn below the windows, filling the desktop.
+// It may be nil.
+func (d *Desktop) SetBackdrop(backdrop Primitive) *Desktop {
+	if d.backdrop != nil && d.backdrop != backdrop {
+		detach(d.backdrop)
+	}
+	d.backdrop = backdrop
+	return d
+}
+
+// GetBackdrop returns the primitive drawn below the windows.
+func (d *Desktop) GetBackdrop() Primitive {
+	return d.backdrop
+}
+
+// AddWindow adds a window on top of all other windows. It receives focus if the
+// desktop has focus. The window's position is set with Window.SetPlacement().
+func (d *Desktop) AddWindow(window *Window) *Desktop {
+	d.RemoveWindow(window)
+	d.windows = append(d.windows, window)
+	if d.setFocus != nil && d.HasFocus() {
+		d.setFocus(window)
+	}
+	return d
+}
+
+// RemoveWindow removes a window from the desktop. If it had focus, the focus
+// moves to the new top window.
+func (d *Desktop) RemoveWindow(window *Window) *Desktop {
+	for index, w := range d.windows {
+		if w == window {
+			hasFocus := window.HasFocus()
+			d.windows = append(d.windows[:index], d.windows[index+1:]...)
+			detach(window)
+			if window == d.dragging {
+				d.dragging = nil
+				d.app.releaseMouse()
+			}
+			if hasFocus && d.setFocus != nil {
+				d.setFocus(d)
+			}
+			break
+		}
+	}
+	return d
+}
+
+// GetWindows returns the windows from the bottom to the top.
+func (d *Desktop) GetWindows() []*Window {
+	return append([]*Window(nil), d.windows...)
+}
+
+// RaiseWindow moves a window above all other windows, restoring it if it is
+// minimized. It receives focus if the desktop has focus.
+func (d *Desktop) RaiseWindow(window *Window) *Desktop {
+	for index, w := range d.windows {
+		if w == window {
+			d.windows = append(append(d.windows[:index], d.windows[index+1:]...), window)
+			if window.minimized {
+				window.minimized = false
+			}
+			if d.setFocus != nil && d.HasFocus() && !window.HasFocus() {
+				d.setFocus(window)
+			}
+			break
+		}
+	}
+	return d
+}
+
+// topWindow returns the topmost window which is not minimized, or nil if
+// there is none.
+func (d *Desktop) topWindow() *Window {
+	for index := len(d.windows) - 1; index >= 0; index-- {
+		if !d.windows[index].minimized {
+			return d.windows[index]
+		}
+	}
+	return nil
+}
+
+// cycle raises the next window (the bottom one) or the previous window (the
+// one below the top one). Minimized windows are restored when raised.
+func (d *Desktop) cycle(forward bool) {
+	if len(d.windows) < 2 {
+		return
+	}
+	if forward {
+		d.RaiseWindow(d.windows[0])
+		return
+	}
+	top := d.windows[len(d.windows)-1]
+	d.windows = append([]*Window{top}, d.windows[:len(d.windows)-1]...)
+	d.RaiseWindow(d.windows[len(d.windows)-1])
+}
+
+// toggleMaximized maximizes a window or restores its size.
+func (d *Desktop) toggleMaximized(window *Window) {
+	if window.maximized {
+		window.Restore()
+	} else {
+		window.Maximize()
+	}
+}
+
+// minimize minimizes a window and moves the focus to the new top window if it
+// had focus.
+func (d *Desktop) minimize(window *Window) {
+	hasFocus := window.HasFocus()
+	window.Minimize()
+	d.windowMode = false
+	if hasFocus && d.setFocus != nil {
+		d.setFocus(d)
+	}
+}
+
+// CaptureShortcuts executes the window commands for the given event if it is
+// one of the keys listed in the Desktop documentation, returning nil in that
+// case. Other events are returned unchanged. While the top window is moved
+// with the keyboard (after Alt-W), all key events are consumed. The function
+// has the signature of an input capture, so it can be used like this:
+//
+//   app.SetKeyCapture(desktop.CaptureShortcuts)
+func (d *Desktop) CaptureShortcuts(event pixelgl.Event) pixelgl.Event {
+	top := d.topWindow()
+	if d.windowMode && top != nil {
+		if ev, ok := event.(*pixelgl.KeyEv); ok {
+			d.windowKey(top, ev)
+		}
+		return nil
+	}
+	d.windowMode = false
+
+	ev, ok := event.(*pixelgl.KeyEv)
+	if !ok {
+		return event
+	}
+	switch {
+	case altKey(ev, 'n'):
+		d.cycle(true)
+	case altKey(ev, 'p'):
+		d.cycle(false)
+	case altKey(ev, 'm'):
+		if top != nil {
+			d.toggleMaximized(top)
+		}
+	case altKey(ev, 'h'):
+		if top != nil {
+			d.minimize(top)
+		}
+	case altKey(ev, 'w'):
+		d.windowMode = top != nil && !top.maximized && (top.movable || top.resizable)
+	default:
+		return event
+	}
+	return nil
+}
+
+// windowKey moves or resizes a window with the keyboard.
+func (d *Desktop) windowKey(window *Window, ev *pixelgl.KeyEv) {
+	dx, dy := 0, 0
+	switch ev.Key {
+	case pixelgl.KeyLeft:
+		dx = -1
+	case pixelgl.KeyRight:
+		dx = 1
+	case pixelgl.KeyUp:
+		dy = -1
+	case pixelgl.KeyDown:
+		dy = 1
+	case pixelgl.KeyEnter, pixelgl.KeyEscape:
+		d.windowMode = false
+		return
+	}
+	if ev.Mods&pixelgl.ModShift != 0 {
+		if window.resizable {
+			window.winWidth += dx
+			window.winHeight += dy
+			window.clampSize()
+		}
+	} else if window.movable {
+		window.winX += dx
+		window.winY += dy
+		d.clampPosition(window)
+	}
+}
+
+// clampPosition moves a window's stored position back into the area of the
+// last call to Draw() such that its title bar stays reachable. Nothing is
+// changed before the desktop was drawn.
+func (d *Desktop) clampPosition(window *Window) {
+	if d.areaWidth <= 0 || d.areaHeight <= 0 {
+		return
+	}
+	if window.winX > d.areaWidth-4 {
+		window.winX = d.areaWidth - 4
+	}
+	if window.winX+window.winWidth < 4 {
+		window.winX = 4 - window.winWidth
+	}
+	if window.winY > d.areaHeight-1 {
+		window.winY = d.areaHeight - 1
+	}
+	if window.winY < 0 {
+		window.winY = 0
+	}
+}
+
+// Focus is called when this primitive receives focus. The focus is passed on
+// to the top window or, if there is none, to the backdrop.
+func (d *Desktop) Focus(delegate func(p Primitive)) {
+	d.setFocus = delegate
+	if top := d.topWindow(); top != nil {
+		delegate(top)
+		return
+	}
+	if d.backdrop != nil {
+		delegate(d.backdrop)
+		return
+	}
+	d.Box.Focus(delegate)
+}
+
+// Blur is called when this primitive loses focus.
+func (d *Desktop) Blur() {
+	d.Box.Blur()
+	d.windowMode = false
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (d *Desktop) HasFocus() bool {
+	if d.hasFocus || d.backdrop != nil && d.backdrop.GetFocusable().HasFocus() {
+		return true
+	}
+	for _, window := range d.windows {
+		if window.HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// Detach passes the call on to the backdrop and the windows.
+func (d *Desktop) Detach() {
+	if d.backdrop != nil {
+		detach(d.backdrop)
+	}
+	for _, window := range d.windows {
+		detach(window)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (d *Desktop) Draw(screen ubcell.Screen) {
+	d.Box.Draw(screen)
+
+	x, y, width, height := d.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the task bar.
+	d.taskbar = d.taskbar[:0]
+	for _, window := range d.windows {
+		if window.minimized {
+			d.taskbar = append(d.taskbar, desktopTask{window: window})
+		}
+	}
+	if len(d.taskbar) > 0 && height > 1 {
+		height--
+		style := ubcell.StyleDefault.Background(Styles.ContrastBackgroundColor)
+		for cx := x; cx < x+width; cx++ {
+			screen.SetContent(cx, y+height, ' ', style)
+		}
+		pos := x
+		for index := range d.taskbar {
+			task := &d.taskbar[index]
+			title := task.window.title
+			if title == "" {
+				title = "window"
+			}
+			task.x = pos
+			_, task.width = Print(screen, " "+title+" ", pos, y+height, x+width-pos, AlignLeft, Styles.PrimaryTextColor)
+			pos += task.width + 1
+		}
+	}
+	d.areaX, d.areaY, d.areaWidth, d.areaHeight = x, y, width, height
+
+	// Draw the backdrop.
+	if d.backdrop != nil {
+		d.backdrop.SetRect(x, y, width, height)
+		d.backdrop.Draw(screen)
+	}
+
+	// Draw the windows, keeping their title bars reachable.
+	for _, window := range d.windows {
+		if window.minimized {
+			continue
+		}
+		if window.maximized {
+			window.SetRect(x, y, width, height)
+		} else {
+			wx, wy := window.winX, window.winY
+			if wx > width-4 {
+				wx = width - 4
+			}
+			if wx+window.winWidth < 4 {
+				wx = 4 - window.winWidth
+			}
+			if wy > height-1 {
+				wy = height - 1
+			}
+			if wy < 0 {
+				wy = 0
+			}
+			window.SetRect(x+wx, y+wy, window.winWidth, window.winHeight)
+		}
+		drawClipped(screen, window, x, y, width, height)
+	}
+
+	// Highlight the border of a window moved with the keyboard.
+	if top := d.topWindow(); d.windowMode && top != nil {
+		wx, wy, ww, wh := top.GetRect()
+		highlight := func(cx, cy int) {
+			if cx >= x && cx < x+width && cy >= y && cy < y+height {
+				ch, style := screen.GetContent(cx, cy)
+				screen.SetContent(cx, cy, ch, style.Foreground(Styles.TertiaryTextColor))
+			}
+		}
+		for cx := wx; cx < wx+ww; cx++ {
+			highlight(cx, wy)
+			highlight(cx, wy+wh-1)
+		}
+		for cy := wy + 1; cy < wy+wh-1; cy++ {
+			highlight(wx, cy)
+			highlight(wx+ww-1, cy)
+		}
+	}
+}
+
+// KeyHandler returns the handler for this primitive. It receives keys while
+// the desktop itself has focus, i.e. when there are no windows and no
+// backdrop.
+func (d *Desktop) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return d.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		d.CaptureShortcuts(event)
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *Desktop) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return d.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.CursorEvent)
+		if ok && d.dragging != nil {
+			d.drag(ev)
+			return
+		}
+
+		// Scroll events go to the window which has focus.
+		if !ok {
+			for index := len(d.windows) - 1; index >= 0; index-- {
+				if window := d.windows[index]; !window.minimized && passMouse(window, event, setFocus) {
+					return
+				}
+			}
+			passMouse(d.backdrop, event, setFocus)
+			return
+		}
+
+		// Restore windows from the task bar.
+		if ev.Y == d.areaY+d.areaHeight && ev.Button == pixelgl.MouseButtonLeft && ev.Act == pixelgl.PRESS {
+			for _, task := range d.taskbar {
+				if ev.X >= task.x && ev.X < task.x+task.width {
+					d.RaiseWindow(task.window)
+					setFocus(task.window)
+					return
+				}
+			}
+		}
+
+		// Find the window below the cursor.
+		var window *Window
+		for index := len(d.windows) - 1; index >= 0; index-- {
+			if w := d.windows[index]; !w.minimized && w.InRect(ev.X, ev.Y) && inRect(d, ev.X, ev.Y) {
+				window = w
+				break
+			}
+		}
+		if window == nil {
+			passMouse(d.backdrop, event, setFocus)
+			return
+		}
+		if ev.Button != pixelgl.MouseButtonLeft || ev.Act != pixelgl.PRESS {
+			passMouse(window, event, setFocus)
+			return
+		}
+
+		// Clicks raise the window and may start dragging.
+		d.RaiseWindow(window)
+		if !window.HasFocus() {
+			setFocus(window)
+		}
+		wx, wy, ww, wh := window.GetRect()
+		left, right := ev.X == wx, ev.X == wx+ww-1
+		top, bottom := ev.Y == wy, ev.Y == wy+wh-1
+		switch {
+		case window.buttonAt(ev.X, ev.Y) == 'm':
+			d.minimize(window)
+		case window.buttonAt(ev.X, ev.Y) == 'M':
+			d.toggleMaximized(window)
+		case window.resizable && !window.maximized && (left || right) && (top || bottom):
+			switch {
+			case left && top:
+				d.startDrag(window, dragTopLeft, ev)
+			case right && top:
+				d.startDrag(window, dragTopRight, ev)
+			case left:
+				d.startDrag(window, dragBottomLeft, ev)
+			default:
+				d.startDrag(window, dragBottomRight, ev)
+			}
+		case top:
+			if d.lastClickWindow == window && time.Since(d.lastClick) < DoubleClickInterval {
+				d.lastClick = time.Time{}
+				d.toggleMaximized(window)
+				return
+			}
+			d.lastClick, d.lastClickWindow = time.Now(), window
+			if window.movable && !window.maximized {
+				d.startDrag(window, dragMove, ev)
+			}
+		default:
+			passMouse(window, event, setFocus)
+		}
+	})
+}
+
+// startDrag starts moving or resizing a window with the mouse.
+func (d *Desktop) startDrag(window *Window, mode int, ev *pixelgl.CursorEvent) {
+	d.dragging, d.dragMode = window, mode
+	d.dragX, d.dragY = ev.X, ev.Y
+	d.dragRectX, d.dragRectY, d.dragW, d.dragH = window.GetPlacement()
+	d.app.grabMouse(d)
+}
+
+// drag follows the cursor while a window is moved or resized.
+func (d *Desktop) drag(ev *pixelgl.CursorEvent) {
+	window := d.dragging
+	if ev.Act == pixelgl.RELEASE {
+		d.dragging = nil
+		d.app.releaseMouse()
+		return
+	}
+	dx, dy := ev.X-d.dragX, ev.Y-d.dragY
+	x, y, width, height := d.dragRectX, d.dragRectY, d.dragW, d.dragH
+	if d.dragMode == dragMove {
+		window.winX, window.winY = x+dx, y+dy
+		d.clampPosition(window)
+		return
+	}
+
+	// Resize, keeping the opposite corner in place.
+	if d.dragMode == dragTopLeft || d.dragMode == dragBottomLeft {
+		if width-dx < window.minWidth {
+			dx = width - window.minWidth
+		}
+		x, width = x+dx, width-dx
+	} else {
+		width += dx
+	}
+	if d.dragMode == dragTopLeft || d.dragMode == dragTopRight {
+		if height-dy < window.minHeight {
+			dy = height - window.minHeight
+		}
+		y, height = y+dy, height-dy
+	} else {
+		height += dy
+	}
+	window.SetPlacement(x, y, width, height)
+	d.clampPosition(window)
+}
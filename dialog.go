@@ -0,0 +1,789 @@
+package tview
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// dialog is the base of the dialogs in this file: a window with a border and a
+// title which is centered on the screen. It shows a message above a form.
+type dialog struct {
+	*Box
+
+	// The frame embedded in the dialog.
+	frame *Frame
+
+	// The form embedded in the dialog's frame.
+	form *Form
+
+	// The message text (original, not word-wrapped).
+	text string
+
+	// The text color.
+	textColor color.RGBA
+}
+
+// newDialog returns a new dialog with the given title and message.
+func newDialog(title, text string) *dialog {
+	d := &dialog{
+		Box:       NewBox(),
+		text:      text,
+		textColor: Styles.PrimaryTextColor,
+	}
+	d.form = NewForm().
+		SetButtonsAlign(AlignCenter).
+		SetButtonBackgroundColor(Styles.PrimitiveBackgroundColor).
+		SetButtonTextColor(Styles.PrimaryTextColor).
+		SetFieldBackgroundColor(Styles.PrimitiveBackgroundColor)
+	d.form.SetBackgroundColor(Styles.ContrastBackgroundColor).SetBorderPadding(0, 0, 0, 0)
+	d.frame = NewFrame(d.form).SetBorders(0, 0, 1, 0, 0, 0)
+	d.frame.SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(Styles.ContrastBackgroundColor).
+		SetBorderPadding(1, 1, 1, 1)
+	return d
+}
+
+// Focus is called when this primitive receives focus.
+func (d *dialog) Focus(delegate func(p Primitive)) {
+	delegate(d.form)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (d *dialog) HasFocus() bool {
+	return d.form.HasFocus()
+}
+
+// Detach passes the call on to the dialog's contents.
+func (d *dialog) Detach() {
+	detach(d.frame)
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *dialog) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return d.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		passMouse(d.frame, event, setFocus)
+	})
+}
+
+// drawDialog centers the dialog on the screen and draws it. "width" is the
+// minimum width without the border, "formHeight" the number of rows needed by
+// the form. The message text is followed by the given extra lines.
+func (d *dialog) drawDialog(screen ubcell.Screen, width, formHeight int, extra ...string) {
+	d.app = screenApp(screen) // The dialog's box is not drawn.
+
+	// Calculate the width.
+	buttonsWidth := -2
+	for _, button := range d.form.buttons {
+		buttonsWidth += StringWidth(button.label) + 4 + 2
+	}
+	screenWidth, screenHeight := screen.Size()
+	if width < screenWidth/3 {
+		width = screenWidth / 3
+	}
+	if width < buttonsWidth {
+		width = buttonsWidth
+	}
+	if width > screenWidth-4 {
+		width = screenWidth - 4
+	}
+
+	// Set the texts.
+	d.frame.Clear()
+	lines := append(WordWrap(d.text, width), extra...)
+	for _, line := range lines {
+		d.frame.AddText(line, true, AlignCenter, d.textColor)
+	}
+
+	// Set the position and size. The form scrolls if the screen is too small.
+	height := len(lines) + formHeight + 5
+	if height > screenHeight {
+		height = screenHeight
+	}
+	width += 4
+	x := (screenWidth - width) / 2
+	y := (screenHeight - height) / 2
+	d.SetRect(x, y, width, height)
+	d.frame.SetRect(x, y, width, height)
+	d.frame.Draw(screen)
+}
+
+// PromptDialog is a dialog which asks the user to enter a line of text. The
+// text may be checked before it is accepted, see SetValidator().
+type PromptDialog struct {
+	*dialog
+
+	// The field the text is entered in.
+	input *InputField
+
+	// An optional function which is called when the dialog closes.
+	done func(text string, ok bool)
+}
+
+// NewPromptDialog returns a new dialog with the given title and message which
+// asks the user to enter a line of text. The input field is filled with the
+// given value.
+func NewPromptDialog(title, text, value string) *PromptDialog {
+	p := &PromptDialog{
+		dialog: newDialog(title, text),
+		input:  NewInputField().SetText(value),
+	}
+	p.input.SetInputCapture(func(event pixelgl.Event) pixelgl.Event {
+		if ev, ok := event.(*pixelgl.KeyEv); ok && ev.Key == pixelgl.KeyEnter {
+			p.submit()
+			return nil
+		}
+		return event
+	})
+	p.form.AddFormItem(p.input).
+		AddSubmitButton("OK", func() { p.finish(true) }).
+		AddButton("Cancel", func() { p.finish(false) }).
+		SetCancelFunc(func() { p.finish(false) })
+	p.focus = p
+	return p
+}
+
+// SetValidator sets a function which checks the entered text when the user
+// confirms it. If it returns an error, the error's message is shown beneath
+// the input field and the dialog stays open. Provide nil to accept any text.
+func (p *PromptDialog) SetValidator(validator func(text string) error) *PromptDialog {
+	if validator == nil {
+		p.form.SetItemValidator(0, nil)
+		return p
+	}
+	p.form.SetItemValidator(0, func(item FormItem) error {
+		return validator(p.input.GetText())
+	})
+	return p
+}
+
+// SetDoneFunc sets a handler which is called when the dialog closes. It
+// receives the entered text and whether the user confirmed it (false if the
+// user cancelled the dialog).
+func (p *PromptDialog) SetDoneFunc(handler func(text string, ok bool)) *PromptDialog {
+	p.done = handler
+	return p
+}
+
+// GetInputField returns the dialog's input field, e.g. to set a mask character
+// or autocomplete entries.
+func (p *PromptDialog) GetInputField() *InputField {
+	return p.input
+}
+
+// submit confirms the entered text if it is valid.
+func (p *PromptDialog) submit() {
+	if p.form.Validate() {
+		p.finish(true)
+	}
+}
+
+// finish calls the "done" handler.
+func (p *PromptDialog) finish(ok bool) {
+	if p.done != nil {
+		p.done(p.input.GetText(), ok)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (p *PromptDialog) Draw(screen ubcell.Screen) {
+	p.drawDialog(screen, 30, 4) // Input field, error message, empty row, buttons.
+}
+
+// ConfirmDialog is a dialog which asks the user to choose one of several
+// buttons, e.g. "OK" and "Cancel". One of the buttons is focused when the
+// dialog is shown, see SetDefaultButton().
+type ConfirmDialog struct {
+	*dialog
+
+	// An optional function which is called when the dialog closes.
+	done func(buttonIndex int, buttonLabel string)
+}
+
+// NewConfirmDialog returns a new dialog with the given title and message and
+// the given button labels. Without labels, "OK" and "Cancel" are used. The
+// first button is the default button.
+func NewConfirmDialog(title, text string, buttons ...string) *ConfirmDialog {
+	c := &ConfirmDialog{
+		dialog: newDialog(title, text),
+	}
+	if len(buttons) == 0 {
+		buttons = []string{"OK", "Cancel"}
+	}
+	for index, label := range buttons {
+		func(i int, l string) {
+			c.form.AddButton(l, func() { c.finish(i, l) })
+		}(index, label)
+	}
+	c.form.SetCancelFunc(func() { c.finish(-1, "") })
+	c.focus = c
+	return c
+}
+
+// SetDefaultButton sets the index of the button which has focus when the
+// dialog is shown, i.e. which is selected when the user presses Enter.
+func (c *ConfirmDialog) SetDefaultButton(index int) *ConfirmDialog {
+	c.form.focusedElement = index
+	return c
+}
+
+// SetDoneFunc sets a handler which is called when the user selected one of the
+// buttons. It receives the index of the button as well as its label. The
+// handler is also called when the user presses the Escape key. The index will
+// then be negative and the label an empty string.
+func (c *ConfirmDialog) SetDoneFunc(handler func(buttonIndex int, buttonLabel string)) *ConfirmDialog {
+	c.done = handler
+	return c
+}
+
+// finish calls the "done" handler.
+func (c *ConfirmDialog) finish(buttonIndex int, buttonLabel string) {
+	if c.done != nil {
+		c.done(buttonIndex, buttonLabel)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (c *ConfirmDialog) Draw(screen ubcell.Screen) {
+	c.drawDialog(screen, 0, 1)
+}
+
+// ProgressDialog is a dialog which shows the progress of a long-running
+// operation and lets the user cancel it.
+type ProgressDialog struct {
+	*dialog
+
+	// The progress, from 0 to 1.
+	progress float64
+
+	// The color of the progress bar.
+	barColor color.RGBA
+
+	// Whether or not the user cancelled the operation.
+	cancelled bool
+
+	// An optional function which is called when the user cancels the operation.
+	cancel func()
+}
+
+// NewProgressDialog returns a new dialog with the given title and message
+// which shows a progress bar and a "Cancel" button.
+func NewProgressDialog(title, text string) *ProgressDialog {
+	p := &ProgressDialog{
+		dialog:   newDialog(title, text),
+		barColor: Styles.GraphicsColor,
+	}
+	p.form.AddButton("Cancel", p.Cancel).
+		SetCancelFunc(p.Cancel)
+	p.focus = p
+	return p
+}
+
+// SetText sets the message text, e.g. to describe the current step of the
+// operation.
+func (p *ProgressDialog) SetText(text string) *ProgressDialog {
+	p.text = text
+	return p
+}
+
+// SetProgress sets the progress of the operation, from 0 (nothing done) to 1
+// (finished).
+func (p *ProgressDialog) SetProgress(progress float64) *ProgressDialog {
+	if progress < 0 {
+		progress = 0
+	} else if progress > 1 {
+		progress = 1
+	}
+	p.progress = progress
+	return p
+}
+
+// GetProgress returns the progress of the operation, from 0 to 1.
+func (p *ProgressDialog) GetProgress() float64 {
+	return p.progress
+}
+
+// SetBarColor sets the color of the progress bar.
+func (p *ProgressDialog) SetBarColor(color color.RGBA) *ProgressDialog {
+	p.barColor = color
+	return p
+}
+
+// SetCancelFunc sets a handler which is called when the user selects the
+// "Cancel" button or presses Escape. It is called at most once. The dialog
+// stays open until it is closed by the application.
+func (p *ProgressDialog) SetCancelFunc(handler func()) *ProgressDialog {
+	p.cancel = handler
+	return p
+}
+
+// Cancel marks the operation as cancelled and calls the cancel handler if it
+// was not cancelled before.
+func (p *ProgressDialog) Cancel() {
+	if p.cancelled {
+		return
+	}
+	p.cancelled = true
+	p.form.buttons[0].SetLabel("Cancelling…")
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// IsCancelled returns whether the user cancelled the operation.
+func (p *ProgressDialog) IsCancelled() bool {
+	return p.cancelled
+}
+
+// Draw draws this primitive onto the screen.
+func (p *ProgressDialog) Draw(screen ubcell.Screen) {
+	screenWidth, _ := screen.Size()
+	width := screenWidth / 3
+	if width < 20 {
+		width = 20
+	}
+	if width > screenWidth-4 {
+		width = screenWidth - 4
+	}
+
+	// Render the progress bar as an extra line of text.
+	barWidth := width - 5
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	filled := int(p.progress*float64(barWidth) + 0.5)
+	bar := fmt.Sprintf("[#%02x%02x%02x]%s[-]%s%4d%%",
+		p.barColor.R, p.barColor.G, p.barColor.B,
+		strings.Repeat("█", filled), strings.Repeat("░", barWidth-filled), int(p.progress*100+0.5))
+	p.drawDialog(screen, width, 1, "", bar)
+}
+
+// filePickerEntry is an entry of a file picker's directory listing.
+type filePickerEntry struct {
+	name string
+	dir  bool
+}
+
+// FilePicker is a dialog which lets the user browse the local file system and
+// choose a file or, with SetDirectoriesOnly(), a directory. The current
+// directory is shown above a filter field and the directory listing. Typing in
+// the filter field only lists the entries whose names contain the typed text.
+// Files may also be restricted to names matching patterns, see SetPatterns().
+//
+// Selecting a directory in the listing (with Enter or the "Open" button)
+// enters it, selecting ".." enters the parent directory. Selecting a file
+// chooses it. In directory mode, the "Select" button chooses the current
+// directory.
+type FilePicker struct {
+	*dialog
+
+	// The directory being browsed, an absolute path.
+	directory string
+
+	// The field the filter text is entered in.
+	filter *InputField
+
+	// The directory listing and the form item which wraps it.
+	list     *List
+	listItem *FormPrimitive
+
+	// The entries of the directory and the indices of those which are shown in
+	// the list.
+	entries []filePickerEntry
+	shown   []int
+
+	// The patterns file names must match. All files are shown if empty.
+	patterns []string
+
+	// Whether or not only directories are listed and chosen.
+	directoriesOnly bool
+
+	// Whether or not entries whose names start with a dot are listed.
+	showHidden bool
+
+	// The error which occurred when the directory was last read, if any.
+	err error
+
+	// An optional function which is called when the dialog closes.
+	done func(path string, ok bool)
+}
+
+// NewFilePicker returns a new dialog with the given title which lets the user
+// choose a file, starting in the given directory. The working directory is
+// used if it is empty.
+func NewFilePicker(title, directory string) *FilePicker {
+	f := &FilePicker{
+		dialog: newDialog(title, ""),
+		filter: NewInputField(),
+		list:   NewList().ShowSecondaryText(false),
+	}
+	f.filter.SetLabel("Filter").SetChangedFunc(func(text string) {
+		f.refresh()
+	})
+	f.list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		f.open(index)
+	})
+	f.listItem = NewFormPrimitive(f.list)
+	f.form.AddFormItem(f.filter).
+		AddFormItem(f.listItem).
+		AddButton("Open", func() { f.open(f.list.GetCurrentItem()) }).
+		AddButton("Cancel", func() { f.finish("", false) }).
+		SetCancelFunc(func() { f.finish("", false) })
+	f.focus = f
+	f.SetDirectory(directory)
+	return f
+}
+
+// SetDirectory sets the directory being browsed. The working directory is used
+// if it is empty.
+func (f *FilePicker) SetDirectory(directory string) *FilePicker {
+	if directory == "" {
+		directory = "."
+	}
+	if abs, err := filepath.Abs(directory); err == nil {
+		directory = abs
+	}
+	f.directory = directory
+	f.filter.SetText("")
+	f.read()
+	return f
+}
+
+// GetDirectory returns the directory being browsed.
+func (f *FilePicker) GetDirectory() string {
+	return f.directory
+}
+
+// SetPatterns restricts the listed files to those whose names match at least
+// one of the given patterns, e.g. "*.go". See filepath.Match() for the pattern
+// syntax. Directories are always listed.
+func (f *FilePicker) SetPatterns(patterns ...string) *FilePicker {
+	f.patterns = patterns
+	f.read()
+	return f
+}
+
+// SetDirectoriesOnly sets whether the user chooses a directory instead of a
+// file. Only directories are listed then, and a "Select" button chooses the
+// current directory.
+func (f *FilePicker) SetDirectoriesOnly(directoriesOnly bool) *FilePicker {
+	if directoriesOnly != f.directoriesOnly {
+		if directoriesOnly {
+			f.form.buttons = append([]*Button{NewButton("Select").SetSelectedFunc(func() {
+				f.finish(f.directory, true)
+			})}, f.form.buttons...)
+		} else {
+			f.form.buttons = f.form.buttons[1:]
+		}
+	}
+	f.directoriesOnly = directoriesOnly
+	f.read()
+	return f
+}
+
+// SetShowHidden sets whether entries whose names start with a dot are listed.
+func (f *FilePicker) SetShowHidden(showHidden bool) *FilePicker {
+	f.showHidden = showHidden
+	f.read()
+	return f
+}
+
+// SetDoneFunc sets a handler which is called when the dialog closes. It
+// receives the absolute path of the chosen file or directory and whether the
+// user chose one (false if the user cancelled the dialog).
+func (f *FilePicker) SetDoneFunc(handler func(path string, ok bool)) *FilePicker {
+	f.done = handler
+	return f
+}
+
+// read reads the current directory and refreshes the listing.
+func (f *FilePicker) read() {
+	f.entries = nil
+	files, err := ioutil.ReadDir(f.directory)
+	f.err = err
+	if filepath.Dir(f.directory) != f.directory {
+		f.entries = append(f.entries, filePickerEntry{name: "..", dir: true})
+	}
+	for _, file := range files {
+		name := file.Name()
+		if !f.showHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		dir := file.IsDir()
+		if !dir && file.Mode()&os.ModeSymlink != 0 {
+			// Follow symbolic links to directories.
+			if info, err := os.Stat(filepath.Join(f.directory, name)); err == nil {
+				dir = info.IsDir()
+			}
+		}
+		if !dir && (f.directoriesOnly || !f.matches(name)) {
+			continue
+		}
+		f.entries = append(f.entries, filePickerEntry{name: name, dir: dir})
+	}
+
+	// Directories first, then files, each sorted by name.
+	sort.SliceStable(f.entries, func(i, j int) bool {
+		a, b := f.entries[i], f.entries[j]
+		if a.name == ".." || b.name == ".." {
+			return a.name == ".."
+		}
+		if a.dir != b.dir {
+			return a.dir
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+	f.refresh()
+}
+
+// matches returns whether a file name matches one of the patterns.
+func (f *FilePicker) matches(name string) bool {
+	if len(f.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range f.patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// refresh fills the list with the entries matching the filter text.
+func (f *FilePicker) refresh() {
+	filter := strings.ToLower(f.filter.GetText())
+	f.list.Clear()
+	f.shown = f.shown[:0]
+	for index, entry := range f.entries {
+		if filter != "" && entry.name != ".." && !strings.Contains(strings.ToLower(entry.name), filter) {
+			continue
+		}
+		text := Escape(entry.name)
+		if entry.dir {
+			text += string(filepath.Separator)
+		}
+		f.list.AddItem(text, "", 0, nil)
+		f.shown = append(f.shown, index)
+	}
+}
+
+// open enters the directory or chooses the file at the given list index.
+func (f *FilePicker) open(listIndex int) {
+	if listIndex < 0 || listIndex >= len(f.shown) {
+		return
+	}
+	entry := f.entries[f.shown[listIndex]]
+	if entry.dir {
+		f.SetDirectory(filepath.Join(f.directory, entry.name))
+		return
+	}
+	f.finish(filepath.Join(f.directory, entry.name), true)
+}
+
+// finish calls the "done" handler.
+func (f *FilePicker) finish(path string, ok bool) {
+	if f.done != nil {
+		f.done(path, ok)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (f *FilePicker) Draw(screen ubcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+
+	// The listing fills two thirds of the screen height: the dialog consists of
+	// 5 rows of border, padding, and header space, the directory (and an error),
+	// the filter field, an empty row, the listing, an empty row, and the buttons.
+	f.text = f.directory
+	var extra []string
+	if f.err != nil {
+		extra = append(extra, fmt.Sprintf("[#%02x%02x%02x]%s[-]", Styles.ErrorTextColor.R, Styles.ErrorTextColor.G, Styles.ErrorTextColor.B, Escape(f.err.Error())))
+	}
+	width := screenWidth * 2 / 3
+	textHeight := len(WordWrap(f.directory, width)) + len(extra)
+	listHeight := screenHeight*2/3 - 5 - textHeight - 4
+	if listHeight < 3 {
+		listHeight = 3
+	}
+	f.listItem.SetFieldHeight(listHeight)
+	f.drawDialog(screen, width, listHeight+4, extra...)
+}
+
+// showAndWait shows a dialog over the root primitive and blocks until the
+// dialog is closed, either by the function passed to "setDone" or by a call to
+// CloseDialog(), or until the application stops. It must not be called from
+// the event loop.
+func (a *Application) showAndWait(dialog Primitive, setDone func(closeDialog func())) {
+	shown := make(chan chan struct{}, 1)
+	update := func() {
+		setDone(func() {
+			a.CloseDialog(dialog)
+		})
+		a.ShowDialog(dialog)
+		a.RLock()
+		shown <- a.dialogs[len(a.dialogs)-1].closed
+		a.RUnlock()
+		a.Draw()
+	}
+
+	var closed chan struct{}
+	if !a.queueUnlessStopped(update) {
+		return
+	}
+	select {
+	case closed = <-shown:
+	case <-a.stopped:
+		return
+	}
+	select {
+	case <-closed:
+	case <-a.stopped:
+	}
+}
+
+// Prompt shows a PromptDialog over the root primitive and waits until the user
+// closes it. It returns the entered text and whether the user confirmed it.
+// The optional validator is passed to PromptDialog.SetValidator(). The focus
+// returns to where it was when the dialog closes. If the dialog is closed with
+// CloseDialog() or the application stops instead, ok is false.
+//
+// This function blocks until the user closes the dialog and must therefore not
+// be called from the event loop, e.g. from a key handler. Start a goroutine in
+// this case:
+//
+//   go func() {
+//     if name, ok := app.Prompt("Rename", "Enter the new name:", name, nil); ok {
+//       app.QueueUpdateDraw(func() { rename(name) })
+//     }
+//   }()
+func (a *Application) Prompt(title, text, value string, validator func(text string) error) (result string, ok bool) {
+	dialog := NewPromptDialog(title, text, value).SetValidator(validator)
+	a.showAndWait(dialog, func(closeDialog func()) {
+		dialog.SetDoneFunc(func(text string, accepted bool) {
+			result, ok = text, accepted
+			closeDialog()
+		})
+	})
+	return
+}
+
+// Confirm shows a ConfirmDialog with the buttons "OK" and "Cancel" over the
+// root primitive and waits until the user closes it. It returns true if the
+// user selected "OK". The default button is "OK" unless "cancelByDefault" is
+// true. Like Prompt(), this function must not be called from the event loop.
+func (a *Application) Confirm(title, text string, cancelByDefault bool) (confirmed bool) {
+	dialog := NewConfirmDialog(title, text)
+	if cancelByDefault {
+		dialog.SetDefaultButton(1)
+	}
+	a.showAndWait(dialog, func(closeDialog func()) {
+		dialog.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			confirmed = buttonIndex == 0
+			closeDialog()
+		})
+	})
+	return
+}
+
+// ChooseFile shows a FilePicker starting in the given directory over the root
+// primitive and waits until the user closes it. Only files matching one of the
+// given patterns are listed (all files if there are none). It returns the
+// chosen file and whether the user chose one. Like Prompt(), this function
+// must not be called from the event loop.
+func (a *Application) ChooseFile(title, directory string, patterns ...string) (path string, ok bool) {
+	dialog := NewFilePicker(title, directory).SetPatterns(patterns...)
+	a.showAndWait(dialog, func(closeDialog func()) {
+		dialog.SetDoneFunc(func(chosen string, chose bool) {
+			path, ok = chosen, chose
+			closeDialog()
+		})
+	})
+	return
+}
+
+// ChooseDirectory works like ChooseFile() but lets the user choose a
+// directory.
+func (a *Application) ChooseDirectory(title, directory string) (path string, ok bool) {
+	dialog := NewFilePicker(title, directory).SetDirectoriesOnly(true)
+	a.showAndWait(dialog, func(closeDialog func()) {
+		dialog.SetDoneFunc(func(chosen string, chose bool) {
+			path, ok = chosen, chose
+			closeDialog()
+		})
+	})
+	return
+}
+
+// RunWithProgress shows a ProgressDialog over the root primitive while it
+// calls the "work" function, then closes the dialog and returns the function's
+// error. The function runs in the calling goroutine. It reports its progress,
+// from 0 to 1, with the "progress" function and should return early when the
+// "cancelled" channel is closed, which happens when the user cancels the
+// dialog or when the application stops. Like Prompt(), this function must not
+// be called from the event loop.
+//
+//   err := app.RunWithProgress("Import", "Importing files...", func(progress func(float64), cancelled <-chan struct{}) error {
+//     for index, file := range files {
+//       select {
+//       case <-cancelled:
+//         return errCancelled
+//       default:
+//       }
+//       if err := importFile(file); err != nil {
+//         return err
+//       }
+//       progress(float64(index+1) / float64(len(files)))
+//     }
+//     return nil
+//   })
+func (a *Application) RunWithProgress(title, text string, work func(progress func(float64), cancelled <-chan struct{}) error) error {
+	dialog := NewProgressDialog(title, text)
+	cancelled := make(chan struct{})
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() {
+			close(cancelled)
+		})
+	}
+	dialog.SetCancelFunc(cancel)
+
+	// Once the application stops, updates are not executed anymore. They are
+	// dropped then and the work is cancelled.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-a.stopped:
+			cancel()
+		case <-done:
+		}
+	}()
+	update := func(f func()) {
+		a.queueUnlessStopped(func() {
+			f()
+			a.Draw()
+		})
+	}
+
+	update(func() {
+		a.ShowDialog(dialog)
+	})
+	err := work(func(progress float64) {
+		update(func() {
+			dialog.SetProgress(progress)
+		})
+	}, cancelled)
+	update(func() {
+		a.CloseDialog(dialog)
+	})
+	return err
+}
@@ -15,15 +15,30 @@ The package implements the following widgets:
   - InputField: One-line input fields to enter text.
   - DropDown: Drop-down selection fields.
   - Checkbox: Selectable checkbox for boolean values.
+  - RadioGroup: Mutually exclusive options shown next to or below each other.
+  - Switch: Toggle switch for boolean values.
   - Button: Buttons which get activated when the user selects them.
   - Form: Forms composed of input fields, drop down selections, checkboxes, and
     buttons.
   - Modal: A centered window with a text message and one or more buttons.
+  - PromptDialog, ConfirmDialog, ProgressDialog, FilePicker: Dialogs which ask
+    for text, a decision, or a file, or which show progress. See
+    Application.ShowDialog() and blocking helpers such as Application.Prompt().
   - Flex: A Flexbox based layout manager.
+  - SplitPane: Two primitives separated by a movable divider.
   - Pages: A page based layout manager.
+  - Tabs: Pages with a strip of tabs to switch between them.
+  - Desktop: Movable, resizable windows floating above a main view.
+  - MenuBar: Drop-down menus with submenus, checkable items, and mnemonics.
+    Any primitive can have a context menu, see Box.SetContextMenu().
+  - StatusBar: A line of text segments aligned to the left, center, and right.
+  - Canvas: A pixel-addressable drawing surface made of Braille patterns or
+    block elements.
+  - Image: Raster images, drawn as sprites or with half-block characters.
 
 The package also provides Application which is used to poll the event queue and
-draw widgets on screen.
+draw widgets on screen. It also shows transient notifications, see
+Application.Notify(), and tooltips, see Box.SetTooltip().
 
 Hello World
 
@@ -1,8 +1,10 @@
 package tview
 
 import (
+	"fmt"
 	"image/color"
 	"strings"
+	"unicode"
 
 	runewidth "github.com/mattn/go-runewidth"
 	"github.com/nowakf/pixel/pixelgl"
@@ -15,9 +17,30 @@ type dropDownOption struct {
 	Selected func() // The (optional) callback for when this option was selected.
 }
 
+// Filter modes for drop-downs, see DropDown.SetFilterMode().
+const (
+	// Typing jumps to the first option which starts with the typed text.
+	DropDownJump = iota
+
+	// Typing narrows the options to those which contain the typed text.
+	DropDownFilterSubstring
+
+	// Typing narrows the options to those which contain the typed characters
+	// in the same order, but not necessarily next to each other.
+	DropDownFilterFuzzy
+)
+
 // DropDown implements a selection widget whose options become visible in a
 // drop-down list when activated.
 //
+// By default, typing while the list is open jumps to the first option starting
+// with the typed text. With SetFilterMode(), typing narrows the list to the
+// matching options instead, highlighting the matched characters.
+//
+// In multi-select mode (see SetMultiSelect()), any number of options may be
+// selected. Selecting an option in the list checks or unchecks it, the list
+// stays open until the user presses Escape.
+//
 // See https://github.com/rivo/tview/wiki/DropDown for an example.
 type DropDown struct {
 	*Box
@@ -32,8 +55,32 @@ type DropDown struct {
 	// Set to true if the options are visible and selectable.
 	open bool
 
-	// The runes typed so far to directly access one of the list items.
-	prefix string
+	// Whether or not the user is prevented from changing the selection.
+	readOnly bool
+
+	// The runes typed so far to directly access one of the list items or to
+	// filter the list.
+	prefix lineEditor
+
+	// How typing affects the list, one of the DropDownJump or DropDownFilter
+	// constants.
+	filterMode int
+
+	// The indices of the options shown in the list.
+	filtered []int
+
+	// The maximum height of the list. 0 means no limit.
+	maxHeight int
+
+	// Whether or not any number of options may be selected.
+	multiSelect bool
+
+	// The options selected in multi-select mode.
+	selectedOptions map[int]bool
+
+	// An optional function which is called when the user checks or unchecks
+	// an option in multi-select mode.
+	selectionChanged func(indices []int, texts []string)
 
 	// The list element for the options.
 	list *List
@@ -79,6 +126,8 @@ func NewDropDown() *DropDown {
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
 		prefixTextColor:      Styles.ContrastSecondaryTextColor,
+		maxHeight:            10,
+		selectedOptions:      make(map[int]bool),
 	}
 
 	d.focus = d
@@ -141,6 +190,67 @@ func (d *DropDown) SetPrefixTextColor(color color.RGBA) *DropDown {
 	return d
 }
 
+// SetFilterMode sets how typing affects the list while it is open:
+//
+//   - DropDownJump: Jump to the first option starting with the typed text.
+//   - DropDownFilterSubstring: Only show options containing the typed text.
+//   - DropDownFilterFuzzy: Only show options containing the typed characters
+//     in the same order.
+//
+// Matching is not case-sensitive. In the filter modes, matched characters are
+// shown in the prefix text color (see SetPrefixTextColor()).
+func (d *DropDown) SetFilterMode(mode int) *DropDown {
+	d.filterMode = mode
+	return d
+}
+
+// SetMaxHeight sets the maximum height of the list. Longer lists are scrolled.
+// A value of 0 means the list may extend to the edge of the screen. The default
+// is 10.
+func (d *DropDown) SetMaxHeight(height int) *DropDown {
+	d.maxHeight = height
+	return d
+}
+
+// SetMultiSelect sets the flag indicating whether or not any number of options
+// may be selected. In multi-select mode, the options are shown with a checkmark
+// and the field shows all selected options.
+func (d *DropDown) SetMultiSelect(multiSelect bool) *DropDown {
+	d.multiSelect = multiSelect
+	return d
+}
+
+// SetOptionSelected checks (true) or unchecks (false) the option with the given
+// index in multi-select mode. The selection changed handler is not called.
+func (d *DropDown) SetOptionSelected(index int, selected bool) *DropDown {
+	if selected {
+		d.selectedOptions[index] = true
+	} else {
+		delete(d.selectedOptions, index)
+	}
+	return d
+}
+
+// GetSelectedOptions returns the indices and texts of the options selected in
+// multi-select mode, in the order of the options.
+func (d *DropDown) GetSelectedOptions() (indices []int, texts []string) {
+	for index, option := range d.options {
+		if d.selectedOptions[index] {
+			indices = append(indices, index)
+			texts = append(texts, option.Text)
+		}
+	}
+	return
+}
+
+// SetSelectionChangedFunc sets a handler which is called when the user checks
+// or unchecks an option in multi-select mode. It receives the indices and texts
+// of all selected options.
+func (d *DropDown) SetSelectionChangedFunc(handler func(indices []int, texts []string)) *DropDown {
+	d.selectionChanged = handler
+	return d
+}
+
 // SetFormAttributes sets attributes shared by all form items.
 func (d *DropDown) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor color.RGBA) FormItem {
 	d.label = label
@@ -151,6 +261,25 @@ func (d *DropDown) SetFormAttributes(label string, labelColor, bgColor, fieldTex
 	return d
 }
 
+// SetReadOnly sets whether the user is prevented from changing the selection.
+// A read-only drop-down can still receive focus but doesn't open its list. It
+// passes Enter, Escape, Tab, and Backtab on to the handler set with
+// SetDoneFunc(), so forms can be navigated as usual, and ignores all other
+// keys.
+func (d *DropDown) SetReadOnly(readOnly bool) *DropDown {
+	d.readOnly = readOnly
+	if readOnly {
+		d.open = false
+	}
+	return d
+}
+
+// IsReadOnly returns whether the user is prevented from changing the
+// selection.
+func (d *DropDown) IsReadOnly() bool {
+	return d.readOnly
+}
+
 // SetFieldWidth sets the screen width of the options area. A value of 0 means
 // extend to as long as the longest option text.
 func (d *DropDown) SetFieldWidth(width int) *DropDown {
@@ -173,11 +302,15 @@ func (d *DropDown) GetFieldWidth() int {
 	return fieldWidth
 }
 
+// GetFieldHeight returns this primitive's field height.
+func (d *DropDown) GetFieldHeight() int {
+	return 1
+}
+
 // AddOption adds a new selectable option to this drop-down. The "selected"
 // callback is called when this option was selected. It may be nil.
 func (d *DropDown) AddOption(text string, selected func()) *DropDown {
 	d.options = append(d.options, &dropDownOption{Text: text, Selected: selected})
-	d.list.AddItem(text, "", 0, selected)
 	return d
 }
 
@@ -188,6 +321,7 @@ func (d *DropDown) AddOption(text string, selected func()) *DropDown {
 func (d *DropDown) SetOptions(texts []string, selected func(text string, index int)) *DropDown {
 	d.list.Clear()
 	d.options = nil
+	d.selectedOptions = make(map[int]bool)
 	for index, text := range texts {
 		func(t string, i int) {
 			d.AddOption(text, func() {
@@ -217,6 +351,127 @@ func (d *DropDown) SetFinishedFunc(handler func(key *pixelgl.KeyEv)) FormItem {
 	return d.SetDoneFunc(handler)
 }
 
+// updateList fills the list with the options which match the typed text in
+// the filter modes, or with all options otherwise. The current item is kept if
+// possible.
+func (d *DropDown) updateList() {
+	current := d.list.GetCurrentItem()
+	d.list.Clear()
+	d.filtered = d.filtered[:0]
+	query := d.prefix.String()
+	for index, option := range d.options {
+		text := option.Text
+		if d.filterMode != DropDownJump && query != "" {
+			// Match the text as it's shown, without color tags.
+			plain := stripTags(text)
+			positions := matchOption(plain, query, d.filterMode == DropDownFilterFuzzy)
+			if positions == nil {
+				continue
+			}
+			text = d.highlight(plain, positions)
+		}
+		if d.multiSelect {
+			if d.selectedOptions[index] {
+				text = "✓ " + text
+			} else {
+				text = "  " + text
+			}
+		}
+		d.list.AddItem(text, "", 0, nil)
+		d.filtered = append(d.filtered, index)
+	}
+	if current < len(d.filtered) {
+		d.list.SetCurrentItem(current)
+	}
+}
+
+// highlight returns the given option text, which must not contain color tags,
+// with color tags around the runes at the given positions. The rest of the
+// text is escaped so that it's printed as is.
+func (d *DropDown) highlight(text string, positions []int) string {
+	on := fmt.Sprintf("[#%02x%02x%02x]", d.prefixTextColor.R, d.prefixTextColor.G, d.prefixTextColor.B)
+	off := fmt.Sprintf("[#%02x%02x%02x]", d.list.mainTextColor.R, d.list.mainTextColor.G, d.list.mainTextColor.B)
+	var (
+		result  strings.Builder
+		segment []rune
+		matched bool
+	)
+	for index, ch := range []rune(text) {
+		match := len(positions) > 0 && positions[0] == index
+		if match {
+			positions = positions[1:]
+		}
+		if match != matched {
+			result.WriteString(Escape(string(segment)))
+			segment = segment[:0]
+			if match {
+				result.WriteString(on)
+			} else {
+				result.WriteString(off)
+			}
+			matched = match
+		}
+		segment = append(segment, ch)
+	}
+	result.WriteString(Escape(string(segment)))
+	return result.String()
+}
+
+// matchOption returns the positions of the runes of an option text which match
+// the query, or nil if the text does not match. Matching is not case-sensitive.
+// A fuzzy match finds the query's runes in order, otherwise they must appear
+// next to each other.
+func matchOption(text, query string, fuzzy bool) []int {
+	runes := []rune(text)
+	queryRunes := []rune(query)
+	for index := range runes {
+		runes[index] = unicode.ToLower(runes[index])
+	}
+	for index := range queryRunes {
+		queryRunes[index] = unicode.ToLower(queryRunes[index])
+	}
+
+	var positions []int
+	if fuzzy {
+		for index, ch := range runes {
+			if len(positions) < len(queryRunes) && ch == queryRunes[len(positions)] {
+				positions = append(positions, index)
+			}
+		}
+		if len(positions) < len(queryRunes) {
+			return nil
+		}
+		return positions
+	}
+
+substring:
+	for start := 0; start+len(queryRunes) <= len(runes); start++ {
+		for offset, ch := range queryRunes {
+			if runes[start+offset] != ch {
+				continue substring
+			}
+		}
+		for offset := range queryRunes {
+			positions = append(positions, start+offset)
+		}
+		return positions
+	}
+	return nil
+}
+
+// fieldText returns the text shown in the selection area: the current option
+// or, in multi-select mode, all selected options.
+func (d *DropDown) fieldText() string {
+	if d.multiSelect {
+		_, texts := d.GetSelectedOptions()
+		return strings.Join(texts, ", ")
+	}
+	if d.currentOption >= 0 && d.currentOption < len(d.options) {
+		return d.options[d.currentOption].Text
+	}
+	return ""
+}
+
 // Draw draws this primitive onto the screen.
 func (d *DropDown) Draw(screen ubcell.Screen) {
 	d.Box.Draw(screen)
@@ -258,110 +513,171 @@ func (d *DropDown) Draw(screen ubcell.Screen) {
 	}
 
 	// Draw selected text.
-	if d.open && len(d.prefix) > 0 {
+	if d.open && !d.prefix.empty() && d.filterMode != DropDownJump {
+		// Show the filter text.
+		Print(screen, d.prefix.String(), x, y, fieldWidth, AlignLeft, d.prefixTextColor)
+	} else if d.open && !d.prefix.empty() && len(d.filtered) > 0 {
 		// Show the prefix.
-		Print(screen, d.prefix, x, y, fieldWidth, AlignLeft, d.prefixTextColor)
-		prefixWidth := runewidth.StringWidth(d.prefix)
-		listItemText := d.options[d.list.GetCurrentItem()].Text
-		if prefixWidth < fieldWidth && len(d.prefix) < len(listItemText) {
-			Print(screen, listItemText[len(d.prefix):], x+prefixWidth, y, fieldWidth-prefixWidth, AlignLeft, d.fieldTextColor)
+		prefix := d.prefix.String()
+		Print(screen, prefix, x, y, fieldWidth, AlignLeft, d.prefixTextColor)
+		prefixWidth := runewidth.StringWidth(prefix)
+		listItemText := d.options[d.filtered[d.list.GetCurrentItem()]].Text
+		if prefixWidth < fieldWidth && len(prefix) < len(listItemText) {
+			Print(screen, listItemText[len(prefix):], x+prefixWidth, y, fieldWidth-prefixWidth, AlignLeft, d.fieldTextColor)
 		}
-	} else {
-		if d.currentOption >= 0 && d.currentOption < len(d.options) {
-			color := d.fieldTextColor
-			// Just show the current selection.
-			if d.GetFocusable().HasFocus() && !d.open {
-				color = d.fieldBackgroundColor
-			}
-			Print(screen, d.options[d.currentOption].Text, x, y, fieldWidth, AlignLeft, color)
+	} else if text := d.fieldText(); text != "" {
+		color := d.fieldTextColor
+		// Just show the current selection.
+		if d.GetFocusable().HasFocus() && !d.open {
+			color = d.fieldBackgroundColor
 		}
+		Print(screen, text, x, y, fieldWidth, AlignLeft, color)
 	}
 
 	// Draw options list.
 	if d.HasFocus() && d.open {
-		// We prefer to drop down but if there is no space, maybe drop up?
-		lx := x
-		ly := y + 1
 		lwidth := maxWidth
-		lheight := len(d.options)
+		if d.multiSelect {
+			lwidth += 2
+		}
+		lheight := len(d.filtered)
+		if lheight == 0 {
+			lheight = 1 // Keep the (empty) list visible while filtering.
+		}
+		if d.maxHeight > 0 && lheight > d.maxHeight {
+			lheight = d.maxHeight
+		}
+
+		// We prefer to drop down but if there is no space, we drop up if there
+		// is more space above.
 		_, sheight := screen.Size()
-		if ly+lheight >= sheight && ly-2 > lheight-ly {
-			ly = y - lheight
-			if ly < 0 {
-				ly = 0
+		ly := y + 1
+		below, above := sheight-ly, y
+		if lheight > below && above > below {
+			if lheight > above {
+				lheight = above
 			}
+			ly = y - lheight
+		} else if lheight > below {
+			lheight = below
 		}
-		if ly+lheight >= sheight {
-			lheight = sheight - ly
-		}
-		d.list.SetRect(lx, ly, lwidth, lheight)
+		d.list.SetRect(x, ly, lwidth, lheight)
 		d.list.Draw(screen)
 	}
 }
 
-// KeyHandler returns the handler for this primitive.
-func (d *DropDown) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
-	return d.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
-		// A helper function which selects an item in the drop-down list based on
-		// the current prefix.
-		evalPrefix := func() {
-			if len(d.prefix) > 0 {
-				for index, option := range d.options {
-					if strings.HasPrefix(strings.ToLower(option.Text), d.prefix) {
-						d.list.SetCurrentItem(index)
-						return
-					}
-				}
-				// Prefix does not match any item. Remove last rune.
-				r := []rune(d.prefix)
-				d.prefix = string(r[:len(r)-1])
+// evalPrefix selects the first option which starts with the typed text. If
+// there is none, the last typed rune is removed.
+func (d *DropDown) evalPrefix() {
+	if !d.prefix.empty() {
+		for index, option := range d.options {
+			if strings.HasPrefix(strings.ToLower(option.Text), strings.ToLower(d.prefix.String())) {
+				d.list.SetCurrentItem(index)
+				return
 			}
 		}
+		// Prefix does not match any item. Remove last rune.
+		d.prefix.backspace()
+	}
+}
 
+// KeyHandler returns the handler for this primitive.
+func (d *DropDown) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return d.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
 		// Process key event.
 		ev, ok := event.(*pixelgl.KeyEv)
 		if !ok {
 			return
 		}
+		if d.readOnly {
+			// Read-only drop-downs only let the user move on.
+			if finishingKey(ev) && d.done != nil {
+				d.done(ev)
+			}
+			return
+		}
 		switch key := ev.Key; key {
 		case pixelgl.KeyEnter, pixelgl.KeyRune, pixelgl.KeyDown:
-			d.prefix = ""
+			d.prefix.setText("")
+			d.updateList()
+			if d.currentOption >= 0 && d.currentOption < len(d.filtered) {
+				d.list.SetCurrentItem(d.currentOption)
+			}
 
 			// If the first key was a letter already, it becomes part of the prefix.
 			if r := ev.Ch; key == pixelgl.KeyRune && r != ' ' {
-				d.prefix += string(r)
-				evalPrefix()
+				d.prefix.insert(r)
+				if d.filterMode == DropDownJump {
+					d.evalPrefix()
+				} else {
+					d.updateList()
+					d.list.SetCurrentItem(0)
+				}
 			}
 
 			// Hand control over to the list.
 			d.open = true
 			d.list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+				option := d.filtered[index]
+
+				// In multi-select mode, check or uncheck the option and keep the
+				// list open.
+				if d.multiSelect {
+					d.SetOptionSelected(option, !d.selectedOptions[option])
+					d.updateList()
+					if d.selectionChanged != nil {
+						d.selectionChanged(d.GetSelectedOptions())
+					}
+					return
+				}
+
 				// An option was selected. Close the list again.
 				d.open = false
 				setFocus(d)
-				d.currentOption = index
+				d.currentOption = option
 
 				// Trigger "selected" event.
 				if d.options[d.currentOption].Selected != nil {
 					d.options[d.currentOption].Selected()
 				}
+			}).SetDoneFunc(func() {
+				// Escape closes the list without selecting anything.
+				d.open = false
+				setFocus(d)
 			}).SetInputCapture(func(event pixelgl.Event) pixelgl.Event {
 				ev, ok := event.(*pixelgl.KeyEv)
 				if !ok {
 					return event
 				}
 
+				// There is nothing to select in an empty list.
+				if len(d.filtered) == 0 && (ev.Key == pixelgl.KeyEnter || ev.Key == pixelgl.KeyRune && d.filterMode == DropDownJump) {
+					return nil
+				}
+
+				if d.filterMode != DropDownJump {
+					// Typing narrows the list.
+					switch ev.Key {
+					case pixelgl.KeyRune:
+						d.prefix.insert(ev.Ch)
+					case pixelgl.KeyBackspace:
+						d.prefix.backspace()
+					default:
+						return event
+					}
+					d.updateList()
+					d.list.SetCurrentItem(0)
+					return nil
+				}
+
 				if ev.Key == pixelgl.KeyRune {
-					d.prefix += string(ev.Ch)
-					evalPrefix()
+					d.prefix.insert(ev.Ch)
+					d.evalPrefix()
 				} else if ev.Key == pixelgl.KeyBackspace {
-					if len(d.prefix) > 0 {
-						r := []rune(d.prefix)
-						d.prefix = string(r[:len(r)-1])
-					}
-					evalPrefix()
+					d.prefix.backspace()
+					d.evalPrefix()
 				} else {
-					d.prefix = ""
+					d.prefix.setText("")
 				}
 				return event
 			})
@@ -0,0 +1,62 @@
+package tview
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchOption(t *testing.T) {
+	tests := []struct {
+		text, query string
+		fuzzy       bool
+		positions   []int
+	}{
+		// Contiguous matches.
+		{"Apple", "app", false, []int{0, 1, 2}},
+		{"Pineapple", "APPLE", false, []int{4, 5, 6, 7, 8}},
+		{"Banana", "ana", false, []int{1, 2, 3}},
+		{"Größe", "öß", false, []int{2, 3}},
+		{"Apple", "ale", false, nil},
+		{"Apple", "apples", false, nil},
+		{"", "a", false, nil},
+
+		// Fuzzy matches.
+		{"Apple", "ale", true, []int{0, 3, 4}},
+		{"Apple", "app", true, []int{0, 1, 2}},
+		{"New York", "nyk", true, []int{0, 4, 7}},
+		{"Größe", "GE", true, []int{0, 4}},
+		{"Apple", "ela", true, nil},
+		{"Apple", "appple", true, nil},
+		{"", "a", true, nil},
+	}
+	for _, test := range tests {
+		if positions := matchOption(test.text, test.query, test.fuzzy); !reflect.DeepEqual(positions, test.positions) {
+			t.Errorf("matchOption(%q, %q, %t) = %v, expected %v", test.text, test.query, test.fuzzy, positions, test.positions)
+		}
+	}
+}
+
+func TestDropDownFilterTags(t *testing.T) {
+	d := NewDropDown().
+		SetFilterMode(DropDownFilterSubstring).
+		SetOptions([]string{"[red]Apple", "Cherry [x[]", "Banana"}, nil)
+	d.prefix.setText("r")
+	d.updateList()
+
+	// The color tag does not match, the escaped tag keeps its brackets.
+	if !reflect.DeepEqual(d.filtered, []int{1}) {
+		t.Fatalf("filtered options %v, expected [1]", d.filtered)
+	}
+	if text := stripTags(d.list.items[0].MainText); text != "Cherry [x]" {
+		t.Errorf("highlighted option is printed as %q", text)
+	}
+
+	d.prefix.setText("[x")
+	d.updateList()
+	if !reflect.DeepEqual(d.filtered, []int{1}) {
+		t.Fatalf("filtered options %v, expected [1]", d.filtered)
+	}
+	if text := stripTags(d.list.items[0].MainText); text != "Cherry [x]" {
+		t.Errorf("highlighted option is printed as %q", text)
+	}
+}
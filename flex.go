@@ -1,6 +1,7 @@
 package tview
 
 import (
+	"github.com/nowakf/pixel/pixelgl"
 	"github.com/nowakf/ubcell"
 )
 
@@ -10,12 +11,25 @@ const (
 	FlexColumn
 )
 
+// Alignments of flex items across the layout's direction.
+const (
+	FlexAlignDefault = iota // Use the container's alignment (items only).
+	FlexStretch             // Fill the entire line.
+	FlexStart               // Align with the top or left edge of the line.
+	FlexCenter              // Center within the line.
+	FlexEnd                 // Align with the bottom or right edge of the line.
+)
+
 // flexItem holds layout options for one item.
 type flexItem struct {
 	Item       Primitive // The item to be positioned. May be nil for an empty item.
 	FixedSize  int       // The item's fixed size which may not be changed, 0 if it has no fixed size.
 	Proportion int       // The item's proportion.
 	Focus      bool      // Whether or not this item attracts the layout's focus.
+	MinSize    int       // The item's minimum size if it is flexible, 0 for no minimum.
+	MaxSize    int       // The item's maximum size if it is flexible, 0 for no maximum.
+	Align      int       // The item's alignment across the layout's direction.
+	CrossSize  int       // The item's size across the layout's direction, 0 to fill the line.
 }
 
 // Flex is a basic implementation of the Flexbox layout. The contained
@@ -23,6 +37,16 @@ type flexItem struct {
 // distributed along that dimension depends on their layout settings, which is
 // either a fixed length or a proportional length. See AddItem() for details.
 //
+// Flexible items may be given a minimum and a maximum size (see
+// SetItemSizeLimits()). Across the layout's direction, items fill the available
+// space by default, or are aligned with a given size (see SetAlignment() and
+// SetItemAlignment()). SetGap() adds space between items.
+//
+// If wrapping is turned on (see SetWrap()), items which don't fit into the
+// available space with their fixed or minimum sizes continue on a new line
+// (FlexColumn) or column (FlexRow). The lines share the available space unless
+// their items have a size across the layout's direction.
+//
 // See https://github.com/rivo/tview/wiki/Flex for an example.
 type Flex struct {
 	*Box
@@ -36,6 +60,15 @@ type Flex struct {
 	// If set to true, will use the entire screen as its available space instead
 	// its box dimensions.
 	fullScreen bool
+
+	// The default alignment of the items across the layout's direction.
+	align int
+
+	// The number of cells between items and between lines.
+	gap int
+
+	// Whether or not items which don't fit continue on a new line.
+	wrap bool
 }
 
 // NewFlex returns a new flexbox layout container with no primitives and its
@@ -45,6 +78,7 @@ func NewFlex() *Flex {
 	f := &Flex{
 		Box:       NewBox(),
 		direction: FlexColumn,
+		align:     FlexStretch,
 	}
 	f.focus = f
 	return f
@@ -83,17 +117,79 @@ func (f *Flex) AddItem(item Primitive, fixedSize, proportion int, focus bool) *F
 	return f
 }
 
+// SetItemSizeLimits sets the minimum and the maximum size of the given
+// flexible item along the layout's direction. A value of 0 means no limit.
+// Items with a fixed size are not affected.
+func (f *Flex) SetItemSizeLimits(p Primitive, minSize, maxSize int) *Flex {
+	for index := range f.items {
+		if f.items[index].Item == p {
+			f.items[index].MinSize = minSize
+			f.items[index].MaxSize = maxSize
+		}
+	}
+	return f
+}
+
+// SetItemAlignment sets the alignment of the given item across the layout's
+// direction, one of the Flex alignment constants, and its size in that
+// direction. FlexAlignDefault uses the container's alignment (see
+// SetAlignment()). A size of 0 makes the item fill the line, as does the
+// FlexStretch alignment.
+func (f *Flex) SetItemAlignment(p Primitive, align, crossSize int) *Flex {
+	for index := range f.items {
+		if f.items[index].Item == p {
+			f.items[index].Align = align
+			f.items[index].CrossSize = crossSize
+		}
+	}
+	return f
+}
+
+// SetAlignment sets the default alignment of the items across the layout's
+// direction: FlexStretch (the default), FlexStart, FlexCenter, or FlexEnd.
+// Only items with a size across the layout's direction (see
+// SetItemAlignment()) are affected by alignments other than FlexStretch.
+func (f *Flex) SetAlignment(align int) *Flex {
+	f.align = align
+	return f
+}
+
+// SetGap sets the number of cells between items and between wrapped lines.
+func (f *Flex) SetGap(gap int) *Flex {
+	f.gap = gap
+	return f
+}
+
+// SetWrap sets the flag indicating whether or not items which don't fit into
+// the available space with their fixed or minimum sizes continue on a new line.
+func (f *Flex) SetWrap(wrap bool) *Flex {
+	f.wrap = wrap
+	return f
+}
+
 // RemoveItem removes all items for the given primitive from the container,
 // keeping the order of the remaining items intact.
 func (f *Flex) RemoveItem(p Primitive) *Flex {
 	for index := len(f.items) - 1; index >= 0; index-- {
 		if f.items[index].Item == p {
 			f.items = append(f.items[:index], f.items[index+1:]...)
+			if p != nil {
+				detach(p)
+			}
 		}
 	}
 	return f
 }
 
+// Detach passes the call on to all contained primitives.
+func (f *Flex) Detach() {
+	for _, item := range f.items {
+		if item.Item != nil {
+			detach(item.Item)
+		}
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (f *Flex) Draw(screen ubcell.Screen) {
 	f.Box.Draw(screen)
@@ -108,50 +204,197 @@ func (f *Flex) Draw(screen ubcell.Screen) {
 
 	// How much space can we distribute?
 	x, y, width, height := f.GetInnerRect()
-	var proportionSum int
-	distSize := width
+	mainSize, crossSize := width, height
 	if f.direction == FlexRow {
-		distSize = height
+		mainSize, crossSize = height, width
 	}
-	for _, item := range f.items {
-		if item.FixedSize > 0 {
-			distSize -= item.FixedSize
-		} else {
-			proportionSum += item.Proportion
+
+	// Break the items into lines and determine the size of each line.
+	lines := f.lines(mainSize)
+	lineSizes := make([]int, len(lines))
+	if len(lines) == 1 {
+		lineSizes[0] = crossSize
+	} else {
+		free := crossSize - f.gap*(len(lines)-1)
+		var flexibleLines int
+		for index, line := range lines {
+			for _, itemIndex := range line {
+				if size := f.items[itemIndex].CrossSize; size > lineSizes[index] {
+					lineSizes[index] = size
+				}
+			}
+			if lineSizes[index] == 0 {
+				flexibleLines++
+			} else {
+				free -= lineSizes[index]
+			}
+		}
+		for index := range lineSizes {
+			if lineSizes[index] == 0 && flexibleLines > 0 {
+				size := free / flexibleLines
+				if size < 0 {
+					size = 0
+				}
+				lineSizes[index] = size
+				free -= size
+				flexibleLines--
+			}
 		}
 	}
 
 	// Calculate positions and draw items.
-	pos := x
-	if f.direction == FlexRow {
-		pos = y
-	}
-	for _, item := range f.items {
-		size := item.FixedSize
-		if size <= 0 {
-			size = distSize * item.Proportion / proportionSum
-			distSize -= size
-			proportionSum -= item.Proportion
-		}
-		if item.Item != nil {
+	crossPos := 0
+	for lineIndex, line := range lines {
+		lineSize := lineSizes[lineIndex]
+		sizes := f.distribute(line, mainSize)
+		mainPos := 0
+		for index, itemIndex := range line {
+			item := &f.items[itemIndex]
+			size := sizes[index]
+			if item.Item == nil {
+				mainPos += size + f.gap
+				continue
+			}
+			if crossPos >= crossSize && crossPos > 0 {
+				// This line doesn't fit anymore.
+				item.Item.SetRect(x, y, 0, 0)
+				continue
+			}
+			itemSize, offset := f.crossPlacement(item, lineSize)
 			if f.direction == FlexColumn {
-				item.Item.SetRect(pos, y, size, height)
+				item.Item.SetRect(x+mainPos, y+crossPos+offset, size, itemSize)
 			} else {
-				item.Item.SetRect(x, pos, width, size)
+				item.Item.SetRect(x+crossPos+offset, y+mainPos, itemSize, size)
 			}
-		}
-		pos += size
+			mainPos += size + f.gap
 
-		if item.Item != nil {
 			if item.Item.GetFocusable().HasFocus() {
 				defer item.Item.Draw(screen)
 			} else {
 				item.Item.Draw(screen)
 			}
 		}
+		crossPos += lineSize + f.gap
 	}
 }
 
+// lines returns the indices of the items on each line. Without wrapping, all
+// items are on one line. Otherwise, a new line is started when the fixed or
+// minimum sizes of the items exceed the available space.
+func (f *Flex) lines(mainSize int) (lines [][]int) {
+	var (
+		line []int
+		used int
+	)
+	for index, item := range f.items {
+		size := item.FixedSize
+		if size <= 0 {
+			size = item.MinSize
+		}
+		if f.wrap && len(line) > 0 && used+f.gap+size > mainSize {
+			lines = append(lines, line)
+			line, used = nil, 0
+		}
+		if len(line) > 0 {
+			used += f.gap
+		}
+		used += size
+		line = append(line, index)
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return
+}
+
+// distribute returns the sizes of the items of one line along the layout's
+// direction. Fixed-size items keep their size, the remaining space is
+// distributed among the flexible items according to their proportions. Items
+// which would violate their size limits are set to the limit and the remaining
+// space is distributed again among the other items.
+func (f *Flex) distribute(line []int, mainSize int) []int {
+	sizes := make([]int, len(line))
+	done := make([]bool, len(line))
+	available := mainSize - f.gap*(len(line)-1)
+	for index, itemIndex := range line {
+		if size := f.items[itemIndex].FixedSize; size > 0 {
+			sizes[index] = size
+			done[index] = true
+			available -= size
+		}
+	}
+
+	for {
+		// Distribute the space among the remaining items.
+		distSize, proportionSum := available, 0
+		for index, itemIndex := range line {
+			if !done[index] {
+				proportionSum += f.items[itemIndex].Proportion
+			}
+		}
+		for index, itemIndex := range line {
+			if done[index] {
+				continue
+			}
+			sizes[index] = 0
+			if proportionSum > 0 {
+				proportion := f.items[itemIndex].Proportion
+				sizes[index] = distSize * proportion / proportionSum
+				distSize -= sizes[index]
+				proportionSum -= proportion
+			}
+		}
+
+		// Enforce the size limits.
+		var limited bool
+		for index, itemIndex := range line {
+			if done[index] {
+				continue
+			}
+			item := &f.items[itemIndex]
+			size := sizes[index]
+			if item.MaxSize > 0 && size > item.MaxSize {
+				size = item.MaxSize
+			}
+			if size < item.MinSize {
+				size = item.MinSize
+			}
+			if size < 0 {
+				size = 0
+			}
+			if size != sizes[index] {
+				sizes[index] = size
+				done[index] = true
+				available -= size
+				limited = true
+			}
+		}
+		if !limited {
+			return sizes
+		}
+	}
+}
+
+// crossPlacement returns the size of an item across the layout's direction and
+// its offset from the start of its line.
+func (f *Flex) crossPlacement(item *flexItem, lineSize int) (size, offset int) {
+	align := item.Align
+	if align == FlexAlignDefault {
+		align = f.align
+	}
+	size = item.CrossSize
+	if align == FlexStretch || size <= 0 || size > lineSize {
+		return lineSize, 0
+	}
+	switch align {
+	case FlexCenter:
+		offset = (lineSize - size) / 2
+	case FlexEnd:
+		offset = lineSize - size
+	}
+	return
+}
+
 // Focus is called when this primitive receives focus.
 func (f *Flex) Focus(delegate func(p Primitive)) {
 	for _, item := range f.items {
@@ -171,3 +414,14 @@ func (f *Flex) HasFocus() bool {
 	}
 	return false
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *Flex) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return f.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		for _, item := range f.items {
+			if passMouse(item.Item, event, setFocus) {
+				return
+			}
+		}
+	})
+}
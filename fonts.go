@@ -0,0 +1,394 @@
+package tview
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png" // Color glyphs are loaded from PNG files.
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/nowakf/pixel"
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/pixel/text"
+	"github.com/nowakf/ubcell"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font is one font of a fallback chain (see Config.Fallbacks). Either Path,
+// the location of a TrueType file, or TTF, the contents of such a file, must
+// be set. TTF takes precedence if both are given.
+type Font struct {
+	Path string
+	TTF  []byte
+}
+
+// FontGoMono is the monospaced Go font which is embedded in the binary. It is
+// used as the primary font if Config.FontPath is empty.
+var FontGoMono = Font{TTF: gomono.TTF}
+
+// DefaultRuneSets are the runes which are added to the glyph atlas if
+// Config.RuneSets is empty. They cover ASCII, Latin-1, general punctuation,
+// and the graphical characters used by the primitives of this package.
+var DefaultRuneSets = [][]rune{
+	RuneRange(0x0020, 0x007e), // ASCII.
+	RuneRange(0x00a0, 0x00ff), // Latin-1 Supplement.
+	RuneRange(0x2010, 0x205e), // General Punctuation.
+	RuneRange(0x2190, 0x21ff), // Arrows.
+	RuneRange(0x2500, 0x257f), // Box Drawing.
+	RuneRange(0x2580, 0x259f), // Block Elements.
+	RuneRange(0x25a0, 0x25ff), // Geometric Shapes.
+	RuneRange(0x2800, 0x28ff), // Braille Patterns.
+}
+
+// RuneRange returns all runes from "from" to "to" (inclusive). It can be used
+// to add whole Unicode blocks to Config.RuneSets, for example:
+//
+//   cfg.RuneSets = append(tview.DefaultRuneSets, tview.RuneRange(0x4e00, 0x9fff)) // CJK.
+func RuneRange(from, to rune) []rune {
+	if to < from {
+		return nil
+	}
+	runes := make([]rune, 0, to-from+1)
+	for r := from; r <= to; r++ {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+// RunesOf returns the distinct runes of the given texts, excluding color tags.
+// This is useful to add only those glyphs to the atlas which are actually
+// shown, instead of whole Unicode blocks.
+func RunesOf(texts ...string) []rune {
+	seen := make(map[rune]bool)
+	var runes []rune
+	for _, text := range texts {
+		text = escapePattern.ReplaceAllString(colorPattern.ReplaceAllString(text, ""), "[$1$2]")
+		for _, r := range text {
+			if !seen[r] {
+				seen[r] = true
+				runes = append(runes, r)
+			}
+		}
+	}
+	return runes
+}
+
+// load parses the font.
+func (f Font) load() (*truetype.Font, error) {
+	ttf := f.TTF
+	if ttf == nil {
+		if f.Path == "" {
+			return nil, errors.New("font has neither a path nor TTF data")
+		}
+		var err error
+		ttf, err = ioutil.ReadFile(f.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return truetype.Parse(ttf)
+}
+
+// fallbackFace is a font.Face which resolves each glyph individually to the
+// first font of a chain which contains it. The glyph atlas built from it will
+// therefore contain glyphs from all fonts in the chain.
+type fallbackFace struct {
+	sync.Mutex
+
+	// The fonts of the chain, the primary font first.
+	fonts []*truetype.Font
+
+	// The faces corresponding to the fonts.
+	faces []font.Face
+
+	// Caches the index of the face which was chosen for a rune.
+	resolved map[rune]int
+}
+
+// newFallbackFace returns a face for the given font chain. The first font is
+// the primary font whose metrics are used for the face.
+func newFallbackFace(fonts []Font, options *truetype.Options) (*fallbackFace, error) {
+	face := &fallbackFace{resolved: make(map[rune]int)}
+	for _, f := range fonts {
+		ttf, err := f.load()
+		if err != nil {
+			face.Close()
+			return nil, err
+		}
+		face.fonts = append(face.fonts, ttf)
+		face.faces = append(face.faces, truetype.NewFace(ttf, options))
+	}
+	if len(face.faces) == 0 {
+		return nil, errors.New("no fonts")
+	}
+	return face, nil
+}
+
+// faceFor returns the face which is used to draw the given rune. If no font
+// contains the rune, the primary font's face is returned (which will then
+// draw its "missing glyph" symbol).
+func (f *fallbackFace) faceFor(r rune) font.Face {
+	f.Lock()
+	defer f.Unlock()
+	index, ok := f.resolved[r]
+	if !ok {
+		for i, ttf := range f.fonts {
+			if ttf.Index(r) != 0 {
+				index = i
+				break
+			}
+		}
+		f.resolved[r] = index
+	}
+	return f.faces[index]
+}
+
+// fallback returns whether the given rune is taken from one of the fallback
+// fonts, i.e. whether the primary font lacks it but another font has it.
+func (f *fallbackFace) fallback(r rune) bool {
+	return f.faceFor(r) != f.faces[0]
+}
+
+// Close closes all faces of the chain.
+func (f *fallbackFace) Close() error {
+	var err error
+	for _, face := range f.faces {
+		if e := face.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Glyph returns the glyph of the first font which contains the rune.
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+// GlyphBounds returns the bounds of the glyph of the first font which contains
+// the rune.
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+// GlyphAdvance returns the advance of the glyph of the first font which
+// contains the rune.
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+// Kern returns the kerning between two runes if they come from the same font,
+// and 0 otherwise.
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := f.faceFor(r0)
+	if face != f.faceFor(r1) {
+		return 0
+	}
+	return face.Kern(r0, r1)
+}
+
+// Metrics returns the metrics of the primary font so that all cells have the
+// same size, regardless of which font a glyph is taken from.
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
+
+// ColorGlyphErrors is returned by LoadColorGlyphs() if some files could not be
+// loaded. It maps the file names to the errors which occurred.
+type ColorGlyphErrors map[string]error
+
+// Error returns a description of the first few errors.
+func (e ColorGlyphErrors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	message := fmt.Sprintf("%d color glyphs could not be loaded", len(names))
+	for index, name := range names {
+		if index == 3 {
+			message += ", ..."
+			break
+		}
+		message += fmt.Sprintf(", %s: %s", name, e[name])
+	}
+	return message
+}
+
+// LoadColorGlyphs loads color bitmaps (e.g. emoji) from a directory of PNG
+// files which are named after the hexadecimal code point they represent, for
+// example "1f600.png" for U+1F600. This is the naming scheme used by the
+// common emoji image sets. Files whose name is not a single code point (e.g.
+// emoji sequences such as "1f468-200d-1f469.png") are skipped.
+//
+// Files which cannot be opened or decoded are skipped, too. They are reported
+// in an error of type ColorGlyphErrors, which is returned along with the
+// glyphs which were loaded.
+func LoadColorGlyphs(dir string) (map[rune]image.Image, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	glyphs := make(map[rune]image.Image)
+	failed := make(ColorGlyphErrors)
+	for _, info := range files {
+		name := info.Name()
+		if info.IsDir() || strings.ToLower(filepath.Ext(name)) != ".png" {
+			continue
+		}
+		name = strings.TrimPrefix(strings.TrimSuffix(name, filepath.Ext(name)), "u")
+		code, err := strconv.ParseUint(name, 16, 32)
+		if err != nil {
+			continue // Not a single code point.
+		}
+		file, err := os.Open(filepath.Join(dir, info.Name()))
+		if err != nil {
+			failed[info.Name()] = err
+			continue
+		}
+		img, _, err := image.Decode(file)
+		file.Close()
+		if err != nil {
+			failed[info.Name()] = err
+			continue
+		}
+		glyphs[rune(code)] = img
+	}
+	if len(failed) > 0 {
+		return glyphs, failed
+	}
+	return glyphs, nil
+}
+
+// appGlyphs holds what an application needs to draw the glyphs which the
+// screen cannot draw itself, see Application.drawGlyphs().
+type appGlyphs struct {
+	// The sprites of the color glyphs drawn so far.
+	sprites map[rune]*pixel.Sprite
+
+	// A white pixel, scaled to fill the background of cells.
+	background *pixel.Sprite
+
+	// The text which draws single glyphs from the atlas.
+	text *text.Text
+}
+
+// glyphCell is a cell over which a glyph is drawn.
+type glyphCell struct {
+	ch     rune
+	matrix pixel.Matrix // Maps the cell's area to the screen.
+	width  float64      // The width of the cell's area in pixels.
+	fg, bg color.RGBA
+
+	// The color glyph and its matrix. The sprite is nil for glyphs which are
+	// taken from the atlas.
+	sprite       *pixel.Sprite
+	spriteMatrix pixel.Matrix
+}
+
+// drawGlyphs draws the glyphs which the screen cannot draw with its font over
+// their cells: the color glyphs (see Config.ColorGlyphPath) and the glyphs of
+// the fallback fonts (see Config.Fallbacks), which are taken from the glyph
+// atlas. The cell's background is filled first to hide the screen's "missing
+// glyph" symbol. Wide runes cover two cells.
+func (a *Application) drawGlyphs(screen ubcell.Screen) {
+	atlas, err := a.cfg.GetAtlas()
+	if err != nil || atlas == nil {
+		return
+	}
+	colorGlyphs, _ := a.cfg.GetColorGlyphs()
+	face, _ := a.cfg.face.(*fallbackFace)
+	if len(colorGlyphs) == 0 && (face == nil || len(face.faces) < 2) {
+		return // The screen's font has all glyphs there are.
+	}
+	cell := screenCellSize(screen)
+	if cell.X == 0 || cell.Y == 0 {
+		return
+	}
+
+	// Find the cells to draw over.
+	glyphs := &a.glyphs
+	var cells []glyphCell
+	screenWidth, screenHeight := screen.Size()
+	for y := 0; y < screenHeight; y++ {
+		for x := 0; x < screenWidth; x++ {
+			ch, style := screen.GetContent(x, y)
+			img, isColor := colorGlyphs[ch]
+			if !isColor && (face == nil || !face.fallback(ch)) {
+				continue
+			}
+			width := runewidth.RuneWidth(ch)
+			if width < 1 {
+				width = 1
+			}
+			if x+width > screenWidth {
+				width = screenWidth - x
+			}
+			c := glyphCell{
+				ch:     ch,
+				matrix: screen.GetMatrix(x, y, width, 1),
+				width:  float64(width) * cell.X,
+			}
+			c.fg, c.bg = style.Decompose()
+			if isColor {
+				c.sprite = glyphs.sprites[ch]
+				if c.sprite == nil {
+					picture := pixel.PictureDataFromImage(img)
+					c.sprite = pixel.NewSprite(picture, picture.Bounds())
+					if glyphs.sprites == nil {
+						glyphs.sprites = make(map[rune]*pixel.Sprite)
+					}
+					glyphs.sprites[ch] = c.sprite
+				}
+				bounds := c.sprite.Frame()
+				src, dst := imageLayout(ImageFit, bounds.W(), bounds.H(), c.width, cell.Y)
+				c.spriteMatrix = pixel.IM.
+					ScaledXY(pixel.ZV, pixel.V(dst.W()/src.W(), dst.H()/src.H())).
+					Chained(c.matrix)
+			}
+			cells = append(cells, c)
+			x += width - 1
+		}
+	}
+	if len(cells) == 0 {
+		return
+	}
+
+	if glyphs.background == nil {
+		white := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		white.Set(0, 0, color.White)
+		picture := pixel.PictureDataFromImage(white)
+		glyphs.background = pixel.NewSprite(picture, picture.Bounds())
+	}
+	if glyphs.text == nil {
+		glyphs.text = text.New(pixel.ZV, atlas)
+	}
+	background, glyphText, cellHeight := glyphs.background, glyphs.text, cell.Y
+	screen.Call(func(win *pixelgl.Window) {
+		for _, c := range cells {
+			background.DrawColorMask(win, pixel.IM.ScaledXY(pixel.ZV, pixel.V(c.width, cellHeight)).Chained(c.matrix), c.bg)
+			if c.sprite != nil {
+				c.sprite.Draw(win, c.spriteMatrix)
+				continue
+			}
+
+			// Center the glyph's line box in the cell.
+			glyphText.Clear()
+			glyphText.Ink(c.fg)
+			glyphText.Add(c.ch, pixel.ZV.Sub(glyphText.BoundsOf(string(c.ch)).Center()))
+			glyphText.Apply()
+			glyphText.Draw(win, c.matrix)
+		}
+	})
+}
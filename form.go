@@ -30,6 +30,11 @@ type FormItem interface {
 	// required.
 	GetFieldWidth() int
 
+	// GetFieldHeight returns the height of the form item in number of screen
+	// rows. Most items occupy a single row. Values less than 1 are treated as
+	// 1.
+	GetFieldHeight() int
+
 	// SetEnteredFunc sets the handler function for when the user finished
 	// entering data into the item. The handler may receive events for the
 	// Enter key (we're done), the Escape key (cancel input), the Tab key (move to
@@ -37,10 +42,32 @@ type FormItem interface {
 	SetFinishedFunc(handler func(key *pixelgl.KeyEv)) FormItem
 }
 
-// Form allows you to combine multiple one-line form elements into a vertical
-// or horizontal layout. Form elements include types such as InputField or
+// finishingKey returns whether a key finishes the input into a form item, i.e.
+// whether it's one of the keys passed to the handler set with
+// SetFinishedFunc().
+func finishingKey(ev *pixelgl.KeyEv) bool {
+	switch ev.Key {
+	case pixelgl.KeyEnter, pixelgl.KeyEscape, pixelgl.KeyTab, pixelgl.KeyBacktab:
+		return true
+	}
+	return false
+}
+
+// Form allows you to combine multiple form elements into a vertical or
+// horizontal layout. Form elements include types such as InputField or
 // Checkbox. These elements can be optionally followed by one or more buttons
-// for which you can define form-wide actions (e.g. Save, Clear, Cancel).
+// for which you can define form-wide actions (e.g. Save, Clear, Cancel). Most
+// elements occupy a single row but other primitives, e.g. a List, can be added
+// with any height (see AddPrimitive()). In vertical layouts, items can be
+// organized in titled, optionally bordered or collapsible groups (see
+// AddGroup()) and placed side by side (see Inline()).
+//
+// If the elements don't fit into the form, it scrolls such that the focused
+// element is always visible and a scroll indicator is shown on the right.
+//
+// Item values can be checked with validators (see SetItemValidator() and
+// SetValidator()). Error messages are shown next to the invalid items and
+// buttons added with AddSubmitButton() only submit a valid form.
 //
 // See https://github.com/rivo/tview/wiki/Form for an example.
 type Form struct {
@@ -83,6 +110,51 @@ type Form struct {
 
 	// An optional function which is called when the user hits Escape.
 	cancel func()
+
+	// Optional functions which check the values of individual items.
+	validators map[FormItem]func(item FormItem) error
+
+	// An optional function which checks rules involving multiple items.
+	validator func(form *Form) error
+
+	// The errors found during the last validation of each item. Valid items
+	// have no entry.
+	errors map[FormItem]error
+
+	// The error returned by the form-level validator during the last
+	// validation.
+	formError error
+
+	// The color of error messages.
+	errorColor color.RGBA
+
+	// If set to true, error messages are shown to the right of items with a
+	// fixed field width instead of beneath them.
+	errorsBeside bool
+
+	// The buttons which submit the form, i.e. which are only selected when the
+	// form is valid.
+	submitButtons map[*Button]bool
+
+	// The function which was last used to hand on focus to one of the form's
+	// elements.
+	delegate func(p Primitive)
+
+	// The items which are bound to struct fields (see Bind()).
+	bindings []*formBinding
+
+	// The number of rows the form is scrolled down.
+	offset int
+
+	// The items which are placed in the same row as the item before them in
+	// vertical layouts (see Inline()).
+	inline map[FormItem]bool
+
+	// The items after which the group they belong to ends (see EndGroup()).
+	groupEnds map[FormItem]bool
+
+	// The color of the scroll indicator.
+	scrollIndicatorColor color.RGBA
 }
 
 // NewForm returns a new form.
@@ -97,6 +169,13 @@ func NewForm() *Form {
 		fieldTextColor:        Styles.PrimaryTextColor,
 		buttonBackgroundColor: Styles.ContrastBackgroundColor,
 		buttonTextColor:       Styles.PrimaryTextColor,
+		validators:            make(map[FormItem]func(item FormItem) error),
+		errors:                make(map[FormItem]error),
+		errorColor:            Styles.ErrorTextColor,
+		submitButtons:         make(map[*Button]bool),
+		scrollIndicatorColor:  Styles.GraphicsColor,
+		inline:                make(map[FormItem]bool),
+		groupEnds:             make(map[FormItem]bool),
 	}
 
 	f.focus = f
@@ -139,6 +218,13 @@ func (f *Form) SetFieldTextColor(color color.RGBA) *Form {
 	return f
 }
 
+// SetScrollIndicatorColor sets the color of the scroll indicator which is shown
+// when the form's elements don't fit into it.
+func (f *Form) SetScrollIndicatorColor(color color.RGBA) *Form {
+	f.scrollIndicatorColor = color
+	return f
+}
+
 // SetButtonsAlign sets how the buttons align horizontally, one of AlignLeft
 // (the default), AlignCenter, and AlignRight. This is only
 func (f *Form) SetButtonsAlign(align int) *Form {
@@ -215,6 +301,79 @@ func (f *Form) AddCheckbox(label string, checked bool, changed func(checked bool
 	return f
 }
 
+// AddRadioGroup adds a radio group to the form. It has a label, options, the
+// index of the initially selected option, a flag indicating whether the options
+// are arranged horizontally, and an (optional) callback function which is
+// invoked when the user selects a different option.
+func (f *Form) AddRadioGroup(label string, options []string, initialOption int, horizontal bool, changed func(option string, optionIndex int)) *Form {
+	f.items = append(f.items, NewRadioGroup(options...).
+		SetLabel(label).
+		SetCurrentOption(initialOption).
+		SetHorizontal(horizontal).
+		SetChangedFunc(changed))
+	return f
+}
+
+// AddSwitch adds a switch to the form. It has a label, an initial state, and an
+// (optional) callback function which is invoked when the user toggles the
+// switch.
+func (f *Form) AddSwitch(label string, on bool, changed func(on bool)) *Form {
+	f.items = append(f.items, NewSwitch().
+		SetLabel(label).
+		SetOn(on).
+		SetChangedFunc(changed))
+	return f
+}
+
+// AddPrimitive adds any primitive to the form, e.g. a List or a TextView, with
+// a label. The primitive is placed in a field of the given width (0 extends it
+// as far as possible) and height (the number of rows). See FormPrimitive for
+// details.
+func (f *Form) AddPrimitive(label string, p Primitive, fieldWidth, fieldHeight int) *Form {
+	f.items = append(f.items, NewFormPrimitive(p).
+		SetLabel(label).
+		SetFieldWidth(fieldWidth).
+		SetFieldHeight(fieldHeight))
+	return f
+}
+
+// AddGroup starts a new group of form items with the given header. All items
+// added after it belong to the group until EndGroup() or AddGroup() is called.
+// Groups have a title, an optional border, and may be collapsible. See
+// FormGroup for details. For example:
+//
+//   form.AddGroup(tview.NewFormGroup("Server").SetBorder(true)).
+//     AddInputField("Host", "", 0, nil, nil).
+//     AddInputField("Port", "", 6, tview.InputFieldInteger, nil).Inline().
+//     EndGroup()
+//
+// The group header counts as a form item, i.e. it has an index in the form
+// (see GetFormItem()).
+func (f *Form) AddGroup(group *FormGroup) *Form {
+	f.items = append(f.items, group)
+	return f
+}
+
+// EndGroup ends the current group. Items added after this call don't belong
+// to any group.
+func (f *Form) EndGroup() *Form {
+	if len(f.items) > 0 {
+		f.groupEnds[f.items[len(f.items)-1]] = true
+	}
+	return f
+}
+
+// Inline places the item added last in the same row as the item before it,
+// e.g. to show first and last name side by side. Items with a fixed field
+// width keep their width, the remaining space is shared by items with a
+// flexible field width. This only applies to vertical layouts.
+func (f *Form) Inline() *Form {
+	if len(f.items) > 0 {
+		f.inline[f.items[len(f.items)-1]] = true
+	}
+	return f
+}
+
 // AddButton adds a new button to the form. The "selected" function is called
 // when the user selects this button. It may be nil.
 func (f *Form) AddButton(label string, selected func()) *Form {
@@ -222,12 +381,37 @@ func (f *Form) AddButton(label string, selected func()) *Form {
 	return f
 }
 
+// AddSubmitButton adds a new button to the form which submits it. When the
+// user selects this button, the form is validated first (see Validate()). The
+// "submitted" function is only called if the form is valid. Otherwise, focus
+// moves to the first invalid item. While the form is known to be invalid, the
+// button's label is drawn in the error color.
+func (f *Form) AddSubmitButton(label string, submitted func()) *Form {
+	button := NewButton(label)
+	button.SetSelectedFunc(func() {
+		if f.Validate() && submitted != nil {
+			submitted()
+		}
+	})
+	f.submitButtons[button] = true
+	f.buttons = append(f.buttons, button)
+	return f
+}
+
 // Clear removes all input elements from the form, including the buttons if
 // specified.
 func (f *Form) Clear(includeButtons bool) *Form {
 	f.items = nil
+	f.validators = make(map[FormItem]func(item FormItem) error)
+	f.errors = make(map[FormItem]error)
+	f.formError = nil
+	f.bindings = nil
+	f.offset = 0
+	f.inline = make(map[FormItem]bool)
+	f.groupEnds = make(map[FormItem]bool)
 	if includeButtons {
 		f.buttons = nil
+		f.submitButtons = make(map[*Button]bool)
 	}
 	f.focusedElement = 0
 	return f
@@ -255,81 +439,549 @@ func (f *Form) SetCancelFunc(callback func()) *Form {
 	return f
 }
 
+// SetItemValidator sets a function which checks the value of the form item at
+// the given index (buttons are not included). It is called when the user
+// leaves the item and when the form is validated (see Validate()). If it
+// returns an error, the error's message is shown next to the item until the
+// item is validated again. For example:
+//
+//   form.SetItemValidator(0, func(item tview.FormItem) error {
+//     if item.(*tview.InputField).GetText() == "" {
+//       return errors.New("Please enter your name")
+//     }
+//     return nil
+//   })
+//
+// Provide nil to remove the validator.
+func (f *Form) SetItemValidator(index int, validator func(item FormItem) error) *Form {
+	item := f.items[index]
+	if validator == nil {
+		delete(f.validators, item)
+		delete(f.errors, item)
+	} else {
+		f.validators[item] = validator
+	}
+	return f
+}
+
+// SetValidator sets a function which checks rules involving multiple items,
+// e.g. that two password fields match. It is called by Validate() if all items
+// with a validator are valid. If it returns an error, the error's message is
+// shown above the buttons.
+func (f *Form) SetValidator(validator func(form *Form) error) *Form {
+	f.validator = validator
+	f.formError = nil
+	return f
+}
+
+// SetErrorColor sets the color of error messages.
+func (f *Form) SetErrorColor(color color.RGBA) *Form {
+	f.errorColor = color
+	return f
+}
+
+// SetErrorsBeside sets the flag which, if true, causes error messages to be
+// shown to the right of items in vertical layouts instead of beneath them.
+// This only applies to items with a fixed field width. Messages of other items
+// and messages in horizontal layouts are always shown beneath the items.
+func (f *Form) SetErrorsBeside(beside bool) *Form {
+	f.errorsBeside = beside
+	return f
+}
+
+// Validate runs the validators of all items and, if they all pass, the form's
+// validator. It returns true if the form is valid. If an item is invalid, focus
+// moves to the first invalid item (if the form had focus before).
+func (f *Form) Validate() bool {
+	firstInvalid := -1
+	for index := range f.items {
+		if !f.validateItem(index) && firstInvalid < 0 {
+			firstInvalid = index
+		}
+	}
+
+	f.formError = nil
+	if firstInvalid < 0 && f.validator != nil {
+		f.formError = f.validator(f)
+	}
+
+	if firstInvalid >= 0 {
+		// Make sure the item is visible.
+		if group := f.itemGroups()[firstInvalid]; group != nil {
+			group.collapsed = false
+		}
+		if f.delegate != nil {
+			f.focusedElement = firstInvalid
+			f.Focus(f.delegate)
+		}
+	}
+
+	return firstInvalid < 0 && f.formError == nil
+}
+
+// IsValid returns whether or not the form was found to be valid the last time
+// its items were validated. Items which were never validated are considered
+// valid.
+func (f *Form) IsValid() bool {
+	return len(f.errors) == 0 && f.formError == nil
+}
+
+// GetItemError returns the error found during the last validation of the form
+// item at the given index, nil if the item was valid. Buttons are not included.
+func (f *Form) GetItemError(index int) error {
+	return f.errors[f.items[index]]
+}
+
+// GetError returns the error returned by the form's validator during the last
+// validation, nil if there was none.
+func (f *Form) GetError() error {
+	return f.formError
+}
+
+// validateItem runs the validator of the form item with the given index, if
+// it has one, and stores the result. Returns true if the item is valid.
+func (f *Form) validateItem(index int) bool {
+	item := f.items[index]
+	validator, ok := f.validators[item]
+	if !ok {
+		return true
+	}
+	if err := validator(item); err != nil {
+		f.errors[item] = err
+		return false
+	}
+	delete(f.errors, item)
+	return true
+}
+
+// formPosition is the position of a form element before scrolling.
+type formPosition struct{ x, y, width, height int }
+
+// formFrame is the frame drawn around the items of a bordered group.
+type formFrame struct {
+	formPosition
+	color color.RGBA
+}
+
 // Draw draws this primitive onto the screen.
 func (f *Form) Draw(screen ubcell.Screen) {
 	f.Box.Draw(screen)
+	f.syncBindings()
 
 	// Determine the dimensions.
 	x, y, width, height := f.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
 	topLimit := y
 	bottomLimit := y + height
+
+	// Lay out the elements. If they don't fit, make room for the scroll
+	// indicator.
+	positions, errorPositions, frames, contentHeight := f.layout(x, y, width)
+	scrollable := contentHeight > height && width > 1
+	if scrollable {
+		positions, errorPositions, frames, contentHeight = f.layout(x, y, width-1)
+	}
+
+	// Scroll such that the focused element (and its error message) is visible.
+	focused := -1
+	for index, item := range f.items {
+		if item.GetFocusable().HasFocus() {
+			focused = index
+		}
+	}
+	for index, button := range f.buttons {
+		if button.HasFocus() {
+			focused = len(f.items) + index
+		}
+	}
+	if focused >= 0 {
+		top := positions[focused].y - y
+		bottom := top + positions[focused].height
+		if focused < len(f.items) && errorPositions[focused].width > 0 {
+			if errorBottom := errorPositions[focused].y - y + 1; errorBottom > bottom {
+				bottom = errorBottom
+			}
+		}
+		if bottom > f.offset+height {
+			f.offset = bottom - height
+		}
+		if top < f.offset {
+			f.offset = top
+		}
+	}
+	if f.offset > contentHeight-height {
+		f.offset = contentHeight - height
+	}
+	if f.offset < 0 {
+		f.offset = 0
+	}
+	offset := f.offset
+
+	// clip returns the visible part of an element's rows.
+	clip := func(position formPosition) (int, int) {
+		top, bottom := position.y-offset, position.y-offset+position.height
+		if top < topLimit {
+			top = topLimit
+		}
+		if bottom > bottomLimit {
+			bottom = bottomLimit
+		}
+		return top, bottom - top
+	}
+
+	// Draw items.
+	for index, item := range f.items {
+		// Set position.
+		y, height := clip(positions[index])
+		item.SetRect(positions[index].x, y, positions[index].width, height)
+
+		// Is this item visible?
+		if height <= 0 || positions[index].width <= 0 {
+			continue
+		}
+
+		// Draw items with focus last (in case of overlaps).
+		if item.GetFocusable().HasFocus() {
+			defer item.Draw(screen)
+		} else {
+			item.Draw(screen)
+		}
+	}
+
+	// Draw the frames of bordered groups. (Their headers draw the top.)
+	for _, frame := range frames {
+		frameStyle := ubcell.StyleDefault.Background(f.backgroundColor).Foreground(frame.color)
+		top, bottom := frame.y-offset, frame.y-offset+frame.height-1
+		for row := top + 1; row <= bottom; row++ {
+			if row < topLimit || row >= bottomLimit {
+				continue
+			}
+			left, right := GraphicsVertBar, GraphicsVertBar
+			if row == bottom {
+				left, right = GraphicsBottomLeftCorner, GraphicsBottomRightCorner
+				for column := frame.x + 1; column < frame.x+frame.width-1; column++ {
+					screen.SetContent(column, row, GraphicsHoriBar, frameStyle)
+				}
+			}
+			screen.SetContent(frame.x, row, left, frameStyle)
+			screen.SetContent(frame.x+frame.width-1, row, right, frameStyle)
+		}
+	}
+
+	// Draw error messages.
+	for index, position := range errorPositions {
+		var err error
+		if index < len(f.items) {
+			err = f.errors[f.items[index]]
+		} else {
+			err = f.formError
+		}
+		y := position.y - offset
+		if err == nil || position.width <= 0 || y < topLimit || y >= bottomLimit {
+			continue
+		}
+		Print(screen, err.Error(), position.x, y, position.width, AlignLeft, f.errorColor)
+	}
+
+	// Draw buttons.
+	for index, button := range f.buttons {
+		// Set position.
+		buttonIndex := index + len(f.items)
+		y, height := clip(positions[buttonIndex])
+		button.SetRect(positions[buttonIndex].x, y, positions[buttonIndex].width, height)
+
+		// Is this button visible?
+		if height <= 0 || positions[buttonIndex].width <= 0 {
+			continue
+		}
+
+		// Draw button.
+		button.Draw(screen)
+	}
+
+	// Draw the scroll indicator.
+	if scrollable {
+		thumbHeight := height * height / contentHeight
+		if thumbHeight < 1 {
+			thumbHeight = 1
+		}
+		thumbY := offset * (height - thumbHeight) / (contentHeight - height)
+		style := ubcell.StyleDefault.Background(f.backgroundColor).Foreground(f.scrollIndicatorColor)
+		for row := 0; row < height; row++ {
+			ch := GraphicsVertBar
+			if row >= thumbY && row < thumbY+thumbHeight {
+				ch = '█'
+			}
+			screen.SetContent(x+width-1, y+row, ch, style)
+		}
+	}
+}
+
+// layout calculates the positions of the form's items and buttons (in this
+// order) as well as of the items' error messages and the form's error message
+// (the last one) when they are placed in an area of the given width. Hidden
+// items are given a width of 0. It also returns the frames drawn around the
+// items of bordered groups and the height needed to show everything.
+func (f *Form) layout(x, y, width int) (positions, errorPositions []formPosition, frames []formFrame, contentHeight int) {
+	topLimit := y
 	rightLimit := x + width
 	startX := x
 
-	// Find the longest label.
-	var maxLabelWidth int
-	for _, item := range f.items {
-		label := strings.TrimSpace(item.GetLabel())
-		labelWidth := StringWidth(label)
-		if labelWidth > maxLabelWidth {
-			maxLabelWidth = labelWidth
+	groups := f.itemGroups()
+	positions = make([]formPosition, len(f.items)+len(f.buttons))
+	errorPositions = make([]formPosition, len(f.items)+1) // The last one is the form's error.
+
+	// Find the longest label of each group. Only labels which start a row are
+	// aligned.
+	maxLabelWidths := make(map[*FormGroup]int)
+	for index, item := range f.items {
+		if _, isGroup := item.(*FormGroup); isGroup || f.isHidden(index, groups) || !f.horizontal && f.joinsRow(index) {
+			continue
+		}
+		labelWidth := StringWidth(strings.TrimSpace(item.GetLabel())) + 1 // Add one space.
+		if labelWidth > maxLabelWidths[groups[index]] {
+			maxLabelWidths[groups[index]] = labelWidth
 		}
 	}
-	maxLabelWidth++ // Add one space.
 
-	// Calculate positions of form items.
-	positions := make([]struct{ x, y, width, height int }, len(f.items)+len(f.buttons))
-	var focusedPosition struct{ x, y, width, height int }
-	for index, item := range f.items {
-		// Calculate the space needed.
-		label := strings.TrimSpace(item.GetLabel())
-		labelWidth := StringWidth(label)
-		var itemWidth int
-		if f.horizontal {
+	// placeError places the error message of the item with the given index
+	// beneath the item.
+	placeError := func(index int, labelWidth int) bool {
+		if f.errors[f.items[index]] == nil {
+			return false
+		}
+		position := positions[index]
+		errorPositions[index] = formPosition{x: position.x + labelWidth, y: position.y + position.height, width: position.width - labelWidth, height: 1}
+		return true
+	}
+
+	rowHeight := 1 // The height of the current row in horizontal layouts.
+	if f.horizontal {
+		// Calculate positions of form items, from left to right.
+		for index, item := range f.items {
+			if f.isHidden(index, groups) {
+				continue
+			}
+
+			// Calculate the space needed.
+			label := strings.TrimSpace(item.GetLabel())
+			labelWidth := StringWidth(label)
+			itemHeight := item.GetFieldHeight()
+			if itemHeight < 1 {
+				itemHeight = 1
+			}
 			fieldWidth := item.GetFieldWidth()
 			if fieldWidth == 0 {
 				fieldWidth = DefaultFormFieldWidth
 			}
-			label += " "
-			labelWidth++
-			itemWidth = labelWidth + fieldWidth
-		} else {
-			// We want all fields to align vertically.
-			label += strings.Repeat(" ", maxLabelWidth-labelWidth)
-			itemWidth = width
+			if labelWidth > 0 {
+				label += " "
+				labelWidth++
+			}
+			itemWidth := labelWidth + fieldWidth
+
+			// Advance to next line if there is no space.
+			if x > startX && x+labelWidth+1 >= rightLimit {
+				x = startX
+				y += rowHeight + 1
+				rowHeight = 1
+			}
+
+			// Adjust the item's attributes.
+			if x+itemWidth >= rightLimit {
+				itemWidth = rightLimit - x
+			}
+			item.SetFormAttributes(
+				label,
+				f.labelColor,
+				f.backgroundColor,
+				f.fieldTextColor,
+				f.fieldBackgroundColor,
+			)
+
+			// Save position. There is always an empty row beneath horizontal items
+			// for error messages.
+			positions[index] = formPosition{x: x, y: y, width: itemWidth, height: itemHeight}
+			placeError(index, 0)
+
+			// Advance to next item.
+			x += itemWidth + f.itemPadding
+			if itemHeight > rowHeight {
+				rowHeight = itemHeight
+			}
 		}
 
-		// Advance to next line if there is no space.
-		if f.horizontal && x+labelWidth+1 >= rightLimit {
-			x = startX
+		// Place the form's error message.
+		if f.formError != nil {
+			if x > startX {
+				x = startX
+				y += rowHeight + 1
+				rowHeight = 1
+			}
+			errorPositions[len(f.items)] = formPosition{x: x, y: y, width: rightLimit - x, height: 1}
 			y += 2
 		}
+	} else {
+		// Calculate positions of form items, from top to bottom, one row at a
+		// time. Bordered groups are indented.
+		areaX, areaWidth := x, width
+		var (
+			frameGroup *FormGroup // The group whose frame is open.
+			frameTop   int        // The row of the open frame's header.
+		)
 
-		// Adjust the item's attributes.
-		if x+itemWidth >= rightLimit {
-			itemWidth = rightLimit - x
+		// closeFrame adds the open frame, if any, after the last row.
+		closeFrame := func() {
+			if frameGroup == nil {
+				return
+			}
+			y -= f.itemPadding
+			frames = append(frames, formFrame{
+				formPosition: formPosition{x: startX, y: frameTop, width: width, height: y - frameTop + 1},
+				color:        frameGroup.borderColor,
+			})
+			y += 1 + f.itemPadding
+			x, areaX, areaWidth = startX, startX, width
+			frameGroup = nil
 		}
-		item.SetFormAttributes(
-			label,
-			f.labelColor,
-			f.backgroundColor,
-			f.fieldTextColor,
-			f.fieldBackgroundColor,
-		)
 
-		// Save position.
-		positions[index].x = x
-		positions[index].y = y
-		positions[index].width = itemWidth
-		positions[index].height = 1
-		if item.GetFocusable().HasFocus() {
-			focusedPosition = positions[index]
+		for index := 0; index < len(f.items); {
+			item := f.items[index]
+			if f.isHidden(index, groups) {
+				index++
+				continue
+			}
+
+			// Group headers span the entire width.
+			if group, ok := item.(*FormGroup); ok {
+				closeFrame()
+				group.SetFormAttributes("", f.labelColor, f.backgroundColor, f.fieldTextColor, f.fieldBackgroundColor)
+				positions[index] = formPosition{x: startX, y: y, width: width, height: 1}
+				y++
+				if group.bordered && !group.collapsed && width > 2 {
+					frameGroup, frameTop = group, y-1
+					x, areaX, areaWidth = startX+1, startX+1, width-2
+				} else {
+					y += f.itemPadding
+				}
+				if f.groupEnds[item] && frameGroup != nil {
+					y += f.itemPadding // An empty frame.
+					closeFrame()
+				}
+				index++
+				continue
+			}
+
+			// Collect the items of this row.
+			row := []int{index}
+			for next := index + 1; next < len(f.items); next++ {
+				if !f.joinsRow(next) || f.isHidden(next, groups) {
+					break
+				}
+				row = append(row, next)
+			}
+
+			// Calculate the widths of the row's items. Items with a flexible field
+			// width share the remaining space.
+			widths := make([]int, len(row))
+			labelWidths := make([]int, len(row))
+			remaining, flexible := areaWidth-(len(row)-1), 0
+			for rowIndex, itemIndex := range row {
+				rowItem := f.items[itemIndex]
+				labelWidth := StringWidth(strings.TrimSpace(rowItem.GetLabel())) + 1
+				if rowIndex == 0 {
+					labelWidth = maxLabelWidths[groups[itemIndex]]
+				}
+				labelWidths[rowIndex] = labelWidth
+				if fieldWidth := rowItem.GetFieldWidth(); fieldWidth > 0 && len(row) > 1 {
+					widths[rowIndex] = labelWidth + fieldWidth
+					remaining -= widths[rowIndex]
+				} else {
+					flexible++
+				}
+			}
+			for rowIndex := range row {
+				if widths[rowIndex] == 0 && flexible > 0 {
+					widths[rowIndex] = remaining / flexible
+					remaining -= widths[rowIndex]
+					flexible--
+				}
+			}
+
+			// Place the row's items.
+			rowHeight, errorRow := 1, false
+			x = areaX
+			for rowIndex, itemIndex := range row {
+				rowItem := f.items[itemIndex]
+				itemHeight := rowItem.GetFieldHeight()
+				if itemHeight < 1 {
+					itemHeight = 1
+				}
+				itemWidth := widths[rowIndex]
+				if x+itemWidth > areaX+areaWidth {
+					itemWidth = areaX + areaWidth - x
+				}
+				if itemWidth < 0 {
+					itemWidth = 0
+				}
+
+				// We want all fields to align vertically.
+				label := strings.TrimSpace(rowItem.GetLabel())
+				label += strings.Repeat(" ", labelWidths[rowIndex]-StringWidth(label))
+				rowItem.SetFormAttributes(
+					label,
+					f.labelColor,
+					f.backgroundColor,
+					f.fieldTextColor,
+					f.fieldBackgroundColor,
+				)
+				positions[itemIndex] = formPosition{x: x, y: y, width: itemWidth, height: itemHeight}
+				if itemHeight > rowHeight {
+					rowHeight = itemHeight
+				}
+
+				// Place the item's error message.
+				fieldWidth := rowItem.GetFieldWidth()
+				besideX := x + labelWidths[rowIndex] + fieldWidth + 1
+				rightEdge := areaX + areaWidth
+				if len(row) == 1 && f.errors[rowItem] != nil && f.errorsBeside && fieldWidth > 0 && besideX < rightEdge {
+					positions[itemIndex].width = besideX - 1 - x
+					errorPositions[itemIndex] = formPosition{x: besideX, y: y, width: rightEdge - besideX, height: 1}
+				} else if placeError(itemIndex, labelWidths[rowIndex]) {
+					errorPositions[itemIndex].y = y + rowHeight // Adjusted below.
+					errorRow = true
+				}
+
+				x += itemWidth + 1
+			}
+
+			// Error messages are placed beneath the entire row.
+			for _, itemIndex := range row {
+				if errorPositions[itemIndex].width > 0 && errorPositions[itemIndex].y > y {
+					errorPositions[itemIndex].y = y + rowHeight
+				}
+			}
+
+			// Advance to next row.
+			y += rowHeight + f.itemPadding
+			if errorRow {
+				y++
+			}
+			x = areaX
+			for _, itemIndex := range row {
+				if f.groupEnds[f.items[itemIndex]] {
+					closeFrame()
+				}
+			}
+			index = row[len(row)-1] + 1
 		}
+		closeFrame()
 
-		// Advance to next item.
-		if f.horizontal {
-			x += itemWidth + f.itemPadding
-		} else {
+		// Place the form's error message.
+		if f.formError != nil {
+			errorPositions[len(f.items)] = formPosition{x: x, y: y, width: rightLimit - x, height: 1}
 			y += 1 + f.itemPadding
 		}
 	}
@@ -365,7 +1017,8 @@ func (f *Form) Draw(screen ubcell.Screen) {
 		if f.horizontal {
 			if space < buttonWidth-4 {
 				x = startX
-				y += 2
+				y += rowHeight + 1
+				rowHeight = 1
 				space = width
 			}
 		} else {
@@ -376,69 +1029,82 @@ func (f *Form) Draw(screen ubcell.Screen) {
 		if buttonWidth > space {
 			buttonWidth = space
 		}
-		button.SetLabelColor(f.buttonTextColor).
+		labelColor := f.buttonTextColor
+		if f.submitButtons[button] && !f.IsValid() {
+			labelColor = f.errorColor
+		}
+		button.SetLabelColor(labelColor).
 			SetLabelColorActivated(f.buttonBackgroundColor).
 			SetBackgroundColorActivated(f.buttonTextColor).
 			SetBackgroundColor(f.buttonBackgroundColor)
 
-		buttonIndex := index + len(f.items)
-		positions[buttonIndex].x = x
-		positions[buttonIndex].y = y
-		positions[buttonIndex].width = buttonWidth
-		positions[buttonIndex].height = 1
-
-		if button.HasFocus() {
-			focusedPosition = positions[buttonIndex]
-		}
+		positions[index+len(f.items)] = formPosition{x: x, y: y, width: buttonWidth, height: 1}
 
 		x += buttonWidth + 1
 	}
 
-	// Determine vertical offset based on the position of the focused item.
-	var offset int
-	if focusedPosition.y+focusedPosition.height > bottomLimit {
-		offset = focusedPosition.y + focusedPosition.height - bottomLimit
-		if focusedPosition.y-offset < topLimit {
-			offset = focusedPosition.y - topLimit
+	// Determine the content height.
+	all := append(positions, errorPositions...)
+	for _, frame := range frames {
+		all = append(all, frame.formPosition)
+	}
+	for _, position := range all {
+		if position.width > 0 && position.y+position.height-topLimit > contentHeight {
+			contentHeight = position.y + position.height - topLimit
 		}
 	}
 
-	// Draw items.
-	for index, item := range f.items {
-		// Set position.
-		y := positions[index].y - offset
-		height := positions[index].height
-		item.SetRect(positions[index].x, y, positions[index].width, height)
+	return
+}
 
-		// Is this item visible?
-		if y+height <= topLimit || y >= bottomLimit {
-			continue
+// itemGroups returns the group each form item belongs to, nil for items which
+// don't belong to a group. Group headers belong to their own group.
+func (f *Form) itemGroups() []*FormGroup {
+	groups := make([]*FormGroup, len(f.items))
+	var current *FormGroup
+	for index, item := range f.items {
+		if group, ok := item.(*FormGroup); ok {
+			current = group
 		}
-
-		// Draw items with focus last (in case of overlaps).
-		if item.GetFocusable().HasFocus() {
-			defer item.Draw(screen)
-		} else {
-			item.Draw(screen)
+		groups[index] = current
+		if f.groupEnds[item] {
+			current = nil
 		}
 	}
+	return groups
+}
 
-	// Draw buttons.
-	for index, button := range f.buttons {
-		// Set position.
-		buttonIndex := index + len(f.items)
-		y := positions[buttonIndex].y - offset
-		height := positions[buttonIndex].height
-		button.SetRect(positions[buttonIndex].x, y, positions[buttonIndex].width, height)
+// isHidden returns whether or not the item with the given index is hidden
+// because it belongs to a collapsed group. "groups" is the result of
+// itemGroups().
+func (f *Form) isHidden(index int, groups []*FormGroup) bool {
+	group := groups[index]
+	return group != nil && group.collapsed && FormItem(group) != f.items[index]
+}
 
-		// Is this button visible?
-		if y+height <= topLimit || y >= bottomLimit {
-			continue
-		}
+// joinsRow returns whether or not the item with the given index is placed in
+// the same row as the item before it in vertical layouts (see Inline()).
+func (f *Form) joinsRow(index int) bool {
+	if index == 0 || !f.inline[f.items[index]] {
+		return false
+	}
+	if _, isGroup := f.items[index].(*FormGroup); isGroup {
+		return false
+	}
+	_, afterGroup := f.items[index-1].(*FormGroup)
+	return !afterGroup
+}
 
-		// Draw button.
-		button.Draw(screen)
+// isFocusable returns whether or not the form element (item or button) with the
+// given index can receive focus.
+func (f *Form) isFocusable(index int, groups []*FormGroup) bool {
+	if index >= len(f.items) {
+		return true // Buttons.
 	}
+	if group, ok := f.items[index].(*FormGroup); ok && !group.collapsible {
+		return false
+	}
+	return !f.isHidden(index, groups)
 }
 
 // Focus is called by the application when the primitive receives focus.
@@ -448,12 +1114,26 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 	}
 
 	// Hand on the focus to one of our child elements.
-	if f.focusedElement < 0 || f.focusedElement >= len(f.items)+len(f.buttons) {
+	count := len(f.items) + len(f.buttons)
+	if f.focusedElement < 0 || f.focusedElement >= count {
 		f.focusedElement = 0
 	}
+
+	// Skip hidden items and headers of groups which are not collapsible.
+	groups := f.itemGroups()
+	for skipped := 0; !f.isFocusable(f.focusedElement, groups); skipped++ {
+		if skipped == count {
+			return // Nothing can receive focus.
+		}
+		f.focusedElement = (f.focusedElement + 1) % count
+	}
+	f.delegate = delegate
 	handler := func(key *pixelgl.KeyEv) {
 		switch key.Key {
 		case pixelgl.KeyTab, pixelgl.KeyEnter:
+			if f.focusedElement < len(f.items) {
+				f.validateItem(f.focusedElement)
+			}
 			f.focusedElement++
 			f.Focus(delegate)
 		//case pixelgl.KeyBacktab: TODO
@@ -499,3 +1179,48 @@ func (f *Form) HasFocus() bool {
 	}
 	return false
 }
+
+// MouseHandler returns the mouse handler for this primitive. Clicking an item
+// or a button moves the focus to it.
+func (f *Form) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return f.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		if ev, ok := event.(*pixelgl.CursorEvent); ok {
+			x, y, width, height := f.GetInnerRect()
+			if ev.X < x || ev.X >= x+width || ev.Y < y || ev.Y >= y+height {
+				return // Items scrolled out of view.
+			}
+		}
+
+		// Find the element below the cursor.
+		groups := f.itemGroups()
+		target := -1
+		var primitive Primitive
+		for index := 0; index < len(f.items)+len(f.buttons); index++ {
+			if index < len(f.items) {
+				if f.isHidden(index, groups) {
+					continue
+				}
+				primitive = f.items[index]
+			} else {
+				primitive = f.buttons[index-len(f.items)]
+			}
+			if concernsMouse(primitive, event) {
+				target = index
+				break
+			}
+		}
+		if target < 0 {
+			return
+		}
+
+		// Clicks move the focus before the element handles them.
+		if _, _, ok := leftClick(event); ok && target != f.focusedElement && f.isFocusable(target, groups) {
+			if f.focusedElement < len(f.items) {
+				f.validateItem(f.focusedElement)
+			}
+			f.focusedElement = target
+			f.Focus(setFocus)
+		}
+		passMouse(primitive, event, setFocus)
+	})
+}
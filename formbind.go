@@ -0,0 +1,566 @@
+package tview
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// formBinding connects a form item to a struct field (see Form.Bind()).
+type formBinding struct {
+	// The name of the struct field.
+	name string
+
+	// The struct field.
+	field reflect.Value
+
+	// The form item which shows the field's value.
+	item FormItem
+
+	// The field's value when it was bound.
+	initial reflect.Value
+
+	// The value last copied between the field and the item.
+	synced interface{}
+
+	// Returns the item's value, converted to the field's type, or an error if
+	// it can't be converted.
+	get func() (reflect.Value, error)
+
+	// Shows the given value in the item.
+	set func(value reflect.Value)
+
+	// Checks the item's value against the rules from the "validate" tag.
+	check func(value reflect.Value) error
+}
+
+// durationType is the type of time.Duration fields.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// stringerType is the type of the fmt.Stringer interface.
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// Bind adds one form item for each exported field of the struct "ptr" points to
+// and keeps the items and the fields in sync: When the user changes an item,
+// the field is updated immediately (if the item's value can be converted to
+// the field's type). When the field is changed by the application, the item is
+// updated the next time the form is drawn.
+//
+// The following field types are supported, along with the items they are
+// shown with:
+//
+//   - string: InputField (DropDown with the "options" setting)
+//   - bool: Checkbox
+//   - int, int8, ..., uint64: InputField accepting integers
+//   - float32, float64: InputField accepting floating-point numbers
+//   - time.Duration: InputField accepting durations like "1h30m"
+//   - Integer types implementing fmt.Stringer (enums): DropDown with one option
+//     for each value from 0 upward whose String() is not of the form generated
+//     by the "stringer" tool for unknown values ("Type(5)"). The values end
+//     before the first one whose String() panics (e.g. an index out of range)
+//     or repeats a text (e.g. "Unknown" for all unknown values).
+//
+// The items can be configured with a "form" tag, a comma-separated list of the
+// following settings:
+//
+//   - label=<text>: The item's label. The field name is used by default.
+//   - width=<number>: The item's field width.
+//   - options=<a|b|c>: The options of a DropDown for string fields.
+//   - password: Masks the text of an InputField.
+//   - readonly: The item's value cannot be changed by the user.
+//
+// A "form" tag of "-" skips the field. Fields can be validated with a
+// "validate" tag, a comma-separated list of the following rules (see also
+// SetItemValidator()):
+//
+//   - required: The value must not be empty or zero.
+//   - min=<value>: The minimum value (minimum length for strings).
+//   - max=<value>: The maximum value (maximum length for strings).
+//   - regexp=<pattern>: A regular expression strings must match. This must be
+//     the last rule. It extends to the end of the tag.
+//
+// For example:
+//
+//   type Settings struct {
+//     Name    string        `form:"label=Your name,width=20" validate:"required,max=20"`
+//     Color   string        `form:"options=Red|Green|Blue"`
+//     Retries int           `validate:"min=0,max=10"`
+//     Timeout time.Duration `form:"width=10"`
+//     Secret  string        `form:"password"`
+//   }
+//
+// An error is returned if "ptr" is not a pointer to a struct or if a field has
+// an unsupported type or an invalid tag. No items are added in that case.
+func (f *Form) Bind(ptr interface{}) (*Form, error) {
+	value := reflect.ValueOf(ptr)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("Bind() requires a pointer to a struct")
+	}
+	value = value.Elem()
+
+	var bindings []*formBinding
+	for index := 0; index < value.NumField(); index++ {
+		field := value.Type().Field(index)
+		if field.PkgPath != "" {
+			continue // Unexported.
+		}
+		binding, err := newFormBinding(field, value.Field(index))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		if binding != nil {
+			bindings = append(bindings, binding)
+		}
+	}
+
+	for _, binding := range bindings {
+		binding := binding
+		f.AddFormItem(binding.item)
+		f.bindings = append(f.bindings, binding)
+		f.validators[binding.item] = func(item FormItem) error {
+			value, err := binding.get()
+			if err != nil {
+				return err
+			}
+			return binding.check(value)
+		}
+	}
+
+	return f, nil
+}
+
+// Values returns the current values of the items added with Bind(), keyed by
+// struct field name. Items whose values can't be converted to their field's
+// type are not included.
+func (f *Form) Values() map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, binding := range f.bindings {
+		if value, err := binding.get(); err == nil {
+			values[binding.name] = value.Interface()
+		}
+	}
+	return values
+}
+
+// Reset restores the values the struct fields had when Bind() was called, in
+// both the fields and the items, and removes all error messages.
+func (f *Form) Reset() *Form {
+	for _, binding := range f.bindings {
+		binding.field.Set(binding.initial)
+		binding.set(binding.initial)
+		binding.synced = binding.initial.Interface()
+	}
+	f.errors = make(map[FormItem]error)
+	f.formError = nil
+	return f
+}
+
+// syncBindings updates the items whose struct fields have changed since they
+// were last synced.
+func (f *Form) syncBindings() {
+	for _, binding := range f.bindings {
+		if value := binding.field.Interface(); !reflect.DeepEqual(value, binding.synced) {
+			binding.set(binding.field)
+			binding.synced = value
+		}
+	}
+}
+
+// newFormBinding creates the form item for a struct field. Nil is returned if
+// the field is to be skipped.
+func newFormBinding(field reflect.StructField, value reflect.Value) (*formBinding, error) {
+	tag := field.Tag.Get("form")
+	if tag == "-" {
+		return nil, nil
+	}
+
+	// Parse the "form" tag.
+	var (
+		label              = field.Name
+		width              int
+		options            []string
+		password, readonly bool
+	)
+	for _, setting := range strings.Split(tag, ",") {
+		name, argument := setting, ""
+		if pos := strings.Index(setting, "="); pos >= 0 {
+			name, argument = setting[:pos], setting[pos+1:]
+		}
+		switch strings.TrimSpace(name) {
+		case "":
+		case "label":
+			label = argument
+		case "width":
+			var err error
+			if width, err = strconv.Atoi(argument); err != nil {
+				return nil, fmt.Errorf("invalid width %q", argument)
+			}
+		case "options":
+			options = strings.Split(argument, "|")
+		case "password":
+			password = true
+		case "readonly":
+			readonly = true
+		default:
+			return nil, fmt.Errorf("unknown form setting %q", name)
+		}
+	}
+
+	b := &formBinding{
+		name:    field.Name,
+		field:   value,
+		initial: reflect.New(value.Type()).Elem(),
+		synced:  value.Interface(),
+	}
+	b.initial.Set(value)
+
+	// Create the item.
+	update := func() {
+		// Copy the item's value into the field.
+		if value, err := b.get(); err == nil {
+			b.field.Set(value)
+			b.synced = b.field.Interface()
+		}
+	}
+	typ := value.Type()
+	switch {
+	case typ.Kind() == reflect.Bool:
+		checkbox := NewCheckbox().SetLabel(label).SetChecked(value.Bool())
+		b.get = func() (reflect.Value, error) {
+			return reflect.ValueOf(checkbox.IsChecked()).Convert(typ), nil
+		}
+		b.set = func(value reflect.Value) {
+			checkbox.SetChecked(value.Bool())
+		}
+		checkbox.SetChangedFunc(func(bool) { update() })
+		b.item = checkbox
+
+	case isIntegerKind(typ.Kind()) && typ != durationType && typ.Implements(stringerType):
+		// An enum. (time.Duration implements fmt.Stringer, too, but is edited as
+		// text below.)
+		var values []reflect.Value
+		var texts []string
+		for number := 0; number < 256; number++ {
+			option := reflect.New(typ).Elem()
+			if isUnsignedKind(typ.Kind()) {
+				option.SetUint(uint64(number))
+			} else {
+				option.SetInt(int64(number))
+			}
+			text, ok := enumString(option)
+			if !ok {
+				break // String() panicked, e.g. with an index out of range.
+			}
+			if text == fmt.Sprintf("%s(%d)", typ.Name(), number) {
+				if len(values) > 0 {
+					break // The first unknown value after the known ones.
+				}
+				continue // Enums may start at 1.
+			}
+			if containsString(texts, text) {
+				// A text for all unknown values, e.g. "Unknown". If the previous
+				// value had it, too, it was unknown.
+				if texts[len(texts)-1] == text {
+					values, texts = values[:len(values)-1], texts[:len(texts)-1]
+				}
+				break
+			}
+			values = append(values, option)
+			texts = append(texts, text)
+		}
+		if len(values) == 0 {
+			return nil, errors.New("no enum values found")
+		}
+		dropDown := NewDropDown().SetLabel(label).SetFieldWidth(width)
+		b.get = func() (reflect.Value, error) {
+			index, _ := dropDown.GetCurrentOption()
+			if index < 0 || index >= len(values) {
+				return reflect.Value{}, errors.New("Please select an option")
+			}
+			return values[index], nil
+		}
+		b.set = func(value reflect.Value) {
+			current := -1
+			for index, option := range values {
+				if option.Interface() == value.Interface() {
+					current = index
+				}
+			}
+			dropDown.SetCurrentOption(current)
+		}
+		dropDown.SetOptions(texts, func(string, int) { update() })
+		b.set(value)
+		b.item = dropDown
+
+	case typ.Kind() == reflect.String && options != nil:
+		dropDown := NewDropDown().SetLabel(label).SetFieldWidth(width)
+		b.get = func() (reflect.Value, error) {
+			index, text := dropDown.GetCurrentOption()
+			if index < 0 {
+				return reflect.New(typ).Elem(), nil
+			}
+			return reflect.ValueOf(text).Convert(typ), nil
+		}
+		b.set = func(value reflect.Value) {
+			current := -1
+			for index, option := range options {
+				if option == value.String() {
+					current = index
+				}
+			}
+			dropDown.SetCurrentOption(current)
+		}
+		dropDown.SetOptions(options, func(string, int) { update() })
+		b.set(value)
+		b.item = dropDown
+
+	default:
+		// All other types are edited as text.
+		var (
+			accept func(text string, ch rune) bool
+			format func(value reflect.Value) string
+			parse  func(text string) (reflect.Value, error)
+		)
+		kind := typ.Kind()
+		bits := typ.Bits
+		switch {
+		case typ == durationType:
+			format = func(value reflect.Value) string {
+				return time.Duration(value.Int()).String()
+			}
+			parse = func(text string) (reflect.Value, error) {
+				duration, err := time.ParseDuration(text)
+				if err != nil {
+					return reflect.Value{}, errors.New("Please enter a duration, e.g. 1h30m")
+				}
+				return reflect.ValueOf(duration), nil
+			}
+		case kind == reflect.String:
+			format = func(value reflect.Value) string {
+				return value.String()
+			}
+			parse = func(text string) (reflect.Value, error) {
+				return reflect.ValueOf(text).Convert(typ), nil
+			}
+		case isUnsignedKind(kind):
+			accept = func(text string, ch rune) bool {
+				_, err := strconv.ParseUint(text, 10, 64)
+				return err == nil
+			}
+			format = func(value reflect.Value) string {
+				return strconv.FormatUint(value.Uint(), 10)
+			}
+			parse = func(text string) (reflect.Value, error) {
+				number, err := strconv.ParseUint(text, 10, bits())
+				if err != nil {
+					return reflect.Value{}, errors.New("Please enter a positive whole number")
+				}
+				return reflect.ValueOf(number).Convert(typ), nil
+			}
+		case isIntegerKind(kind):
+			accept = InputFieldInteger
+			format = func(value reflect.Value) string {
+				return strconv.FormatInt(value.Int(), 10)
+			}
+			parse = func(text string) (reflect.Value, error) {
+				number, err := strconv.ParseInt(text, 10, bits())
+				if err != nil {
+					return reflect.Value{}, errors.New("Please enter a whole number")
+				}
+				return reflect.ValueOf(number).Convert(typ), nil
+			}
+		case kind == reflect.Float32 || kind == reflect.Float64:
+			accept = InputFieldFloat
+			format = func(value reflect.Value) string {
+				return strconv.FormatFloat(value.Float(), 'g', -1, bits())
+			}
+			parse = func(text string) (reflect.Value, error) {
+				number, err := strconv.ParseFloat(text, bits())
+				if err != nil {
+					return reflect.Value{}, errors.New("Please enter a number")
+				}
+				return reflect.ValueOf(number).Convert(typ), nil
+			}
+		default:
+			return nil, fmt.Errorf("unsupported type %s", typ)
+		}
+
+		inputField := NewInputField().
+			SetLabel(label).
+			SetText(format(value)).
+			SetFieldWidth(width).
+			SetAcceptanceFunc(accept)
+		if password {
+			inputField.SetMaskCharacter('*')
+		}
+		b.get = func() (reflect.Value, error) {
+			return parse(inputField.GetText())
+		}
+		b.set = func(value reflect.Value) {
+			inputField.SetText(format(value))
+		}
+		inputField.SetChangedFunc(func(string) { update() })
+		b.item = inputField
+	}
+
+	if readonly {
+		switch item := b.item.(type) {
+		case *InputField:
+			item.SetReadOnly(true)
+		case *Checkbox:
+			item.SetReadOnly(true)
+		case *DropDown:
+			item.SetReadOnly(true)
+		}
+	}
+
+	// Parse the "validate" tag.
+	var err error
+	if b.check, err = parseFormRules(field.Tag.Get("validate"), typ); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// parseFormRules returns a function which checks values of the given type
+// against the rules of a "validate" tag (see Form.Bind()).
+func parseFormRules(tag string, typ reflect.Type) (func(value reflect.Value) error, error) {
+	var (
+		required bool
+		min, max *float64
+		pattern  *regexp.Regexp
+	)
+
+	// bound parses a "min" or "max" argument.
+	bound := func(argument string) (*float64, error) {
+		var number float64
+		var err error
+		if typ == durationType {
+			var duration time.Duration
+			duration, err = time.ParseDuration(argument)
+			number = float64(duration)
+		} else {
+			number, err = strconv.ParseFloat(argument, 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid bound %q", argument)
+		}
+		return &number, nil
+	}
+
+	for tag != "" {
+		rule := tag
+		if strings.HasPrefix(rule, "regexp=") {
+			tag = "" // Regular expressions extend to the end of the tag.
+		} else if pos := strings.Index(tag, ","); pos >= 0 {
+			rule, tag = tag[:pos], tag[pos+1:]
+		} else {
+			tag = ""
+		}
+		name, argument := rule, ""
+		if pos := strings.Index(rule, "="); pos >= 0 {
+			name, argument = rule[:pos], rule[pos+1:]
+		}
+
+		var err error
+		switch strings.TrimSpace(name) {
+		case "":
+		case "required":
+			required = true
+		case "min":
+			min, err = bound(argument)
+		case "max":
+			max, err = bound(argument)
+		case "regexp":
+			pattern, err = regexp.Compile(argument)
+		default:
+			err = fmt.Errorf("unknown validation rule %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(value reflect.Value) error {
+		if required && isZeroValue(value) {
+			return errors.New("Required")
+		}
+
+		// Determine the number which is compared to the bounds.
+		var number float64
+		unit := ""
+		switch kind := value.Kind(); {
+		case kind == reflect.String:
+			number, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+		case isUnsignedKind(kind):
+			number = float64(value.Uint())
+		case isIntegerKind(kind):
+			number = float64(value.Int())
+		case kind == reflect.Float32 || kind == reflect.Float64:
+			number = value.Float()
+		}
+
+		// formatBound formats a bound for an error message.
+		formatBound := func(bound float64) string {
+			if typ == durationType {
+				return time.Duration(bound).String()
+			}
+			return strconv.FormatFloat(bound, 'g', -1, 64) + unit
+		}
+		if min != nil && number < *min {
+			return fmt.Errorf("Must be at least %s", formatBound(*min))
+		}
+		if max != nil && number > *max {
+			return fmt.Errorf("Must be at most %s", formatBound(*max))
+		}
+
+		if pattern != nil && value.Kind() == reflect.String && !pattern.MatchString(value.String()) {
+			return errors.New("Invalid format")
+		}
+
+		return nil
+	}, nil
+}
+
+// isIntegerKind returns whether or not the given kind is a signed or unsigned
+// integer.
+func isIntegerKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uint64
+}
+
+// enumString returns the String() of an enum value. ok is false if String()
+// panicked.
+func enumString(value reflect.Value) (text string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			text, ok = "", false
+		}
+	}()
+	return value.Interface().(fmt.Stringer).String(), true
+}
+
+// containsString returns whether a string is in the given slice.
+func containsString(texts []string, text string) bool {
+	for _, t := range texts {
+		if t == text {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnsignedKind returns whether or not the given kind is an unsigned integer.
+func isUnsignedKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
+
+// isZeroValue returns whether or not the given value is its type's zero value.
+func isZeroValue(value reflect.Value) bool {
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
@@ -0,0 +1,183 @@
+package tview
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nowakf/pixel/pixelgl"
+)
+
+func TestParseFormRulesMalformed(t *testing.T) {
+	tests := []struct {
+		tag string
+		typ reflect.Type
+	}{
+		{"unknown", reflect.TypeOf("")},
+		{"required,unknown=1", reflect.TypeOf("")},
+		{"min", reflect.TypeOf(0)},
+		{"min=", reflect.TypeOf(0)},
+		{"min=abc", reflect.TypeOf(0)},
+		{"max=1,2", reflect.TypeOf(0)},
+		{"max=5s", reflect.TypeOf(0)},
+		{"min=5", durationType},
+		{"max=1x", durationType},
+		{"regexp=[a-", reflect.TypeOf("")},
+		{"regexp=(", reflect.TypeOf("")},
+		{"min=1,regexp=a(", reflect.TypeOf("")},
+	}
+	for _, test := range tests {
+		check, err := parseFormRules(test.tag, test.typ)
+		if err == nil {
+			t.Errorf("%q: expected an error", test.tag)
+		}
+		if check != nil {
+			t.Errorf("%q: expected no validation function", test.tag)
+		}
+	}
+}
+
+func TestParseFormRules(t *testing.T) {
+	tests := []struct {
+		tag     string
+		value   interface{}
+		invalid bool
+	}{
+		{"", "", false},
+		{",,", "", false},
+		{"required", "", true},
+		{"required", "a", false},
+		{" required ", "", true},
+		{"required", 0, true},
+		{"min=2,max=3", "a", true},
+		{"min=2,max=3", "abc", false},
+		{"min=2,max=3", "abcd", true},
+		{"min=2", "äö", false},
+		{"min=-1.5", -2.0, true},
+		{"min=-1.5", -1.5, false},
+		{"max=10", uint8(11), true},
+		{"min=1s,max=1m", 500 * time.Millisecond, true},
+		{"min=1s,max=1m", time.Second, false},
+		{"min=1s,max=1m", time.Hour, true},
+		{"regexp=^[a-z]+$", "abc", false},
+		{"regexp=^[a-z]+$", "ABC", true},
+		// Regular expressions extend to the end of the tag.
+		{"regexp=^a{1,2}$", "aa", false},
+		{"regexp=^a{1,2}$", "aaa", true},
+		{"required,regexp=^a,b$", "a,b", false},
+	}
+	for _, test := range tests {
+		value := reflect.ValueOf(test.value)
+		check, err := parseFormRules(test.tag, value.Type())
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.tag, err)
+			continue
+		}
+		if err := check(value); (err != nil) != test.invalid {
+			t.Errorf("%q with %v: got error %v, expected invalid %t", test.tag, test.value, err, test.invalid)
+		}
+	}
+}
+
+// testColor is an enum whose String() panics for unknown values.
+type testColor int
+
+func (c testColor) String() string {
+	return [...]string{"Red", "Green", "Blue"}[c]
+}
+
+func TestFormBind(t *testing.T) {
+	type settings struct {
+		Name    string
+		Retries int
+		Ratio   float64
+		Enabled bool
+		Timeout time.Duration
+		Color   testColor
+	}
+	s := settings{
+		Name:    "test",
+		Retries: 3,
+		Ratio:   0.5,
+		Enabled: true,
+		Timeout: 5 * time.Second,
+		Color:   testColor(1),
+	}
+	form, err := NewForm().Bind(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(form.items) != 6 {
+		t.Fatalf("got %d items", len(form.items))
+	}
+	name := form.GetFormItem(0).(*InputField)
+	retries := form.GetFormItem(1).(*InputField)
+	ratio := form.GetFormItem(2).(*InputField)
+	enabled := form.GetFormItem(3).(*Checkbox)
+	timeout, ok := form.GetFormItem(4).(*InputField)
+	if !ok {
+		t.Fatalf("time.Duration field is shown with a %T", form.GetFormItem(4))
+	}
+	color := form.GetFormItem(5).(*DropDown)
+
+	// The items show the initial values.
+	if name.GetText() != "test" || retries.GetText() != "3" || ratio.GetText() != "0.5" || !enabled.IsChecked() || timeout.GetText() != "5s" {
+		t.Errorf("items show %q, %q, %q, %t, %q", name.GetText(), retries.GetText(), ratio.GetText(), enabled.IsChecked(), timeout.GetText())
+	}
+	if index, text := color.GetCurrentOption(); index != 1 || text != "Green" || len(color.options) != 3 {
+		t.Errorf("enum shows option %d (%q) of %d", index, text, len(color.options))
+	}
+	values := form.Values()
+	expected := map[string]interface{}{
+		"Name":    "test",
+		"Retries": 3,
+		"Ratio":   0.5,
+		"Enabled": true,
+		"Timeout": 5 * time.Second,
+		"Color":   testColor(1),
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("initial values are %v", values)
+	}
+
+	// User changes are copied into the fields.
+	name.SetText("changed")
+	retries.SetText("-7")
+	ratio.SetText("1e3")
+	enabled.KeyHandler()(&pixelgl.KeyEv{Key: pixelgl.KeyEnter, Act: pixelgl.PRESS}, func(Primitive) {})
+	timeout.SetText("1h30m")
+	color.SetCurrentOption(2)
+	color.options[2].Selected()
+	if s != (settings{"changed", -7, 1000, false, 90 * time.Minute, testColor(2)}) {
+		t.Errorf("fields are %+v after editing", s)
+	}
+
+	// Invalid input leaves the fields unchanged.
+	retries.SetText("many")
+	timeout.SetText("soon")
+	if s.Retries != -7 || s.Timeout != 90*time.Minute {
+		t.Errorf("fields are %+v after invalid input", s)
+	}
+	if _, ok := form.Values()["Timeout"]; ok {
+		t.Error("invalid duration is included in the values")
+	}
+
+	// Changes of the fields are shown when the form is drawn.
+	s = settings{"synced", 12, 0.25, true, time.Millisecond, testColor(0)}
+	form.syncBindings()
+	if name.GetText() != "synced" || retries.GetText() != "12" || ratio.GetText() != "0.25" || !enabled.IsChecked() || timeout.GetText() != "1ms" {
+		t.Errorf("items show %q, %q, %q, %t, %q after syncing", name.GetText(), retries.GetText(), ratio.GetText(), enabled.IsChecked(), timeout.GetText())
+	}
+	if index, _ := color.GetCurrentOption(); index != 0 {
+		t.Errorf("enum shows option %d after syncing", index)
+	}
+
+	// Reset restores the initial values.
+	form.Reset()
+	if s != (settings{"test", 3, 0.5, true, 5 * time.Second, testColor(1)}) {
+		t.Errorf("fields are %+v after resetting", s)
+	}
+	if !reflect.DeepEqual(form.Values(), expected) {
+		t.Errorf("values are %v after resetting", form.Values())
+	}
+}
@@ -0,0 +1,217 @@
+package tview
+
+import (
+	"image/color"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// FormGroup is the header of a group of form items. It is added to a form with
+// Form.AddGroup() and all items added after it belong to the group, up to the
+// next call to Form.EndGroup() or Form.AddGroup().
+//
+// In vertical layouts, the header is shown as a titled separator line or, if
+// the group has a border, as the top of a frame drawn around the group's
+// items. Labels are aligned within each group.
+//
+// Collapsible groups can be collapsed and expanded by the user with the Enter
+// or the space key when the header has focus. The items of a collapsed group
+// are hidden and skipped when moving the focus through the form. Headers of
+// groups which are not collapsible don't receive focus.
+type FormGroup struct {
+	*Box
+
+	// The group's title.
+	title string
+
+	// The color of the title.
+	titleColor color.RGBA
+
+	// Whether or not a frame is drawn around the group's items.
+	bordered bool
+
+	// Whether or not the user can collapse the group.
+	collapsible bool
+
+	// Whether or not the group's items are hidden.
+	collapsed bool
+
+	// An optional function which is called when the group was collapsed or
+	// expanded by the user.
+	changed func(collapsed bool)
+
+	// An optional function which is called when the user leaves the header.
+	done func(*pixelgl.KeyEv)
+}
+
+// NewFormGroup returns a new group header with the given title.
+func NewFormGroup(title string) *FormGroup {
+	g := &FormGroup{
+		Box:        NewBox(),
+		title:      title,
+		titleColor: Styles.TitleColor,
+	}
+	g.borderColor = Styles.BorderColor
+	g.focus = g
+	return g
+}
+
+// SetTitle sets the group's title.
+func (g *FormGroup) SetTitle(title string) *FormGroup {
+	g.title = title
+	return g
+}
+
+// GetTitle returns the group's title.
+func (g *FormGroup) GetTitle() string {
+	return g.title
+}
+
+// SetTitleColor sets the color of the title.
+func (g *FormGroup) SetTitleColor(color color.RGBA) *FormGroup {
+	g.titleColor = color
+	return g
+}
+
+// SetBorder sets the flag indicating whether or not a frame is drawn around
+// the group's items in vertical layouts.
+func (g *FormGroup) SetBorder(show bool) *FormGroup {
+	g.bordered = show
+	return g
+}
+
+// SetCollapsible sets the flag indicating whether or not the user can collapse
+// and expand the group.
+func (g *FormGroup) SetCollapsible(collapsible bool) *FormGroup {
+	g.collapsible = collapsible
+	return g
+}
+
+// SetCollapsed collapses (true) or expands (false) the group. This also works
+// for groups which the user can't collapse.
+func (g *FormGroup) SetCollapsed(collapsed bool) *FormGroup {
+	g.collapsed = collapsed
+	return g
+}
+
+// IsCollapsed returns whether or not the group is collapsed.
+func (g *FormGroup) IsCollapsed() bool {
+	return g.collapsed
+}
+
+// SetChangedFunc sets a handler which is called when the group was collapsed
+// or expanded by the user. The handler receives the new state.
+func (g *FormGroup) SetChangedFunc(handler func(collapsed bool)) *FormGroup {
+	g.changed = handler
+	return g
+}
+
+// GetLabel returns an empty string as group headers have no label.
+func (g *FormGroup) GetLabel() string {
+	return ""
+}
+
+// SetFormAttributes sets attributes shared by all form items. Only the
+// background color applies to group headers.
+func (g *FormGroup) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor color.RGBA) FormItem {
+	g.backgroundColor = bgColor
+	return g
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (g *FormGroup) GetFieldWidth() int {
+	return StringWidth(g.title) + 4
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (g *FormGroup) GetFieldHeight() int {
+	return 1
+}
+
+// SetFinishedFunc sets a handler which is called when the user leaves the
+// header.
+func (g *FormGroup) SetFinishedFunc(handler func(key *pixelgl.KeyEv)) FormItem {
+	g.done = handler
+	return g
+}
+
+// Draw draws this primitive onto the screen.
+func (g *FormGroup) Draw(screen ubcell.Screen) {
+	g.Box.Draw(screen)
+
+	x, y, width, height := g.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	rightLimit := x + width
+	lineStyle := ubcell.StyleDefault.Background(g.backgroundColor).Foreground(g.borderColor)
+	frame := g.bordered && !g.collapsed
+
+	// Draw the left part of the line.
+	if frame {
+		screen.SetContent(x, y, GraphicsTopLeftCorner, lineStyle)
+		if x+1 < rightLimit {
+			screen.SetContent(x+1, y, GraphicsHoriBar, lineStyle)
+		}
+		x += 2
+	}
+
+	// Draw the title.
+	title := g.title
+	if g.collapsible {
+		if g.collapsed {
+			title = "▶ " + title
+		} else {
+			title = "▼ " + title
+		}
+	}
+	if title != "" && x < rightLimit {
+		_, drawnWidth := Print(screen, " "+title+" ", x, y, rightLimit-x, AlignLeft, g.titleColor)
+		if g.focus.HasFocus() {
+			// Invert the title's colors.
+			style := ubcell.StyleDefault.Background(g.titleColor).Foreground(g.backgroundColor)
+			for cx := x; cx < x+drawnWidth; cx++ {
+				ch, _ := screen.GetContent(cx, y)
+				screen.SetContent(cx, y, ch, style)
+			}
+		}
+		x += drawnWidth
+	}
+
+	// Draw the rest of the line.
+	end := rightLimit
+	if frame {
+		end--
+	}
+	for ; x < end; x++ {
+		screen.SetContent(x, y, GraphicsHoriBar, lineStyle)
+	}
+	if frame && x < rightLimit {
+		screen.SetContent(x, y, GraphicsTopRightCorner, lineStyle)
+	}
+}
+
+// KeyHandler returns the handler for this primitive.
+func (g *FormGroup) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return g.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.KeyEv)
+		if !ok {
+			return
+		}
+		switch key := ev.Key; key {
+		case pixelgl.KeyRune, pixelgl.KeyEnter: // Collapse or expand.
+			if key == pixelgl.KeyRune && ev.Ch != ' ' || !g.collapsible {
+				break
+			}
+			g.collapsed = !g.collapsed
+			if g.changed != nil {
+				g.changed(g.collapsed)
+			}
+		case pixelgl.KeyTab, pixelgl.KeyEscape: // We're done.
+			if g.done != nil {
+				g.done(ev)
+			}
+		}
+	})
+}
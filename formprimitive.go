@@ -0,0 +1,183 @@
+package tview
+
+import (
+	"image/color"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// FormPrimitive wraps any primitive, e.g. a List, a Table, or a TextView, so
+// that it can be added to a form as a FormItem. The label is shown to the left
+// of the primitive's first row, the primitive fills the field to the right of
+// it, spanning as many rows as the field height specifies.
+//
+// The wrapper keeps the focus itself and passes key events on to the wrapped
+// primitive, except for the Tab and Escape keys which move to the next form
+// element. The wrapped primitive keeps its own colors.
+type FormPrimitive struct {
+	*Box
+
+	// The wrapped primitive.
+	primitive Primitive
+
+	// The text to be displayed before the primitive.
+	label string
+
+	// The label color.
+	labelColor color.RGBA
+
+	// The screen width of the field. A value of 0 means extend as much as
+	// possible.
+	fieldWidth int
+
+	// The number of rows occupied by the field.
+	fieldHeight int
+
+	// An optional function which is called when the user leaves the item.
+	done func(*pixelgl.KeyEv)
+}
+
+// NewFormPrimitive returns a new form item wrapping the given primitive. Its
+// field is one row high.
+func NewFormPrimitive(p Primitive) *FormPrimitive {
+	f := &FormPrimitive{
+		Box:         NewBox(),
+		primitive:   p,
+		labelColor:  Styles.SecondaryTextColor,
+		fieldHeight: 1,
+	}
+	f.focus = f
+	return f
+}
+
+// GetPrimitive returns the wrapped primitive.
+func (f *FormPrimitive) GetPrimitive() Primitive {
+	return f.primitive
+}
+
+// SetLabel sets the text to be displayed before the primitive.
+func (f *FormPrimitive) SetLabel(label string) *FormPrimitive {
+	f.label = label
+	return f
+}
+
+// GetLabel returns the text to be displayed before the primitive.
+func (f *FormPrimitive) GetLabel() string {
+	return f.label
+}
+
+// SetLabelColor sets the color of the label.
+func (f *FormPrimitive) SetLabelColor(color color.RGBA) *FormPrimitive {
+	f.labelColor = color
+	return f
+}
+
+// SetFieldWidth sets the screen width of the field. A value of 0 means extend
+// as much as possible.
+func (f *FormPrimitive) SetFieldWidth(width int) *FormPrimitive {
+	f.fieldWidth = width
+	return f
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (f *FormPrimitive) GetFieldWidth() int {
+	return f.fieldWidth
+}
+
+// SetFieldHeight sets the number of rows occupied by the field.
+func (f *FormPrimitive) SetFieldHeight(height int) *FormPrimitive {
+	f.fieldHeight = height
+	return f
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (f *FormPrimitive) GetFieldHeight() int {
+	return f.fieldHeight
+}
+
+// SetFormAttributes sets attributes shared by all form items. The field colors
+// are ignored, the wrapped primitive keeps its own colors.
+func (f *FormPrimitive) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor color.RGBA) FormItem {
+	f.label = label
+	f.labelColor = labelColor
+	f.backgroundColor = bgColor
+	return f
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the item. The
+// callback function is provided with the key that was pressed, which is one of
+// the following:
+//
+//   - KeyEscape: Leave the item with no specific direction.
+//   - KeyTab: Move to the next field.
+func (f *FormPrimitive) SetDoneFunc(handler func(key *pixelgl.KeyEv)) *FormPrimitive {
+	f.done = handler
+	return f
+}
+
+// SetFinishedFunc calls SetDoneFunc().
+func (f *FormPrimitive) SetFinishedFunc(handler func(key *pixelgl.KeyEv)) FormItem {
+	return f.SetDoneFunc(handler)
+}
+
+// Draw draws this primitive onto the screen.
+func (f *FormPrimitive) Draw(screen ubcell.Screen) {
+	f.Box.Draw(screen)
+
+	// Prepare
+	x, y, width, height := f.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	_, drawnWidth := Print(screen, f.label, x, y, rightLimit-x, AlignLeft, f.labelColor)
+	x += drawnWidth
+
+	// Draw the primitive.
+	fieldWidth := f.fieldWidth
+	if fieldWidth == 0 || x+fieldWidth > rightLimit {
+		fieldWidth = rightLimit - x
+	}
+	if fieldWidth <= 0 {
+		return
+	}
+	f.primitive.SetRect(x, y, fieldWidth, height)
+	f.primitive.Draw(screen)
+}
+
+// Focus is called when this primitive receives focus.
+func (f *FormPrimitive) Focus(delegate func(p Primitive)) {
+	f.Box.Focus(delegate)
+	f.primitive.Focus(func(p Primitive) {})
+}
+
+// Blur is called when this primitive loses focus.
+func (f *FormPrimitive) Blur() {
+	f.Box.Blur()
+	f.primitive.Blur()
+}
+
+// KeyHandler returns the handler for this primitive.
+func (f *FormPrimitive) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return f.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		if ev, ok := event.(*pixelgl.KeyEv); ok && (ev.Key == pixelgl.KeyTab || ev.Key == pixelgl.KeyEscape) {
+			if f.done != nil {
+				f.done(ev)
+			}
+			return
+		}
+		if handler := f.primitive.KeyHandler(); handler != nil {
+			handler(event, setFocus)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *FormPrimitive) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return f.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		passMouse(f.primitive, event, setFocus)
+	})
+}
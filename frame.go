@@ -3,6 +3,7 @@ package tview
 import (
 	"image/color"
 
+	"github.com/nowakf/pixel/pixelgl"
 	"github.com/nowakf/ubcell"
 )
 
@@ -144,6 +145,11 @@ func (f *Frame) Draw(screen ubcell.Screen) {
 	f.primitive.Draw(screen)
 }
 
+// Detach passes the call on to the contained primitive.
+func (f *Frame) Detach() {
+	detach(f.primitive)
+}
+
 // Focus is called when this primitive receives focus.
 func (f *Frame) Focus(delegate func(p Primitive)) {
 	delegate(f.primitive)
@@ -157,3 +163,10 @@ func (f *Frame) HasFocus() bool {
 	}
 	return false
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *Frame) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return f.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		passMouse(f.primitive, event, setFocus)
+	})
+}
@@ -0,0 +1,287 @@
+package tview
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameSource provides the frames of an animation to a VideoPlayer. Frames are
+// requested by their index, starting at 0. The player requests frames from its
+// playback goroutine, one at a time, so implementations may block (e.g. while
+// waiting for a stream) but don't need to be safe for concurrent use.
+type FrameSource interface {
+	// FrameCount returns the number of frames or -1 if it isn't known in
+	// advance, e.g. for streams.
+	FrameCount() int
+
+	// Frame returns the frame with the given index and the time it is displayed.
+	// A duration of 0 causes the player's default delay to be used (see
+	// VideoPlayer.SetDelay()). io.EOF is returned for indices past the last
+	// frame. Sources which can't go back (e.g. streams) return an error for
+	// indices lower than the last one requested.
+	Frame(index int) (image.Image, time.Duration, error)
+}
+
+// loopCounter is implemented by frame sources whose files define how often the
+// animation is repeated.
+type loopCounter interface {
+	// LoopCount returns the number of times the animation is restarted, using
+	// the semantics of gif.GIF.LoopCount (0 loops forever, -1 plays once).
+	LoopCount() int
+}
+
+// ImageSource is a FrameSource which plays images held in memory, e.g. frames
+// which were generated procedurally.
+type ImageSource struct {
+	frames []image.Image
+	delay  time.Duration
+}
+
+// NewImageSource returns a frame source for the given images, played at the
+// given number of frames per second. If fps is 0, the player's default delay
+// is used.
+func NewImageSource(frames []image.Image, fps float64) *ImageSource {
+	return &ImageSource{frames: frames, delay: fpsDelay(fps)}
+}
+
+// FrameCount returns the number of images.
+func (s *ImageSource) FrameCount() int {
+	return len(s.frames)
+}
+
+// Frame returns the image with the given index.
+func (s *ImageSource) Frame(index int) (image.Image, time.Duration, error) {
+	if index < 0 || index >= len(s.frames) {
+		return nil, 0, io.EOF
+	}
+	return s.frames[index], s.delay, nil
+}
+
+// GIFSource is a FrameSource which plays an animated GIF, honoring its frame
+// delays, disposal methods, and loop count.
+type GIFSource struct {
+	frames    []image.Image
+	delays    []time.Duration
+	loopCount int
+}
+
+// NewGIFSource returns a frame source for the given GIF. The frames are
+// composed (applying each frame's disposal method) when this function is
+// called.
+func NewGIFSource(sequence *gif.GIF) *GIFSource {
+	bounds := image.Rect(0, 0, sequence.Config.Width, sequence.Config.Height)
+	for _, frame := range sequence.Image {
+		bounds = bounds.Union(frame.Bounds())
+	}
+
+	s := &GIFSource{
+		frames:    make([]image.Image, len(sequence.Image)),
+		delays:    make([]time.Duration, len(sequence.Image)),
+		loopCount: sequence.LoopCount,
+	}
+	canvas := image.NewRGBA(bounds)
+	for index, frame := range sequence.Image {
+		disposal := byte(gif.DisposalNone)
+		if index < len(sequence.Disposal) {
+			disposal = sequence.Disposal[index]
+		}
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = copyRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		s.frames[index] = copyRGBA(canvas)
+		if index < len(sequence.Delay) {
+			s.delays[index] = time.Duration(sequence.Delay[index]) * 10 * time.Millisecond
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.ZP, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return s
+}
+
+// FrameCount returns the number of frames of the GIF.
+func (s *GIFSource) FrameCount() int {
+	return len(s.frames)
+}
+
+// Frame returns the composed frame with the given index.
+func (s *GIFSource) Frame(index int) (image.Image, time.Duration, error) {
+	if index < 0 || index >= len(s.frames) {
+		return nil, 0, io.EOF
+	}
+	return s.frames[index], s.delays[index], nil
+}
+
+// LoopCount returns the GIF's loop count.
+func (s *GIFSource) LoopCount() int {
+	return s.loopCount
+}
+
+// SequenceSource is a FrameSource which plays a directory of numbered PNG or
+// JPEG files, e.g. "frame1.png", "frame2.png", ..., "frame10.png". Files are
+// ordered by the last number in their name, then by name. They are decoded
+// when they are shown.
+type SequenceSource struct {
+	paths []string
+	delay time.Duration
+}
+
+// NewSequenceSource returns a frame source for the PNG and JPEG files in the
+// given directory, played at the given number of frames per second. If fps is
+// 0, the player's default delay is used.
+func NewSequenceSource(dir string, fps float64) (*SequenceSource, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SequenceSource{delay: fpsDelay(fps)}
+	for _, info := range files {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".png", ".jpg", ".jpeg":
+			if !info.IsDir() {
+				s.paths = append(s.paths, filepath.Join(dir, info.Name()))
+			}
+		}
+	}
+	if len(s.paths) == 0 {
+		return nil, errors.New("no PNG or JPEG files found")
+	}
+
+	// frameNumber returns the last number in a file name, -1 if there is none.
+	frameNumber := func(path string) int {
+		name := filepath.Base(path)
+		end := strings.LastIndexAny(name, "0123456789")
+		if end < 0 {
+			return -1
+		}
+		start := end
+		for start > 0 && name[start-1] >= '0' && name[start-1] <= '9' {
+			start--
+		}
+		number, err := strconv.Atoi(name[start : end+1])
+		if err != nil {
+			return -1
+		}
+		return number
+	}
+	sort.SliceStable(s.paths, func(i, j int) bool {
+		ni, nj := frameNumber(s.paths[i]), frameNumber(s.paths[j])
+		if ni != nj {
+			return ni < nj
+		}
+		return s.paths[i] < s.paths[j]
+	})
+
+	return s, nil
+}
+
+// FrameCount returns the number of files.
+func (s *SequenceSource) FrameCount() int {
+	return len(s.paths)
+}
+
+// Frame decodes the file with the given index.
+func (s *SequenceSource) Frame(index int) (image.Image, time.Duration, error) {
+	if index < 0 || index >= len(s.paths) {
+		return nil, 0, io.EOF
+	}
+	file, err := os.Open(s.paths[index])
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	return img, s.delay, nil
+}
+
+// RawSource is a FrameSource which reads uncompressed frames from a stream,
+// e.g. the output of an external decoder ("ffmpeg -f rawvideo -pix_fmt rgba").
+// Each frame consists of width*height pixels, row by row, each pixel made up
+// of four bytes: red, green, blue, and alpha (not premultiplied).
+//
+// The stream is read as the frames are requested so it can't be sought
+// backwards and can't be looped.
+type RawSource struct {
+	reader        io.Reader
+	width, height int
+	delay         time.Duration
+
+	// The index of the last frame read and the frame itself.
+	index int
+	frame *image.NRGBA
+}
+
+// NewRawSource returns a frame source which reads frames of the given size from
+// the given reader, played at the given number of frames per second. If fps is
+// 0, the player's default delay is used.
+func NewRawSource(reader io.Reader, width, height int, fps float64) *RawSource {
+	return &RawSource{
+		reader: reader,
+		width:  width,
+		height: height,
+		delay:  fpsDelay(fps),
+		index:  -1,
+	}
+}
+
+// FrameCount returns -1 as the length of a stream is not known.
+func (s *RawSource) FrameCount() int {
+	return -1
+}
+
+// Frame reads frames from the stream until the frame with the given index was
+// read. The last frame can be requested repeatedly.
+func (s *RawSource) Frame(index int) (image.Image, time.Duration, error) {
+	if index < s.index || index < 0 {
+		return nil, 0, errors.New("cannot seek backwards in a stream")
+	}
+	for s.index < index {
+		frame := image.NewNRGBA(image.Rect(0, 0, s.width, s.height))
+		if _, err := io.ReadFull(s.reader, frame.Pix); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return nil, 0, err
+		}
+		s.frame = frame
+		s.index++
+	}
+	return s.frame, s.delay, nil
+}
+
+// fpsDelay converts a frame rate into a frame duration, 0 for a rate of 0.
+func fpsDelay(fps float64) time.Duration {
+	if fps <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / fps)
+}
+
+// copyRGBA returns a copy of the given image.
+func copyRGBA(img *image.RGBA) *image.RGBA {
+	c := image.NewRGBA(img.Bounds())
+	copy(c.Pix, img.Pix)
+	return c
+}
@@ -3,6 +3,7 @@ package tview
 import (
 	"image/color"
 	"math"
+	"strings"
 
 	"github.com/nowakf/pixel/pixelgl"
 	"github.com/nowakf/ubcell"
@@ -15,11 +16,25 @@ type gridItem struct {
 	Width, Height               int       // The number of rows and columns the item occupies.
 	MinGridWidth, MinGridHeight int       // The minimum grid width/height for which this item is visible.
 	Focus                       bool      // Whether or not this item attracts the layout's focus.
+	Area                        string    // The named area the item is placed in, if any. Overrides the position above.
 
 	visible    bool // Whether or not this item was visible the last time the grid was drawn.
 	x, y, w, h int  // The last position of the item relative to the top-left corner of the grid. Undefined if visible is false.
 }
 
+// gridArea is the rectangle of grid cells covered by a named area.
+type gridArea struct {
+	Row, Column, Height, Width int
+}
+
+// gridBreakpoint is a layout which applies when the grid has at least a
+// minimum size. See Grid.SetBreakpoint().
+type gridBreakpoint struct {
+	MinGridWidth, MinGridHeight int
+	rows, columns               []int               // nil if the grid's values are used.
+	areas                       map[string]gridArea // nil if the grid's areas are used.
+}
+
 // Grid is an implementation of a grid-based layout. It works by defining the
 // size of the rows and columns, then placing primitives into the grid.
 //
@@ -29,6 +44,12 @@ type gridItem struct {
 // and "l" keys) while the grid has focus and none of its contained primitives
 // do.
 //
+// Primitives can be placed by row and column indexes (see AddItem()) or into
+// named areas described by a template (see SetAreas() and AddAreaItem()). The
+// rows, columns, and areas may be redefined for larger grid sizes with
+// SetBreakpoint(), e.g. to move a sidebar below the main content when the grid
+// is narrow.
+//
 // See https://github.com/rivo/tview/wiki/Grid for an example.
 type Grid struct {
 	*Box
@@ -40,6 +61,12 @@ type Grid struct {
 	// SetRows()/SetColumns() for details.
 	rows, columns []int
 
+	// The named areas defined with SetAreas().
+	areas map[string]gridArea
+
+	// Alternative layouts for larger grid sizes, see SetBreakpoint().
+	breakpoints []*gridBreakpoint
+
 	// The minimum sizes for rows and columns.
 	minWidth, minHeight int
 
@@ -129,6 +156,124 @@ func (g *Grid) SetSize(numRows, numColumns, rowSize, columnSize int) *Grid {
 	return g
 }
 
+// SetAreas defines named areas in which primitives can be placed with
+// AddAreaItem(), similar to CSS grid template areas. Each string describes one
+// row of the grid and consists of area names separated by whitespace, one per
+// column. The name "." marks a cell which does not belong to any area. Every
+// area must form a rectangle. Example:
+//
+//   grid.SetRows(1, 0, 1).
+//     SetColumns(20, 0).
+//     SetAreas(
+//       "header  header",
+//       "sidebar content",
+//       "footer  footer").
+//     AddAreaItem(menu, "sidebar", false).
+//     AddAreaItem(text, "content", true)
+//
+// The areas do not change the sizes of the rows and columns, which are still
+// defined with SetRows() and SetColumns(). Panics if the rows have different
+// numbers of columns or if an area is not rectangular.
+func (g *Grid) SetAreas(template ...string) *Grid {
+	g.areas = parseGridAreas(template)
+	return g
+}
+
+// SetBreakpoint defines a layout which replaces the one defined with
+// SetRows(), SetColumns(), and SetAreas() when the grid is at least
+// minGridHeight cells high and minGridWidth cells wide. If rows or columns is
+// nil, the values of SetRows() or SetColumns() are used. If no areas are
+// provided, those of SetAreas() are used. See SetAreas() for the template
+// syntax.
+//
+// If multiple breakpoints apply, the one that has at least one highest minimum
+// value will be used, or the breakpoint defined last if those values are the
+// same. Defining a breakpoint with the same minimum values again replaces it.
+// The following example shows a sidebar below the content in narrow grids and
+// left of it in grids which are at least 80 cells wide:
+//
+//   grid.SetRows(0, 10).
+//     SetAreas(
+//       "content",
+//       "sidebar").
+//     SetBreakpoint(0, 80, []int{0}, []int{20, 0},
+//       "sidebar content").
+//     AddAreaItem(menu, "sidebar", false).
+//     AddAreaItem(text, "content", true)
+//
+// Panics if the template is invalid.
+func (g *Grid) SetBreakpoint(minGridHeight, minGridWidth int, rows, columns []int, areas ...string) *Grid {
+	breakpoint := &gridBreakpoint{
+		MinGridWidth:  minGridWidth,
+		MinGridHeight: minGridHeight,
+		rows:          rows,
+		columns:       columns,
+	}
+	if len(areas) > 0 {
+		breakpoint.areas = parseGridAreas(areas)
+	}
+	for index, b := range g.breakpoints {
+		if b.MinGridWidth == minGridWidth && b.MinGridHeight == minGridHeight {
+			g.breakpoints = append(g.breakpoints[:index], g.breakpoints[index+1:]...)
+			break
+		}
+	}
+	g.breakpoints = append(g.breakpoints, breakpoint)
+	return g
+}
+
+// RemoveBreakpoint removes the breakpoint with the given minimum values.
+func (g *Grid) RemoveBreakpoint(minGridHeight, minGridWidth int) *Grid {
+	for index, b := range g.breakpoints {
+		if b.MinGridWidth == minGridWidth && b.MinGridHeight == minGridHeight {
+			g.breakpoints = append(g.breakpoints[:index], g.breakpoints[index+1:]...)
+			break
+		}
+	}
+	return g
+}
+
+// parseGridAreas returns the areas described by the given template. See
+// Grid.SetAreas() for the syntax. Panics if the template is invalid.
+func parseGridAreas(template []string) map[string]gridArea {
+	areas := make(map[string]gridArea)
+	cells := make(map[string]int)
+	columns := -1
+	for row, line := range template {
+		names := strings.Fields(line)
+		if columns >= 0 && len(names) != columns {
+			panic("Grid area rows have different numbers of columns")
+		}
+		columns = len(names)
+		for column, name := range names {
+			if name == "." {
+				continue
+			}
+			cells[name]++
+			area, ok := areas[name]
+			if !ok {
+				areas[name] = gridArea{Row: row, Column: column, Height: 1, Width: 1}
+				continue
+			}
+			if column < area.Column {
+				area.Width += area.Column - column
+				area.Column = column
+			}
+			if column >= area.Column+area.Width {
+				area.Width = column - area.Column + 1
+			}
+			area.Height = row - area.Row + 1
+			areas[name] = area
+		}
+	}
+	for name, area := range areas {
+		if cells[name] != area.Width*area.Height {
+			panic("Grid area " + name + " is not rectangular")
+		}
+	}
+	return areas
+}
+
 // SetMinSize sets an absolute minimum width for rows and an absolute minimum
 // height for columns. Panics if negative values are provided.
 func (g *Grid) SetMinSize(row, column int) *Grid {
@@ -204,12 +349,69 @@ func (g *Grid) AddItem(p Primitive, row, column, height, width, minGridHeight, m
 	return g
 }
 
+// AddAreaItem adds a primitive to the grid, placing it in the named area of
+// the current layout (see SetAreas() and SetBreakpoint()). If the layout which
+// applies to the grid's current size has no such area, the primitive is not
+// drawn.
+//
+// A primitive may be added both to an area and at numeric positions with
+// AddItem(). The rules described there decide which of the positions is used,
+// an area item counting as an item with minimum grid sizes of 0.
+//
+// If focus is set to true, the item will receive focus when the grid receives
+// focus, see AddItem().
+func (g *Grid) AddAreaItem(p Primitive, area string, focus bool) *Grid {
+	g.items = append(g.items, &gridItem{
+		Item:  p,
+		Area:  area,
+		Focus: focus,
+	})
+	return g
+}
+
+// gridDominates returns whether the minimum grid sizes of one layout take
+// precedence over those of another, even if the other one was added later.
+// This is the case if none of the first values is lower and at least one is
+// higher.
+func gridDominates(width, height, otherWidth, otherHeight int) bool {
+	return width >= otherWidth && height >= otherHeight && (width > otherWidth || height > otherHeight)
+}
+
+// breakpoint returns the breakpoint which applies to a grid of the given size,
+// or nil if there is none. Of the breakpoints whose minimum sizes are met, it
+// is the one defined last which no other one dominates.
+func (g *Grid) breakpoint(width, height int) *gridBreakpoint {
+	applies := func(b *gridBreakpoint) bool {
+		return width >= b.MinGridWidth && height >= b.MinGridHeight
+	}
+	for index := len(g.breakpoints) - 1; index >= 0; index-- {
+		b := g.breakpoints[index]
+		if !applies(b) {
+			continue
+		}
+		dominated := false
+		for _, other := range g.breakpoints {
+			if applies(other) && gridDominates(other.MinGridWidth, other.MinGridHeight, b.MinGridWidth, b.MinGridHeight) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			return b
+		}
+	}
+	return nil
+}
+
 // RemoveItem removes all items for the given primitive from the grid, keeping
 // the order of the remaining items intact.
 func (g *Grid) RemoveItem(p Primitive) *Grid {
 	for index := len(g.items) - 1; index >= 0; index-- {
 		if g.items[index].Item == p {
 			g.items = append(g.items[:index], g.items[index+1:]...)
+			if p != nil {
+				detach(p)
+			}
 		}
 	}
 	return g
@@ -217,10 +419,20 @@ func (g *Grid) RemoveItem(p Primitive) *Grid {
 
 // Clear removes all items from the grid.
 func (g *Grid) Clear() *Grid {
+	g.Detach()
 	g.items = nil
 	return g
 }
 
+// Detach passes the call on to all contained primitives.
+func (g *Grid) Detach() {
+	for _, item := range g.items {
+		if item.Item != nil {
+			detach(item.Item)
+		}
+	}
+}
+
 // SetOffset sets the number of rows and columns which are skipped before
 // drawing the first grid cell in the top-left corner. As the grid will never
 // completely move off the screen, these values may be adjusted the next time
@@ -239,6 +451,13 @@ func (g *Grid) GetOffset() (rows, columns int) {
 
 // Focus is called when this primitive receives focus.
 func (g *Grid) Focus(delegate func(p Primitive)) {
+	// Prefer items which are visible at the current grid size.
+	for index := len(g.items) - 1; index >= 0; index-- {
+		if item := g.items[index]; item.Focus && item.visible {
+			delegate(item.Item)
+			return
+		}
+	}
 	for _, item := range g.items {
 		if item.Focus {
 			delegate(item.Item)
@@ -302,29 +521,68 @@ func (g *Grid) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)
 	})
 }
 
+// MouseHandler returns the mouse handler for this primitive.
+func (g *Grid) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return g.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		for _, item := range g.items {
+			if item.visible && passMouse(item.Item, event, setFocus) {
+				return
+			}
+		}
+	})
+}
+
 // Draw draws this primitive onto the screen.
 func (g *Grid) Draw(screen ubcell.Screen) {
 	g.Box.Draw(screen)
 
 	x, y, width, height := g.GetInnerRect()
 
+	// Which layout applies?
+	rowSizes, columnSizes, areas := g.rows, g.columns, g.areas
+	if breakpoint := g.breakpoint(width, height); breakpoint != nil {
+		if breakpoint.rows != nil {
+			rowSizes = breakpoint.rows
+		}
+		if breakpoint.columns != nil {
+			columnSizes = breakpoint.columns
+		}
+		if breakpoint.areas != nil {
+			areas = breakpoint.areas
+		}
+	}
+
 	// Make a list of items which apply.
 	items := make(map[Primitive]*gridItem)
 	for _, item := range g.items {
 		item.visible = false
-		if item.Width <= 0 || item.Height <= 0 || width < item.MinGridWidth || height < item.MinGridHeight {
+		if width < item.MinGridWidth || height < item.MinGridHeight {
 			continue
 		}
+		if item.Area != "" {
+			area := areas[item.Area] // Not drawn if the area doesn't exist.
+			item.Row, item.Column, item.Height, item.Width = area.Row, area.Column, area.Height, area.Width
+		}
 		previousItem, ok := items[item.Item]
-		if ok && item.Width < previousItem.Width && item.Height < previousItem.Height {
+		if ok && gridDominates(previousItem.MinGridWidth, previousItem.MinGridHeight, item.MinGridWidth, item.MinGridHeight) {
 			continue
 		}
 		items[item.Item] = item
 	}
+	for primitive, item := range items {
+		if item.Width <= 0 || item.Height <= 0 {
+			delete(items, primitive)
+		}
+	}
+	for _, item := range g.items {
+		if _, ok := items[item.Item]; !ok && item.Item != nil {
+			detach(item.Item) // Hidden at this size.
+		}
+	}
 
 	// How many rows and columns do we have?
-	rows := len(g.rows)
-	columns := len(g.columns)
+	rows := len(rowSizes)
+	columns := len(columnSizes)
 	for _, item := range items {
 		rowEnd := item.Row + item.Height
 		if rowEnd > rows {
@@ -350,7 +608,7 @@ func (g *Grid) Draw(screen ubcell.Screen) {
 	remainingHeight := height
 	proportionalWidth := 0
 	proportionalHeight := 0
-	for index, row := range g.rows {
+	for index, row := range rowSizes {
 		if row > 0 {
 			if row < g.minHeight {
 				row = g.minHeight
@@ -363,7 +621,7 @@ func (g *Grid) Draw(screen ubcell.Screen) {
 			proportionalHeight += -row
 		}
 	}
-	for index, column := range g.columns {
+	for index, column := range columnSizes {
 		if column > 0 {
 			if column < g.minWidth {
 				column = g.minWidth
@@ -383,11 +641,11 @@ func (g *Grid) Draw(screen ubcell.Screen) {
 		remainingHeight -= (rows - 1) * g.gapRows
 		remainingWidth -= (columns - 1) * g.gapColumns
 	}
-	if rows > len(g.rows) {
-		proportionalHeight += rows - len(g.rows)
+	if rows > len(rowSizes) {
+		proportionalHeight += rows - len(rowSizes)
 	}
-	if columns > len(g.columns) {
-		proportionalWidth += columns - len(g.columns)
+	if columns > len(columnSizes) {
+		proportionalWidth += columns - len(columnSizes)
 	}
 
 	// Distribute proportional rows/columns.
@@ -395,8 +653,8 @@ func (g *Grid) Draw(screen ubcell.Screen) {
 	gridHeight := 0
 	for index := 0; index < rows; index++ {
 		row := 0
-		if index < len(g.rows) {
-			row = g.rows[index]
+		if index < len(rowSizes) {
+			row = rowSizes[index]
 		}
 		if row > 0 {
 			if row < g.minHeight {
@@ -420,8 +678,8 @@ func (g *Grid) Draw(screen ubcell.Screen) {
 	}
 	for index := 0; index < columns; index++ {
 		column := 0
-		if index < len(g.columns) {
-			column = g.columns[index]
+		if index < len(columnSizes) {
+			column = columnSizes[index]
 		}
 		if column > 0 {
 			if column < g.minWidth {
@@ -0,0 +1,189 @@
+package tview
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGridAreas(t *testing.T) {
+	tests := []struct {
+		name     string
+		template []string
+		areas    map[string]gridArea
+		panics   bool
+	}{
+		{
+			name:     "empty",
+			template: nil,
+			areas:    map[string]gridArea{},
+		},
+		{
+			name: "rectangles",
+			template: []string{
+				"header  header",
+				"sidebar content",
+				"sidebar content",
+			},
+			areas: map[string]gridArea{
+				"header":  {Row: 0, Column: 0, Height: 1, Width: 2},
+				"sidebar": {Row: 1, Column: 0, Height: 2, Width: 1},
+				"content": {Row: 1, Column: 1, Height: 2, Width: 1},
+			},
+		},
+		{
+			name: "empty cells",
+			template: []string{
+				". a",
+				". a",
+			},
+			areas: map[string]gridArea{
+				"a": {Row: 0, Column: 1, Height: 2, Width: 1},
+			},
+		},
+		{
+			name: "extra whitespace",
+			template: []string{
+				"  a   b ",
+				"a\tb",
+			},
+			areas: map[string]gridArea{
+				"a": {Row: 0, Column: 0, Height: 2, Width: 1},
+				"b": {Row: 0, Column: 1, Height: 2, Width: 1},
+			},
+		},
+		{
+			name: "L shape",
+			template: []string{
+				"a a",
+				"a b",
+			},
+			panics: true,
+		},
+		{
+			name: "hole",
+			template: []string{
+				"a a a",
+				"a . a",
+				"a a a",
+			},
+			panics: true,
+		},
+		{
+			name: "disconnected",
+			template: []string{
+				"a b a",
+			},
+			panics: true,
+		},
+		{
+			name: "growing to the left",
+			template: []string{
+				". a",
+				"a a",
+			},
+			panics: true,
+		},
+		{
+			name: "ragged rows",
+			template: []string{
+				"a b",
+				"a",
+			},
+			panics: true,
+		},
+		{
+			name: "ragged empty row",
+			template: []string{
+				"a",
+				"",
+			},
+			panics: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				recovered := recover()
+				if test.panics && recovered == nil {
+					t.Error("expected a panic")
+				} else if !test.panics && recovered != nil {
+					t.Errorf("unexpected panic: %v", recovered)
+				}
+			}()
+			areas := parseGridAreas(test.template)
+			if !test.panics && !reflect.DeepEqual(areas, test.areas) {
+				t.Errorf("got %v, expected %v", areas, test.areas)
+			}
+		})
+	}
+}
+
+func TestGridDominates(t *testing.T) {
+	tests := []struct {
+		width, height, otherWidth, otherHeight int
+		dominates                              bool
+	}{
+		{0, 0, 0, 0, false},
+		{80, 0, 80, 0, false},
+		{80, 0, 0, 0, true},
+		{0, 0, 80, 0, false},
+		{80, 20, 80, 0, true},
+		{80, 20, 100, 0, false},
+		{100, 0, 80, 20, false},
+	}
+	for _, test := range tests {
+		if dominates := gridDominates(test.width, test.height, test.otherWidth, test.otherHeight); dominates != test.dominates {
+			t.Errorf("gridDominates(%d, %d, %d, %d) = %t, expected %t", test.width, test.height, test.otherWidth, test.otherHeight, dominates, test.dominates)
+		}
+	}
+}
+
+func TestGridBreakpoint(t *testing.T) {
+	grid := NewGrid().
+		SetBreakpoint(0, 80, []int{1}, nil).
+		SetBreakpoint(20, 0, []int{2}, nil).
+		SetBreakpoint(20, 80, []int{3}, nil).
+		SetBreakpoint(0, 120, []int{4}, nil).
+		SetBreakpoint(0, 40, []int{5}, nil)
+
+	tests := []struct {
+		width, height int
+		rows          []int // The rows of the expected breakpoint, nil for none.
+	}{
+		{30, 10, nil},
+		{40, 10, []int{5}},
+		{80, 10, []int{1}},
+		{30, 20, []int{2}},
+		{80, 20, []int{3}},
+		{120, 10, []int{4}},
+		// Neither {20, 80} nor {0, 120} dominates, so the one defined last
+		// applies.
+		{120, 20, []int{4}},
+	}
+	for _, test := range tests {
+		breakpoint := grid.breakpoint(test.width, test.height)
+		var rows []int
+		if breakpoint != nil {
+			rows = breakpoint.rows
+		}
+		if !reflect.DeepEqual(rows, test.rows) {
+			t.Errorf("breakpoint(%d, %d) has rows %v, expected %v", test.width, test.height, rows, test.rows)
+		}
+	}
+
+	// Defining a breakpoint with the same minimum values again replaces it.
+	grid.SetBreakpoint(0, 80, []int{6}, nil)
+	if breakpoint := grid.breakpoint(80, 10); breakpoint == nil || !reflect.DeepEqual(breakpoint.rows, []int{6}) {
+		t.Errorf("replaced breakpoint does not apply")
+	}
+
+	// The selection does not depend on the order of comparisons: {0, 50} is
+	// dominated by {0, 100}, which leaves {0, 100} and {20, 0}.
+	grid = NewGrid().
+		SetBreakpoint(0, 100, []int{1}, nil).
+		SetBreakpoint(20, 0, []int{2}, nil).
+		SetBreakpoint(0, 50, []int{3}, nil)
+	if breakpoint := grid.breakpoint(120, 30); breakpoint == nil || !reflect.DeepEqual(breakpoint.rows, []int{2}) {
+		t.Errorf("breakpoint(120, 30) is %v, expected the one with rows [2]", breakpoint)
+	}
+}
@@ -0,0 +1,173 @@
+package tview
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// History is a list of previously entered lines, e.g. the commands of a
+// console, which can be recalled in an InputField (see
+// InputField.SetHistory()). The most recent entry comes last.
+//
+// Empty lines are not recorded. When a line is added which is already in the
+// history, the older entry is removed. When the history exceeds its maximum
+// size, the oldest entries are dropped.
+//
+// A history may be shared by several input fields. It can be saved to and
+// loaded from a file, with one entry per line.
+type History struct {
+	sync.Mutex
+
+	// The entries, oldest first.
+	entries []string
+
+	// The maximum number of entries. 0 means no limit.
+	maxSize int
+}
+
+// NewHistory returns a new, empty history which keeps at most the given number
+// of entries. A maximum size of 0 means no limit.
+func NewHistory(maxSize int) *History {
+	return &History{maxSize: maxSize}
+}
+
+// Add appends a line to the history.
+func (h *History) Add(line string) *History {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" || strings.ContainsAny(line, "\r\n") {
+		return h
+	}
+	h.Lock()
+	defer h.Unlock()
+	for index, entry := range h.entries {
+		if entry == line {
+			h.entries = append(h.entries[:index], h.entries[index+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, line)
+	h.truncate()
+	return h
+}
+
+// truncate drops the oldest entries beyond the maximum size. The lock must be
+// held.
+func (h *History) truncate() {
+	if h.maxSize > 0 && len(h.entries) > h.maxSize {
+		h.entries = append([]string(nil), h.entries[len(h.entries)-h.maxSize:]...)
+	}
+}
+
+// SetMaxSize sets the maximum number of entries. 0 means no limit.
+func (h *History) SetMaxSize(maxSize int) *History {
+	h.Lock()
+	defer h.Unlock()
+	h.maxSize = maxSize
+	h.truncate()
+	return h
+}
+
+// GetEntries returns a copy of the entries, oldest first.
+func (h *History) GetEntries() []string {
+	h.Lock()
+	defer h.Unlock()
+	return append([]string(nil), h.entries...)
+}
+
+// GetEntryCount returns the number of entries.
+func (h *History) GetEntryCount() int {
+	h.Lock()
+	defer h.Unlock()
+	return len(h.entries)
+}
+
+// entry returns the entry with the given index or false if there is none.
+func (h *History) entry(index int) (string, bool) {
+	h.Lock()
+	defer h.Unlock()
+	if index < 0 || index >= len(h.entries) {
+		return "", false
+	}
+	return h.entries[index], true
+}
+
+// search returns the index of the newest entry at or before the given index
+// which contains the given text, or -1 if there is none.
+func (h *History) search(text string, index int) int {
+	h.Lock()
+	defer h.Unlock()
+	if index >= len(h.entries) {
+		index = len(h.entries) - 1
+	}
+	for ; index >= 0; index-- {
+		if strings.Contains(h.entries[index], text) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Clear removes all entries.
+func (h *History) Clear() *History {
+	h.Lock()
+	defer h.Unlock()
+	h.entries = nil
+	return h
+}
+
+// ReadFrom adds the lines read from the given reader to the history, as if
+// they had been added with Add().
+func (h *History) ReadFrom(reader io.Reader) (int64, error) {
+	var read int64
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		read += int64(len(scanner.Bytes())) + 1
+		h.Add(scanner.Text())
+	}
+	return read, scanner.Err()
+}
+
+// WriteTo writes the entries to the given writer, one per line, oldest first.
+func (h *History) WriteTo(writer io.Writer) (int64, error) {
+	var written int64
+	for _, entry := range h.GetEntries() {
+		n, err := io.WriteString(writer, entry+"\n")
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Load adds the lines of the file with the given name to the history. A file
+// which does not exist is treated as empty.
+func (h *History) Load(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = h.ReadFrom(file)
+	return err
+}
+
+// Save writes the history to the file with the given name, replacing its
+// contents.
+func (h *History) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := h.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,120 @@
+package tview
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHistoryAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int
+		lines   []string
+		entries []string
+	}{
+		{
+			name:    "order",
+			lines:   []string{"a", "b", "c"},
+			entries: []string{"a", "b", "c"},
+		},
+		{
+			name:    "duplicates",
+			lines:   []string{"a", "b", "a", "c", "a"},
+			entries: []string{"b", "c", "a"},
+		},
+		{
+			name:    "repeated",
+			lines:   []string{"a", "a", "a"},
+			entries: []string{"a"},
+		},
+		{
+			name:    "empty and multi-line",
+			lines:   []string{"", "a", "\n", "b\nc", "d\r\n"},
+			entries: []string{"a", "d"},
+		},
+		{
+			name:    "line break duplicate",
+			lines:   []string{"a", "b", "a\n"},
+			entries: []string{"b", "a"},
+		},
+		{
+			name:    "maximum size",
+			maxSize: 2,
+			lines:   []string{"a", "b", "c"},
+			entries: []string{"b", "c"},
+		},
+		{
+			name:    "maximum size with duplicates",
+			maxSize: 2,
+			lines:   []string{"a", "b", "a", "b", "a"},
+			entries: []string{"b", "a"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			history := NewHistory(test.maxSize)
+			for _, line := range test.lines {
+				history.Add(line)
+			}
+			if entries := history.GetEntries(); !reflect.DeepEqual(entries, test.entries) {
+				t.Errorf("got %q, expected %q", entries, test.entries)
+			}
+		})
+	}
+}
+
+func TestHistorySetMaxSize(t *testing.T) {
+	history := NewHistory(0).Add("a").Add("b").Add("c")
+	history.SetMaxSize(1)
+	if entries := history.GetEntries(); !reflect.DeepEqual(entries, []string{"c"}) {
+		t.Errorf("got %q after shrinking", entries)
+	}
+	history.SetMaxSize(0).Add("d")
+	if entries := history.GetEntries(); !reflect.DeepEqual(entries, []string{"c", "d"}) {
+		t.Errorf("got %q after removing the limit", entries)
+	}
+}
+
+func TestHistoryReadWrite(t *testing.T) {
+	history := NewHistory(0)
+	if _, err := history.ReadFrom(strings.NewReader("a\nb\n\na\nc")); err != nil {
+		t.Fatal(err)
+	}
+	if entries := history.GetEntries(); !reflect.DeepEqual(entries, []string{"b", "a", "c"}) {
+		t.Errorf("read %q", entries)
+	}
+	var buffer strings.Builder
+	if _, err := history.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if written := buffer.String(); written != "b\na\nc\n" {
+		t.Errorf("wrote %q", written)
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	history := NewHistory(0).Add("git status").Add("ls").Add("git log").Add("make")
+	tests := []struct {
+		text         string
+		index, found int
+	}{
+		{"git", 3, 2},
+		{"git", 2, 2},
+		{"git", 1, 0},
+		{"git", 0, 0},
+		{"git", 100, 2}, // Indices beyond the newest entry start at the newest.
+		{"", 3, 3},
+		{"make", 2, -1}, // The search does not wrap around to newer entries.
+		{"git", -1, -1},
+		{"missing", 3, -1},
+	}
+	for _, test := range tests {
+		if found := history.search(test.text, test.index); found != test.found {
+			t.Errorf("search(%q, %d) = %d, expected %d", test.text, test.index, found, test.found)
+		}
+	}
+	if found := NewHistory(0).search("", 0); found != -1 {
+		t.Errorf("found %d in an empty history", found)
+	}
+}
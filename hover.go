@@ -0,0 +1,253 @@
+package tview
+
+import (
+	"time"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// The cursor hovers over a primitive when a cursor event within the
+// primitive's rectangle reaches its mouse handler. As containers pass cursor
+// events on to the child below the cursor, the cursor hovers over a chain of
+// primitives, from the root to the innermost one. When the chain changes, the
+// primitives which left it and those which joined it are notified, see
+// Box.SetHoverFunc().
+
+// TooltipDelay is the time the mouse cursor must rest on a primitive before its
+// tooltip is shown, see Box.SetTooltip().
+var TooltipDelay = 700 * time.Millisecond
+
+// startHover starts collecting the boxes which receive a cursor event.
+func (a *Application) startHover() {
+	a.Lock()
+	defer a.Unlock()
+	a.hoverTracking = true
+	a.hoverTrail = nil
+}
+
+// trackHover records that the cursor is at the given screen cell within the
+// given box.
+func (a *Application) trackHover(b *Box, x, y int) {
+	a.Lock()
+	defer a.Unlock()
+	if !a.hoverTracking {
+		return
+	}
+	b.hoverX, b.hoverY = x, y
+	a.hoverTrail = append(a.hoverTrail, b)
+}
+
+// finishHover stops collecting boxes and returns the ones which received the
+// cursor event.
+func (a *Application) finishHover() []*Box {
+	a.Lock()
+	defer a.Unlock()
+	a.hoverTracking = false
+	trail := a.hoverTrail
+	a.hoverTrail = nil
+	return trail
+}
+
+// SetHoverFunc sets a handler which is called when the mouse cursor enters
+// (entered is true) or leaves (entered is false) the box. It is called from the
+// application's event loop.
+func (b *Box) SetHoverFunc(handler func(entered bool)) *Box {
+	b.hover = handler
+	return b
+}
+
+// IsHovered returns whether the mouse cursor is over the box.
+func (b *Box) IsHovered() bool {
+	return b.hovered
+}
+
+// SetTooltip sets a text which is shown in a small popup near the mouse cursor
+// after it rests on the box for a while (see TooltipDelay). The text may
+// contain color tags and is wrapped if it is long. If a primitive contained in
+// the box has a tooltip, too, the innermost one is shown. An empty text removes
+// the tooltip.
+func (b *Box) SetTooltip(text string) *Box {
+	b.tooltip = text
+	return b
+}
+
+// GetTooltip returns the text set with SetTooltip().
+func (b *Box) GetTooltip() string {
+	return b.tooltip
+}
+
+// appTooltip is the tooltip which is pending or shown by an application.
+type appTooltip struct {
+	// The box whose tooltip is pending or shown.
+	box *Box
+
+	// The screen cell of the cursor when the tooltip appeared.
+	x, y int
+
+	// Whether or not the tooltip is shown.
+	visible bool
+
+	// Whether or not a click hid the tooltip. It stays hidden until the
+	// cursor leaves the box.
+	suppressed bool
+
+	// The timer which shows the tooltip.
+	timer *time.Timer
+}
+
+// updateHover notifies the boxes the cursor entered or left, given the boxes
+// which received the latest cursor event, and schedules their tooltip.
+func (a *Application) updateHover(trail []*Box, ev *pixelgl.CursorEvent) {
+	a.Lock()
+	previous := a.hovered
+	a.hovered = trail
+	a.Unlock()
+
+	for _, b := range previous {
+		if !containsBox(trail, b) {
+			b.hovered = false
+			if b.hover != nil {
+				b.hover(false)
+			}
+		}
+	}
+	for _, b := range trail {
+		if !b.hovered {
+			b.hovered = true
+			if b.hover != nil {
+				b.hover(true)
+			}
+		}
+	}
+
+	// The innermost tooltip applies.
+	var box *Box
+	for index := len(trail) - 1; index >= 0; index-- {
+		if trail[index].tooltip != "" {
+			box = trail[index]
+			break
+		}
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	tooltip := &a.tooltip
+	switch {
+	case box != tooltip.box:
+		a.stopTooltip()
+		tooltip.box = box
+		if box != nil {
+			a.startTooltip()
+		}
+	case box == nil || tooltip.suppressed:
+		// There is nothing to show.
+	case ev.Act == pixelgl.PRESS:
+		// Clicks hide the tooltip.
+		a.stopTooltip()
+		tooltip.suppressed = true
+	case !tooltip.visible:
+		// The cursor must rest before the tooltip appears.
+		a.stopTooltip()
+		a.startTooltip()
+	}
+}
+
+// startTooltip starts the timer which shows the tooltip of the box below the
+// cursor. The application must be locked.
+func (a *Application) startTooltip() {
+	var timer *time.Timer
+	timer = time.AfterFunc(TooltipDelay, func() {
+		a.QueueUpdateDraw(func() {
+			a.Lock()
+			defer a.Unlock()
+			if a.tooltip.timer == timer && a.tooltip.box != nil {
+				a.tooltip.visible = true
+				a.tooltip.x, a.tooltip.y = a.tooltip.box.hoverX, a.tooltip.box.hoverY
+			}
+		})
+	})
+	a.tooltip.timer = timer
+}
+
+// stopTooltip hides the tooltip and stops its timer. The application must be
+// locked.
+func (a *Application) stopTooltip() {
+	if a.tooltip.timer != nil {
+		a.tooltip.timer.Stop()
+		a.tooltip.timer = nil
+	}
+	a.tooltip.visible = false
+	a.tooltip.suppressed = false
+}
+
+// hideTooltip hides the tooltip until the cursor leaves the box, e.g. when the
+// user presses a key.
+func (a *Application) hideTooltip() {
+	a.Lock()
+	defer a.Unlock()
+	if a.tooltip.box != nil {
+		a.stopTooltip()
+		a.tooltip.suppressed = true
+	}
+}
+
+// drawTooltip draws the visible tooltip below and to the right of the cursor,
+// moving it to stay on the screen.
+func (a *Application) drawTooltip(screen ubcell.Screen) {
+	a.RLock()
+	tooltip := a.tooltip
+	a.RUnlock()
+	if !tooltip.visible || tooltip.box == nil || tooltip.box.tooltip == "" {
+		return
+	}
+
+	screenWidth, screenHeight := screen.Size()
+	maxWidth := 40
+	if maxWidth > screenWidth-2 {
+		maxWidth = screenWidth - 2
+	}
+	if maxWidth <= 0 {
+		return
+	}
+	lines := WordWrap(tooltip.box.tooltip, maxWidth)
+	var width int
+	for _, line := range lines {
+		if lineWidth := StringWidth(line); lineWidth > width {
+			width = lineWidth
+		}
+	}
+	width += 2
+	height := len(lines)
+
+	x, y := tooltip.x+1, tooltip.y+1
+	if x+width > screenWidth {
+		x = screenWidth - width
+	}
+	if y+height > screenHeight {
+		y = tooltip.y - height // Show it above the cursor.
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+
+	box := NewBox().SetBackgroundColor(Styles.ContrastBackgroundColor).SetBorderPadding(0, 0, 1, 1)
+	box.SetRect(x, y, width, height)
+	box.Draw(screen)
+	for row, line := range lines {
+		Print(screen, line, x+1, y+row, width-2, AlignLeft, Styles.PrimaryTextColor)
+	}
+}
+
+// containsBox returns whether a box is in the given slice.
+func containsBox(boxes []*Box, b *Box) bool {
+	for _, box := range boxes {
+		if box == b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,263 @@
+package tview
+
+import (
+	"image"
+	"image/color"
+	_ "image/jpeg" // Images may be loaded from JPEG files.
+	_ "image/png"  // Images may be loaded from PNG files.
+	"math"
+	"os"
+
+	"github.com/nowakf/pixel"
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// Image scaling modes.
+const (
+	ImageFit     = iota // Scale to fit into the box, keeping the aspect ratio.
+	ImageFill           // Scale to fill the box, keeping the aspect ratio and cropping the image.
+	ImageStretch        // Scale to the box's size, ignoring the aspect ratio.
+	ImageCenter         // Don't scale, center the image and crop it if necessary.
+)
+
+// Image is a box which displays a raster image within its inner rect. The
+// image is drawn into the window as a sprite, at the point in time when the
+// image's Draw() function is called. Primitives which are drawn later (e.g. a
+// Modal on a later page) will therefore appear on top of it while primitives
+// which were drawn before will be covered.
+//
+// If the screen cannot draw sprites (i.e. it does not render into a window) or
+// if text mode is enabled with SetTextMode(), the image is drawn with
+// half-block characters instead, two pixels per cell.
+type Image struct {
+	*Box
+
+	// The image to be displayed.
+	image image.Image
+
+	// The image as a sprite. This is nil if the image has changed and the sprite
+	// needs to be recreated.
+	picture *pixel.PictureData
+	sprite  *pixel.Sprite
+
+	// One of the Image scaling constants.
+	scaling int
+
+	// A color which is multiplied with the image's colors.
+	mask color.RGBA
+
+	// If set to true, the image is always drawn with half-block characters.
+	textMode bool
+}
+
+// NewImage returns a new image primitive without an image.
+func NewImage() *Image {
+	return &Image{
+		Box:  NewBox(),
+		mask: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	}
+}
+
+// SetImage sets the image to be displayed. Provide nil to remove the image.
+func (i *Image) SetImage(img image.Image) *Image {
+	i.image = img
+	i.picture, i.sprite = nil, nil
+	return i
+}
+
+// GetImage returns the image which is displayed.
+func (i *Image) GetImage() image.Image {
+	return i.image
+}
+
+// Load reads a PNG or JPEG file and displays it.
+func (i *Image) Load(path string) (*Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.SetImage(img), nil
+}
+
+// SetScaling sets how the image is scaled within the box's inner rect, one of
+// ImageFit (the default), ImageFill, ImageStretch, or ImageCenter.
+func (i *Image) SetScaling(scaling int) *Image {
+	i.scaling = scaling
+	return i
+}
+
+// SetMask sets a color which is multiplied with the colors of the image, e.g.
+// to tint it or make it translucent. The default is opaque white which leaves
+// the image unchanged.
+//
+// The mask is alpha-premultiplied, like the colors of the image, i.e. its red,
+// green, and blue values should not exceed its alpha value. For example, the
+// mask which draws the image half-transparent is {0x80, 0x80, 0x80, 0x80}, not
+// {0xff, 0xff, 0xff, 0x80}.
+func (i *Image) SetMask(m color.RGBA) *Image {
+	i.mask = m
+	return i
+}
+
+// SetTextMode sets the flag which, when true, causes the image to be drawn with
+// half-block characters even if the screen could draw it as a sprite.
+func (i *Image) SetTextMode(textMode bool) *Image {
+	i.textMode = textMode
+	return i
+}
+
+// Draw draws this primitive onto the screen.
+func (i *Image) Draw(screen ubcell.Screen) {
+	i.Box.Draw(screen)
+
+	x, y, width, height := i.GetInnerRect()
+	if i.image == nil || width <= 0 || height <= 0 {
+		return
+	}
+
+	cell := screenCellSize(screen)
+	if i.textMode || cell.X == 0 || cell.Y == 0 {
+		i.drawText(screen, x, y, width, height)
+		return
+	}
+
+	if i.sprite == nil {
+		i.picture = pixel.PictureDataFromImage(i.image)
+		i.sprite = pixel.NewSprite(i.picture, i.picture.Bounds())
+	}
+	bounds := i.picture.Bounds()
+	src, dst := imageLayout(i.scaling, bounds.W(), bounds.H(), float64(width)*cell.X, float64(height)*cell.Y)
+
+	// Picture coordinates start at the bottom.
+	frame := pixel.R(
+		bounds.Min.X+src.Min.X,
+		bounds.Max.Y-src.Max.Y,
+		bounds.Min.X+src.Max.X,
+		bounds.Max.Y-src.Min.Y,
+	)
+	picture, sprite, mask := i.picture, i.sprite, i.mask
+	matrix := pixel.IM.
+		ScaledXY(pixel.ZV, pixel.V(dst.W()/src.W(), dst.H()/src.H())).
+		Chained(screen.GetMatrix(x, y, width, height))
+	screen.Call(func(win *pixelgl.Window) {
+		sprite.Set(picture, frame)
+		sprite.DrawColorMask(win, matrix, mask)
+	})
+}
+
+// drawText draws the image with half-block characters into the given cells.
+func (i *Image) drawText(screen ubcell.Screen, x, y, width, height int) {
+	bounds := i.image.Bounds()
+	src, dst := imageLayout(i.scaling, float64(bounds.Dx()), float64(bounds.Dy()), float64(width), float64(2*height))
+	scaleX, scaleY := src.W()/dst.W(), src.H()/dst.H()
+
+	// pixelAt returns the color of the image at the given half-block pixel and
+	// whether or not the image covers it.
+	pixelAt := func(px, py int) (color.RGBA, bool) {
+		fx, fy := float64(px)+0.5, float64(py)+0.5
+		if fx < dst.Min.X || fx >= dst.Max.X || fy < dst.Min.Y || fy >= dst.Max.Y {
+			return color.RGBA{}, false
+		}
+		sx := bounds.Min.X + int(src.Min.X+(fx-dst.Min.X)*scaleX)
+		sy := bounds.Min.Y + int(src.Min.Y+(fy-dst.Min.Y)*scaleY)
+		col := color.RGBAModel.Convert(i.image.At(sx, sy)).(color.RGBA)
+		col = multiplyColors(col, i.mask)
+		if col.A == 0 {
+			return color.RGBA{}, false
+		}
+		return blendColors(i.backgroundColor, col), true
+	}
+
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			top, hasTop := pixelAt(cx, 2*cy)
+			bottom, hasBottom := pixelAt(cx, 2*cy+1)
+			switch {
+			case hasTop && hasBottom:
+				screen.SetContent(x+cx, y+cy, '▀', ubcell.StyleDefault.Background(bottom).Foreground(top))
+			case hasTop:
+				screen.SetContent(x+cx, y+cy, '▀', ubcell.StyleDefault.Background(i.backgroundColor).Foreground(top))
+			case hasBottom:
+				screen.SetContent(x+cx, y+cy, '▄', ubcell.StyleDefault.Background(i.backgroundColor).Foreground(bottom))
+			}
+		}
+	}
+}
+
+// screenCellSize returns the size of one screen cell in window pixels. A zero
+// size is returned if the screen doesn't render into a window.
+func screenCellSize(screen ubcell.Screen) pixel.Vec {
+	origin := screen.GetMatrix(0, 0, 1, 1).Project(pixel.ZV)
+	next := screen.GetMatrix(1, 1, 1, 1).Project(pixel.ZV)
+	return pixel.V(math.Abs(next.X-origin.X), math.Abs(next.Y-origin.Y))
+}
+
+// imageLayout determines which part of an image of the given size is shown
+// ("src", relative to the image's top-left corner) and where it is placed
+// ("dst", relative to the area's top-left corner) when the image is scaled
+// into an area of the given size using one of the Image scaling constants.
+func imageLayout(scaling int, imageWidth, imageHeight, areaWidth, areaHeight float64) (src, dst pixel.Rect) {
+	// centered returns a rect of the given size, centered in a rect of the
+	// given outer size.
+	centered := func(width, height, outerWidth, outerHeight float64) pixel.Rect {
+		x, y := (outerWidth-width)/2, (outerHeight-height)/2
+		return pixel.R(x, y, x+width, y+height)
+	}
+
+	src = pixel.R(0, 0, imageWidth, imageHeight)
+	dst = pixel.R(0, 0, areaWidth, areaHeight)
+	if imageWidth <= 0 || imageHeight <= 0 {
+		return
+	}
+	switch scaling {
+	case ImageFit:
+		scale := math.Min(areaWidth/imageWidth, areaHeight/imageHeight)
+		dst = centered(imageWidth*scale, imageHeight*scale, areaWidth, areaHeight)
+	case ImageFill:
+		scale := math.Max(areaWidth/imageWidth, areaHeight/imageHeight)
+		src = centered(areaWidth/scale, areaHeight/scale, imageWidth, imageHeight)
+	case ImageCenter:
+		width, height := math.Min(imageWidth, areaWidth), math.Min(imageHeight, areaHeight)
+		src = centered(width, height, imageWidth, imageHeight)
+		dst = centered(width, height, areaWidth, areaHeight)
+	}
+	return
+}
+
+// multiplyColors multiplies two colors channel by channel.
+func multiplyColors(a, b color.RGBA) color.RGBA {
+	return color.RGBA{
+		R: uint8(uint16(a.R) * uint16(b.R) / 0xff),
+		G: uint8(uint16(a.G) * uint16(b.G) / 0xff),
+		B: uint8(uint16(a.B) * uint16(b.B) / 0xff),
+		A: uint8(uint16(a.A) * uint16(b.A) / 0xff),
+	}
+}
+
+// blendColors draws a color with an alpha-premultiplied value over an opaque
+// background color. Channels which exceed the alpha value, e.g. because of a
+// mask which is not premultiplied, are clamped to 0xff.
+func blendColors(background, over color.RGBA) color.RGBA {
+	inverse := uint16(0xff - over.A)
+	blend := func(over, background uint8) uint8 {
+		sum := uint16(over) + uint16(background)*inverse/0xff
+		if sum > 0xff {
+			return 0xff
+		}
+		return uint8(sum)
+	}
+	return color.RGBA{
+		R: blend(over.R, background.R),
+		G: blend(over.G, background.G),
+		B: blend(over.B, background.B),
+		A: 0xff,
+	}
+}
@@ -1,10 +1,10 @@
 package tview
 
 import (
+	"fmt"
 	"image/color"
 	"math"
-	"strings"
-	"unicode/utf8"
+	"sync"
 
 	runewidth "github.com/mattn/go-runewidth"
 	"github.com/nowakf/pixel/pixelgl"
@@ -17,12 +17,23 @@ import (
 // Use SetMaskCharacter() to hide input from onlookers (e.g. for password
 // input).
 //
+// The text is edited with readline-style keys, e.g. Ctrl-A and Ctrl-E to move
+// to the beginning and the end of the line, Ctrl-W to delete the previous word,
+// or Ctrl-K to delete the rest of the line.
+//
+// Use SetHistory() to let the user recall previously entered lines with the Up
+// and Down keys and search them with Ctrl-R.
+//
+// Use SetAutocompleteFunc() to show a list of candidates beneath the field
+// while the user is typing. The Up and Down keys navigate the list, Enter and
+// Tab accept the selected candidate, and Escape closes the list.
+//
 // See https://github.com/rivo/tview/wiki/InputField for an example.
 type InputField struct {
 	*Box
 
-	// The text that was entered.
-	text string
+	// The text that was entered and the cursor position.
+	editor lineEditor
 
 	// The text to be displayed before the input area.
 	label string
@@ -50,6 +61,9 @@ type InputField struct {
 	// disables masking.
 	maskCharacter rune
 
+	// Whether or not the user is prevented from changing the text.
+	readOnly bool
+
 	// An optional function which may reject the last character that was entered.
 	accept func(text string, ch rune) bool
 
@@ -60,22 +74,80 @@ type InputField struct {
 	// are done entering text. The key which was pressed is provided (tab,
 	// shift-tab, enter, or escape).
 	done func(*pixelgl.KeyEv)
+
+	// An optional function which returns the autocomplete candidates for the
+	// current text.
+	autocomplete func(text string) []string
+
+	// Whether or not the autocomplete function is called in its own goroutine.
+	autocompleteAsync bool
+
+	// The function which runs the update showing asynchronous autocomplete
+	// candidates in the application's event loop.
+	autocompleteQueue func(update func())
+
+	// Guards the autocomplete list and the fields below, which may be changed
+	// by asynchronous autocomplete results.
+	autocompleteMutex sync.Mutex
+
+	// The list of autocomplete candidates, shown beneath the input area.
+	autocompleteList *List
+
+	// Whether or not the autocomplete list is shown.
+	autocompleteOpen bool
+
+	// The number of autocomplete requests so far. Asynchronous results for
+	// older requests are discarded.
+	autocompleteRequest int
+
+	// An optional history of entered lines.
+	history *History
+
+	// The position of the history entry shown in the input area, counted from
+	// the newest entry (1). 0 if the user is editing a new line.
+	historyOffset int
+
+	// The text the user was editing before recalling history entries.
+	historyDraft string
+
+	// Whether or not a reverse incremental search through the history is in
+	// progress.
+	searching bool
+
+	// The text searched for.
+	search lineEditor
+
+	// The index of the history entry which matched the search.
+	searchIndex int
+
+	// Whether or not the last search found no match.
+	searchFailed bool
+
+	// The text before the search was started.
+	searchDraft string
 }
 
 // NewInputField returns a new input field.
 func NewInputField() *InputField {
+	list := NewList().ShowSecondaryText(false)
+	list.SetMainTextColor(Styles.PrimitiveBackgroundColor).
+		SetSelectedTextColor(Styles.PrimitiveBackgroundColor).
+		SetSelectedBackgroundColor(Styles.PrimaryTextColor).
+		SetBackgroundColor(Styles.MoreContrastBackgroundColor)
+
 	return &InputField{
 		Box:                  NewBox(),
 		labelColor:           Styles.SecondaryTextColor,
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
 		placeholderTextColor: Styles.ContrastSecondaryTextColor,
+		autocompleteList:     list,
 	}
 }
 
 // SetText sets the current text of the input field.
 func (i *InputField) SetText(text string) *InputField {
-	i.text = text
+	i.editor.setText(text)
 	if i.changed != nil {
 		i.changed(text)
 	}
@@ -84,7 +156,7 @@ func (i *InputField) SetText(text string) *InputField {
 
 // GetText returns the current text of the input field.
 func (i *InputField) GetText() string {
-	return i.text
+	return i.editor.String()
 }
 
 // SetLabel sets the text to be displayed before the input area.
@@ -150,6 +222,11 @@ func (i *InputField) GetFieldWidth() int {
 	return i.fieldWidth
 }
 
+// GetFieldHeight returns this primitive's field height.
+func (i *InputField) GetFieldHeight() int {
+	return 1
+}
+
 // SetMaskCharacter sets a character that masks user input on a screen. A value
 // of 0 disables masking.
 func (i *InputField) SetMaskCharacter(mask rune) *InputField {
@@ -157,6 +234,20 @@ func (i *InputField) SetMaskCharacter(mask rune) *InputField {
 	return i
 }
 
+// SetReadOnly sets whether the user is prevented from changing the text. A
+// read-only field can still receive focus. It passes Enter, Escape, Tab, and
+// Backtab on to the handler set with SetDoneFunc(), so forms can be navigated
+// as usual, and ignores all other keys.
+func (i *InputField) SetReadOnly(readOnly bool) *InputField {
+	i.readOnly = readOnly
+	return i
+}
+
+// IsReadOnly returns whether the user is prevented from changing the text.
+func (i *InputField) IsReadOnly() bool {
+	return i.readOnly
+}
+
 // SetAcceptanceFunc sets a handler which may reject the last character that was
 // entered (by returning false).
 //
@@ -174,6 +265,117 @@ func (i *InputField) SetChangedFunc(handler func(text string)) *InputField {
 	return i
 }
 
+// SetAutocompleteFunc sets a function which is called whenever the user changed
+// the text. It receives the current text and returns the candidates which are
+// shown in a list beneath the input area. If nil or an empty slice is returned,
+// the list is closed. When the user accepts a candidate, it replaces the text
+// of the input field.
+//
+// Provide nil to turn autocompletion off.
+func (i *InputField) SetAutocompleteFunc(callback func(currentText string) (entries []string)) *InputField {
+	i.autocomplete = callback
+	if callback == nil {
+		i.closeAutocomplete()
+	}
+	return i
+}
+
+// SetAutocompleteAsync sets the flag indicating whether or not the autocomplete
+// function is called in its own goroutine, e.g. because it queries a server or
+// the file system. The candidates are shown when they arrive unless the text
+// has changed in the meantime.
+//
+// The results must be shown in the application's event loop. When they arrive,
+// the goroutine therefore passes the update which shows them to the "queue"
+// function, which is typically Application.QueueUpdateDraw():
+//
+//   inputField.SetAutocompleteAsync(true, func(update func()) {
+//     app.QueueUpdateDraw(update)
+//   })
+//
+// If "queue" is nil, asynchronous results are discarded.
+func (i *InputField) SetAutocompleteAsync(async bool, queue func(update func())) *InputField {
+	i.autocompleteAsync = async
+	i.autocompleteQueue = queue
+	return i
+}
+
+// Autocomplete calls the autocomplete function for the current text and shows
+// the candidates it returns. This is done automatically when the user changes
+// the text but may also be called directly, e.g. after the candidates changed.
+func (i *InputField) Autocomplete() *InputField {
+	if i.autocomplete == nil {
+		return i
+	}
+	text := i.editor.String()
+	i.autocompleteMutex.Lock()
+	i.autocompleteRequest++
+	request := i.autocompleteRequest
+	i.autocompleteMutex.Unlock()
+
+	if !i.autocompleteAsync {
+		i.setAutocompleteEntries(request, i.autocomplete(text))
+		return i
+	}
+	callback, queue := i.autocomplete, i.autocompleteQueue
+	if queue == nil {
+		return i
+	}
+	go func() {
+		entries := callback(text)
+		queue(func() {
+			i.setAutocompleteEntries(request, entries)
+		})
+	}()
+	return i
+}
+
+// setAutocompleteEntries shows the given autocomplete candidates, the results
+// of the request with the given number. Outdated results are discarded.
+func (i *InputField) setAutocompleteEntries(request int, entries []string) {
+	i.autocompleteMutex.Lock()
+	defer i.autocompleteMutex.Unlock()
+	if request != i.autocompleteRequest {
+		return
+	}
+	i.autocompleteList.Clear()
+	for _, entry := range entries {
+		i.autocompleteList.AddItem(entry, "", 0, nil)
+	}
+	i.autocompleteOpen = len(entries) > 0
+}
+
+// closeAutocomplete closes the autocomplete list and discards pending
+// asynchronous results.
+func (i *InputField) closeAutocomplete() {
+	i.autocompleteMutex.Lock()
+	defer i.autocompleteMutex.Unlock()
+	i.autocompleteRequest++
+	i.autocompleteOpen = false
+	i.autocompleteList.Clear()
+}
+
+// SetHistory sets the history of entered lines. When the user presses Enter,
+// the current text is added to the history. The Up and Down keys recall older
+// and newer entries, and Ctrl-R starts a reverse incremental search: the typed
+// text is looked up in the history, Ctrl-R moves on to older matches, Escape
+// cancels the search, and any other key accepts the match.
+//
+// A history may be shared by several input fields. Provide nil to turn the
+// history off.
+func (i *InputField) SetHistory(history *History) *InputField {
+	i.history = history
+	i.historyOffset = 0
+	i.searching = false
+	return i
+}
+
+// GetHistory returns the history set with SetHistory() or nil if there is
+// none.
+func (i *InputField) GetHistory() *History {
+	return i.history
+}
+
 // SetDoneFunc sets a handler which is called when the user is done entering
 // text. The callback function is provided with the key that was pressed, which
 // is one of the following:
@@ -203,8 +405,16 @@ func (i *InputField) Draw(screen ubcell.Screen) {
 		return
 	}
 
-	// Draw label.
-	_, drawnWidth := Print(screen, i.label, x, y, rightLimit-x, AlignLeft, i.labelColor)
+	// Draw label, or the search prompt during a history search.
+	label := i.label
+	if i.searching {
+		prompt := "reverse-i-search"
+		if i.searchFailed {
+			prompt = "failing " + prompt
+		}
+		label = fmt.Sprintf("(%s)`%s': ", prompt, i.search.String())
+	}
+	_, drawnWidth := Print(screen, label, x, y, rightLimit-x, AlignLeft, i.labelColor)
 	x += drawnWidth
 
 	// Draw input area.
@@ -219,115 +429,278 @@ func (i *InputField) Draw(screen ubcell.Screen) {
 	for index := 0; index < fieldWidth; index++ {
 		screen.SetContent(x+index, y, ' ', fieldStyle)
 	}
+	fieldX, fieldY, autocompleteWidth := x, y, fieldWidth
 
 	// Draw placeholder text.
-	text := i.text
-	if text == "" && i.placeholder != "" {
+	if i.editor.empty() && i.placeholder != "" {
 		Print(screen, i.placeholder, x, y, fieldWidth, AlignLeft, i.placeholderTextColor)
 	}
 
-	// Draw entered text.
+	// Draw entered text, scrolled such that the cursor remains visible.
+	text := i.editor.text
 	if i.maskCharacter > 0 {
-		text = strings.Repeat(string(i.maskCharacter), utf8.RuneCountInString(i.text))
-	}
-	fieldWidth-- // We need one cell for the cursor.
-	if fieldWidth < runewidth.StringWidth(text) {
-		runes := []rune(text)
-		for pos := len(runes) - 1; pos >= 0; pos-- {
-			ch := runes[pos]
-			w := runewidth.RuneWidth(ch)
-			if fieldWidth-w < 0 {
-				break
-			}
-			_, style := screen.GetContent(x+fieldWidth-w, y)
-			style.Foreground(i.fieldTextColor)
-			for w > 0 {
-				fieldWidth--
-				screen.SetContent(x+fieldWidth, y, ch, style)
-				w--
-			}
+		text = make([]rune, len(i.editor.text))
+		for index := range text {
+			text[index] = i.maskCharacter
 		}
-	} else {
-		pos := 0
-		for _, ch := range text {
-			w := runewidth.RuneWidth(ch)
-			_, style := screen.GetContent(x+pos, y)
-			style.Foreground(i.fieldTextColor)
-			for w > 0 {
-				screen.SetContent(x+pos, y, ch, style)
-				pos++
-				w--
-			}
+	}
+	cursor := i.editor.cursor
+	cursorWidth := runewidth.StringWidth(string(text[:cursor]))
+	start := 0
+	for start < cursor && cursorWidth >= fieldWidth {
+		cursorWidth -= runewidth.RuneWidth(text[start])
+		start++
+	}
+	pos := 0
+	for _, ch := range text[start:] {
+		w := runewidth.RuneWidth(ch)
+		if pos+w > fieldWidth {
+			break
+		}
+		_, style := screen.GetContent(x+pos, y)
+		style = style.Foreground(i.fieldTextColor)
+		for w > 0 {
+			screen.SetContent(x+pos, y, ch, style)
+			pos++
+			w--
 		}
 	}
 
 	// Set cursor.
-	if i.focus.HasFocus() {
-		i.setCursor(screen)
+	if i.focus.HasFocus() && fieldWidth > 0 && !i.readOnly {
+		screen.ShowCursor(x+cursorWidth, y)
 	}
-}
 
-// setCursor sets the cursor position.
-func (i *InputField) setCursor(screen ubcell.Screen) {
-	x := i.x
-	y := i.y
-	rightLimit := x + i.width
-	if i.border {
-		x++
-		y++
-		rightLimit -= 2
+	// Draw autocomplete list.
+	i.autocompleteMutex.Lock()
+	defer i.autocompleteMutex.Unlock()
+	if !i.autocompleteOpen || !i.focus.HasFocus() {
+		return
 	}
-	fieldWidth := runewidth.StringWidth(i.text)
-	if i.fieldWidth > 0 && fieldWidth > i.fieldWidth-1 {
-		fieldWidth = i.fieldWidth - 1
+	for _, item := range i.autocompleteList.items {
+		if width := StringWidth(item.MainText); width > autocompleteWidth {
+			autocompleteWidth = width
+		}
 	}
-	x += StringWidth(i.label) + fieldWidth
-	if x >= rightLimit {
-		x = rightLimit - 1
+	// We prefer to drop down but if there is no space, maybe drop up?
+	lheight := len(i.autocompleteList.items)
+	swidth, sheight := screen.Size()
+	ly := fieldY + 1
+	if ly+lheight >= sheight && ly-2 > lheight-ly {
+		ly = fieldY - lheight
+		if ly < 0 {
+			ly = 0
+		}
+	}
+	if ly+lheight >= sheight {
+		lheight = sheight - ly
 	}
-	screen.ShowCursor(x, y)
+	if fieldX+autocompleteWidth > swidth {
+		autocompleteWidth = swidth - fieldX
+	}
+	i.autocompleteList.SetRect(fieldX, ly, autocompleteWidth, lheight)
+	i.autocompleteList.Draw(screen)
 }
+
+// Blur is called when this primitive loses focus.
+func (i *InputField) Blur() {
+	i.Box.Blur()
+	i.closeAutocomplete()
+	i.searching = false
+}
+
+// ChaHandler returns the handler for typed characters.
 func (i *InputField) ChaHandler() func(event *pixelgl.ChaEv, setFocus func(p Primitive)) {
 	return func(event *pixelgl.ChaEv, setFocus func(p Primitive)) {
-		newText := i.text + string(*event)
+		ch := rune(*event)
 		if i.accept != nil {
-			if !i.accept(newText, rune(*event)) {
+			if !i.accept(i.editor.withRune(ch), ch) {
 				return
 			}
 		}
-		i.text = newText
+		i.editor.insert(ch)
 	}
 }
 
 // KeyHandler returns the handler for this primitive.
 func (i *InputField) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
 	return i.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		// Read-only fields only let the user move on.
+		if i.readOnly {
+			if ev, ok := event.(*pixelgl.KeyEv); ok && finishingKey(ev) && i.done != nil {
+				i.done(ev)
+			}
+			return
+		}
 
 		// Trigger changed events.
-		currentText := i.text
+		currentText := i.editor.String()
+		typed := true
 		defer func() {
-			if i.text != currentText && i.changed != nil {
-				i.changed(i.text)
+			text := i.editor.String()
+			if text == currentText {
+				return
+			}
+			if i.changed != nil {
+				i.changed(text)
+			}
+			if typed {
+				i.Autocomplete()
 			}
 		}()
 
+		// Search the history.
+		if i.searching {
+			typed = false
+			if i.handleSearch(event) {
+				return
+			}
+		}
+
+		if ev, ok := event.(*pixelgl.ChaEv); ok {
+			i.ChaHandler()(ev, setFocus)
+			return
+		}
 		ev, ok := event.(*pixelgl.KeyEv)
 		if !ok {
 			return
 		}
 
+		// Navigate or accept autocomplete candidates.
+		if i.handleAutocomplete(ev, setFocus) {
+			typed = false
+			return
+		}
+
+		// Edit the text.
+		if i.editor.handleKey(ev) {
+			return
+		}
+
 		// Process key event.
-		switch ev.Key {
-		case pixelgl.KeyBackspace:
-			if len(i.text) == 0 {
-				break
+		switch {
+		case ev.Key == pixelgl.KeyUp: // Recall an older history entry.
+			typed = false
+			i.recallHistory(i.historyOffset + 1)
+		case ev.Key == pixelgl.KeyDown: // Recall a newer history entry or show autocomplete candidates.
+			typed = false
+			if i.historyOffset > 0 {
+				i.recallHistory(i.historyOffset - 1)
+			} else {
+				i.Autocomplete()
+			}
+		case ctrlKey(ev, 'r'): // Start a history search.
+			if i.history != nil {
+				i.searching = true
+				i.search.setText("")
+				i.searchIndex = i.history.GetEntryCount()
+				i.searchFailed = false
+				i.searchDraft = i.editor.String()
+			}
+		case ev.Key == pixelgl.KeyEnter, ev.Key == pixelgl.KeyTab, ev.Key == pixelgl.KeyEscape: // We're done.
+			if ev.Key == pixelgl.KeyEnter && i.history != nil {
+				i.history.Add(i.editor.String())
+				i.historyOffset = 0
 			}
-			runes := []rune(i.text)
-			i.text = string(runes[:len(runes)-1])
-		case pixelgl.KeyEnter, pixelgl.KeyTab, pixelgl.KeyEscape: // We're done.
 			if i.done != nil {
 				i.done(ev)
 			}
 		}
 	})
 }
+
+// handleAutocomplete processes the keys which apply to the autocomplete list
+// while it is shown. It returns whether the key was consumed.
+func (i *InputField) handleAutocomplete(ev *pixelgl.KeyEv, setFocus func(p Primitive)) bool {
+	i.autocompleteMutex.Lock()
+	if !i.autocompleteOpen {
+		i.autocompleteMutex.Unlock()
+		return false
+	}
+	switch ev.Key {
+	case pixelgl.KeyUp, pixelgl.KeyDown, pixelgl.KeyPageUp, pixelgl.KeyPageDown:
+		i.autocompleteList.KeyHandler()(ev, setFocus)
+		i.autocompleteMutex.Unlock()
+		return true
+	case pixelgl.KeyEnter, pixelgl.KeyTab:
+		list := i.autocompleteList
+		var text string
+		if index := list.GetCurrentItem(); index >= 0 && index < len(list.items) {
+			text = list.items[index].MainText
+		}
+		i.autocompleteMutex.Unlock()
+		i.closeAutocomplete()
+		if text != "" {
+			i.editor.setText(text)
+		}
+		return true
+	case pixelgl.KeyEscape:
+		i.autocompleteMutex.Unlock()
+		i.closeAutocomplete()
+		return true
+	}
+	i.autocompleteMutex.Unlock()
+	return false
+}
+
+// recallHistory shows the history entry at the given position, counted from the
+// newest entry (1). Position 0 restores the text the user was editing before.
+func (i *InputField) recallHistory(offset int) {
+	if i.history == nil || offset < 0 || offset > i.history.GetEntryCount() {
+		return
+	}
+	if i.historyOffset == 0 {
+		i.historyDraft = i.editor.String()
+	}
+	i.historyOffset = offset
+	if offset == 0 {
+		i.editor.setText(i.historyDraft)
+		return
+	}
+	if entry, ok := i.history.entry(i.history.GetEntryCount() - offset); ok {
+		i.editor.setText(entry)
+	}
+}
+
+// handleSearch processes an event during a reverse incremental history search.
+// It returns false if the event ended the search and is to be processed as
+// usual.
+func (i *InputField) handleSearch(event pixelgl.Event) bool {
+	if ch, ok := event.(*pixelgl.ChaEv); ok {
+		i.search.insert(rune(*ch))
+		i.searchHistory(i.searchIndex)
+		return true
+	}
+	ev, ok := event.(*pixelgl.KeyEv)
+	if !ok {
+		return true
+	}
+	switch {
+	case ctrlKey(ev, 'r'): // Find an older match.
+		i.searchHistory(i.searchIndex - 1)
+	case ev.Key == pixelgl.KeyBackspace:
+		i.search.backspace()
+		i.searchHistory(i.history.GetEntryCount() - 1)
+	case ev.Key == pixelgl.KeyEscape, ctrlKey(ev, 'g'): // Cancel the search.
+		i.searching = false
+		i.editor.setText(i.searchDraft)
+	default: // Accept the match.
+		i.searching = false
+		return false
+	}
+	return true
+}
+
+// searchHistory shows the newest history entry at or before the given index
+// which contains the search text.
+func (i *InputField) searchHistory(index int) {
+	index = i.history.search(i.search.String(), index)
+	i.searchFailed = index < 0
+	if index < 0 {
+		return
+	}
+	i.searchIndex = index
+	if entry, ok := i.history.entry(index); ok {
+		i.editor.setText(entry)
+	}
+}
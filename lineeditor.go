@@ -0,0 +1,158 @@
+package tview
+
+import (
+	"unicode"
+
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/nowakf/pixel/pixelgl"
+)
+
+// lineEditor holds a single line of text and a cursor and implements the
+// readline-style editing keys. It is shared by the primitives in which the user
+// types text, e.g. InputField and DropDown's type-to-search.
+//
+// The following keys are supported:
+//
+//   - Left arrow, Ctrl-B: Move the cursor left by one character.
+//   - Right arrow, Ctrl-F: Move the cursor right by one character.
+//   - Home, Ctrl-A: Move the cursor to the beginning of the line.
+//   - End, Ctrl-E: Move the cursor to the end of the line.
+//   - Alt-Left arrow, Alt-B: Move the cursor left by one word.
+//   - Alt-Right arrow, Alt-F: Move the cursor right by one word.
+//   - Backspace, Ctrl-H: Delete the character left of the cursor.
+//   - Delete, Ctrl-D: Delete the character under the cursor.
+//   - Ctrl-W: Delete the word left of the cursor.
+//   - Ctrl-K: Delete everything from the cursor to the end of the line.
+//   - Ctrl-U: Delete the entire line.
+type lineEditor struct {
+	// The text being edited.
+	text []rune
+
+	// The index of the rune in front of which the cursor is positioned.
+	cursor int
+}
+
+// String returns the text being edited.
+func (e *lineEditor) String() string {
+	return string(e.text)
+}
+
+// setText replaces the text and moves the cursor to its end.
+func (e *lineEditor) setText(text string) {
+	e.text = []rune(text)
+	e.cursor = len(e.text)
+}
+
+// empty returns whether the text is empty.
+func (e *lineEditor) empty() bool {
+	return len(e.text) == 0
+}
+
+// withRune returns the text as it would be after inserting the given rune at
+// the cursor position.
+func (e *lineEditor) withRune(ch rune) string {
+	return string(e.text[:e.cursor]) + string(ch) + string(e.text[e.cursor:])
+}
+
+// insert inserts a rune at the cursor position and moves the cursor behind it.
+func (e *lineEditor) insert(ch rune) {
+	e.text = append(e.text, 0)
+	copy(e.text[e.cursor+1:], e.text[e.cursor:])
+	e.text[e.cursor] = ch
+	e.cursor++
+}
+
+// backspace deletes the rune left of the cursor.
+func (e *lineEditor) backspace() {
+	if e.cursor > 0 {
+		e.text = append(e.text[:e.cursor-1], e.text[e.cursor:]...)
+		e.cursor--
+	}
+}
+
+// cursorWidth returns the screen width of the text left of the cursor.
+func (e *lineEditor) cursorWidth() int {
+	return runewidth.StringWidth(string(e.text[:e.cursor]))
+}
+
+// wordLeft returns the index of the beginning of the word left of the cursor.
+func (e *lineEditor) wordLeft() int {
+	pos := e.cursor
+	for pos > 0 && !isWordRune(e.text[pos-1]) {
+		pos--
+	}
+	for pos > 0 && isWordRune(e.text[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+// wordRight returns the index of the end of the word right of the cursor.
+func (e *lineEditor) wordRight() int {
+	pos := e.cursor
+	for pos < len(e.text) && !isWordRune(e.text[pos]) {
+		pos++
+	}
+	for pos < len(e.text) && isWordRune(e.text[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// handleKey processes an editing key. It returns false if the key is not an
+// editing key.
+func (e *lineEditor) handleKey(ev *pixelgl.KeyEv) bool {
+	alt := ev.Mods&pixelgl.ModAlt != 0
+	switch {
+	case ev.Key == pixelgl.KeyLeft && alt, altKey(ev, 'b'):
+		e.cursor = e.wordLeft()
+	case ev.Key == pixelgl.KeyRight && alt, altKey(ev, 'f'):
+		e.cursor = e.wordRight()
+	case ev.Key == pixelgl.KeyLeft, ctrlKey(ev, 'b'):
+		if e.cursor > 0 {
+			e.cursor--
+		}
+	case ev.Key == pixelgl.KeyRight, ctrlKey(ev, 'f'):
+		if e.cursor < len(e.text) {
+			e.cursor++
+		}
+	case ev.Key == pixelgl.KeyHome, ctrlKey(ev, 'a'):
+		e.cursor = 0
+	case ev.Key == pixelgl.KeyEnd, ctrlKey(ev, 'e'):
+		e.cursor = len(e.text)
+	case ev.Key == pixelgl.KeyBackspace, ctrlKey(ev, 'h'):
+		e.backspace()
+	case ev.Key == pixelgl.KeyDelete, ctrlKey(ev, 'd'):
+		if e.cursor < len(e.text) {
+			e.text = append(e.text[:e.cursor], e.text[e.cursor+1:]...)
+		}
+	case ctrlKey(ev, 'w'):
+		start := e.wordLeft()
+		e.text = append(e.text[:start], e.text[e.cursor:]...)
+		e.cursor = start
+	case ctrlKey(ev, 'k'):
+		e.text = e.text[:e.cursor]
+	case ctrlKey(ev, 'u'):
+		e.text, e.cursor = nil, 0
+	default:
+		return false
+	}
+	return true
+}
+
+// isWordRune returns whether the given rune is part of a word.
+func isWordRune(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_'
+}
+
+// ctrlKey returns whether the key event is the given letter pressed with the
+// Control key.
+func ctrlKey(ev *pixelgl.KeyEv, ch rune) bool {
+	return ev.Key == pixelgl.KeyRune && ev.Mods&pixelgl.ModControl != 0 && unicode.ToLower(ev.Ch) == ch
+}
+
+// altKey returns whether the key event is the given letter pressed with the Alt
+// key.
+func altKey(ev *pixelgl.KeyEv, ch rune) bool {
+	return ev.Key == pixelgl.KeyRune && ev.Mods&pixelgl.ModAlt != 0 && unicode.ToLower(ev.Ch) == ch
+}
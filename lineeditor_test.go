@@ -0,0 +1,136 @@
+package tview
+
+import (
+	"testing"
+
+	"github.com/nowakf/pixel/pixelgl"
+)
+
+// key returns a key event for the given key and modifiers.
+func key(k pixelgl.Key, mods pixelgl.ModifierKey) *pixelgl.KeyEv {
+	return &pixelgl.KeyEv{Key: k, Act: pixelgl.PRESS, Mods: mods}
+}
+
+// letter returns a key event for the given letter and modifiers.
+func letter(ch rune, mods pixelgl.ModifierKey) *pixelgl.KeyEv {
+	return &pixelgl.KeyEv{Key: pixelgl.KeyRune, Ch: ch, Act: pixelgl.PRESS, Mods: mods}
+}
+
+func TestLineEditorKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		cursor int
+		key    *pixelgl.KeyEv
+		result string
+		moved  int // The cursor position after the key.
+	}{
+		{"left", "abc", 1, key(pixelgl.KeyLeft, 0), "abc", 0},
+		{"left at start", "abc", 0, letter('b', pixelgl.ModControl), "abc", 0},
+		{"right at end", "abc", 3, key(pixelgl.KeyRight, 0), "abc", 3},
+		{"home", "abc", 2, letter('a', pixelgl.ModControl), "abc", 0},
+		{"end", "abc", 0, key(pixelgl.KeyEnd, 0), "abc", 3},
+		{"word left", "foo bar baz", 9, letter('b', pixelgl.ModAlt), "foo bar baz", 8},
+		{"word left from space", "foo bar ", 8, key(pixelgl.KeyLeft, pixelgl.ModAlt), "foo bar ", 4},
+		{"word right", "foo bar", 0, letter('f', pixelgl.ModAlt), "foo bar", 3},
+		{"backspace", "äbc", 1, key(pixelgl.KeyBackspace, 0), "bc", 0},
+		{"backspace at start", "abc", 0, letter('h', pixelgl.ModControl), "abc", 0},
+		{"delete", "abc", 1, key(pixelgl.KeyDelete, 0), "ac", 1},
+		{"delete at end", "abc", 3, letter('d', pixelgl.ModControl), "abc", 3},
+		{"delete word", "foo bar.baz", 11, letter('w', pixelgl.ModControl), "foo bar.", 8},
+		{"delete word and space", "foo bar  ", 9, letter('w', pixelgl.ModControl), "foo ", 4},
+		{"kill", "foo bar", 3, letter('k', pixelgl.ModControl), "foo", 3},
+		{"clear", "foo bar", 3, letter('u', pixelgl.ModControl), "", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var editor lineEditor
+			editor.setText(test.text)
+			editor.cursor = test.cursor
+			if !editor.handleKey(test.key) {
+				t.Fatal("key not handled")
+			}
+			if text := editor.String(); text != test.result || editor.cursor != test.moved {
+				t.Errorf("got %q with cursor %d, expected %q with cursor %d", text, editor.cursor, test.result, test.moved)
+			}
+		})
+	}
+}
+
+func TestLineEditorInsert(t *testing.T) {
+	var editor lineEditor
+	for _, ch := range "ac" {
+		editor.insert(ch)
+	}
+	editor.cursor = 1
+	if text := editor.withRune('b'); text != "abc" || editor.String() != "ac" {
+		t.Errorf("withRune returned %q and changed the text to %q", text, editor.String())
+	}
+	editor.insert('b')
+	if editor.String() != "abc" || editor.cursor != 2 {
+		t.Errorf("got %q with cursor %d", editor.String(), editor.cursor)
+	}
+	if editor.handleKey(letter('x', 0)) || editor.handleKey(key(pixelgl.KeyEnter, 0)) {
+		t.Error("non-editing key handled")
+	}
+}
+
+func TestInputFieldHistory(t *testing.T) {
+	history := NewHistory(0).Add("git status").Add("ls").Add("git log")
+	field := NewInputField().SetHistory(history)
+	field.SetText("draft")
+	handler := field.KeyHandler()
+	press := func(event pixelgl.Event) {
+		handler(event, func(p Primitive) {})
+	}
+	expect := func(text string) {
+		t.Helper()
+		if field.GetText() != text {
+			t.Errorf("got %q, expected %q", field.GetText(), text)
+		}
+	}
+
+	// Recalling entries stops at the oldest one and goes back to the draft.
+	press(key(pixelgl.KeyUp, 0))
+	expect("git log")
+	for count := 0; count < 5; count++ {
+		press(key(pixelgl.KeyUp, 0))
+	}
+	expect("git status")
+	for count := 0; count < 5; count++ {
+		press(key(pixelgl.KeyDown, 0))
+	}
+	expect("draft")
+
+	// The search finds older matches and does not wrap around at the oldest.
+	press(letter('r', pixelgl.ModControl))
+	for _, ch := range "git" {
+		ch := pixelgl.ChaEv(ch)
+		press(&ch)
+	}
+	expect("git log")
+	press(letter('r', pixelgl.ModControl))
+	expect("git status")
+	if field.searchFailed {
+		t.Error("search failed on an older match")
+	}
+	press(letter('r', pixelgl.ModControl))
+	expect("git status")
+	if !field.searchFailed {
+		t.Error("search did not fail past the oldest match")
+	}
+
+	// Escape restores the draft.
+	press(key(pixelgl.KeyEscape, 0))
+	expect("draft")
+	if field.searching {
+		t.Error("still searching after Escape")
+	}
+
+	// Entered lines are added without duplicates.
+	field.SetText("ls")
+	press(key(pixelgl.KeyEnter, 0))
+	if entries := history.GetEntries(); len(entries) != 3 || entries[2] != "ls" {
+		t.Errorf("history is %q after entering a duplicate", entries)
+	}
+}
@@ -46,6 +46,9 @@ type List struct {
 	// The background color for selected items.
 	selectedBackgroundColor color.RGBA
 
+	// The background color of the item below the mouse cursor.
+	hoverBackgroundColor color.RGBA
+
 	// An optional function which is called when the user has navigated to a list
 	// item.
 	changed func(index int, mainText, secondaryText string, shortcut rune)
@@ -68,6 +71,7 @@ func NewList() *List {
 		shortcutColor:           Styles.SecondaryTextColor,
 		selectedTextColor:       Styles.PrimitiveBackgroundColor,
 		selectedBackgroundColor: Styles.PrimaryTextColor,
+		hoverBackgroundColor:    Styles.ContrastBackgroundColor,
 	}
 }
 
@@ -117,6 +121,13 @@ func (l *List) SetSelectedBackgroundColor(color color.RGBA) *List {
 	return l
 }
 
+// SetHoverBackgroundColor sets the background color of the item below the
+// mouse cursor, unless it is selected.
+func (l *List) SetHoverBackgroundColor(color color.RGBA) *List {
+	l.hoverBackgroundColor = color
+	return l
+}
+
 // ShowSecondaryText determines whether or not to show secondary item texts.
 func (l *List) ShowSecondaryText(show bool) *List {
 	l.showSecondaryText = show
@@ -189,6 +200,7 @@ func (l *List) Draw(screen ubcell.Screen) {
 	// Determine the dimensions.
 	x, y, width, height := l.GetInnerRect()
 	bottomLimit := y + height
+	innerX, innerWidth := x, width
 
 	// Do we show any shortcuts?
 	var showShortcuts bool
@@ -213,6 +225,15 @@ func (l *List) Draw(screen ubcell.Screen) {
 		}
 	}
 
+	// Which item is below the mouse cursor?
+	hoveredItem, itemHeight := -1, 1
+	if l.showSecondaryText {
+		itemHeight = 2
+	}
+	if l.hovered && l.hoverX >= innerX && l.hoverX < innerX+innerWidth && l.hoverY >= y && l.hoverY < bottomLimit {
+		hoveredItem = offset + (l.hoverY-y)/itemHeight
+	}
+
 	// Draw the list items.
 	for index, item := range l.items {
 		if index < offset {
@@ -223,6 +244,16 @@ func (l *List) Draw(screen ubcell.Screen) {
 			break
 		}
 
+		// Background color of the item below the mouse cursor.
+		if index == hoveredItem && index != l.currentItem {
+			style := ubcell.StyleDefault.Background(l.hoverBackgroundColor)
+			for by := y; by < y+itemHeight && by < bottomLimit; by++ {
+				for bx := innerX; bx < innerX+innerWidth; bx++ {
+					screen.SetContent(bx, by, ' ', style)
+				}
+			}
+		}
+
 		// Shortcuts.
 		if showShortcuts && item.Shortcut != 0 {
 			Print(screen, fmt.Sprintf("(%s)", string(item.Shortcut)), x-5, y, 4, AlignRight, l.shortcutColor)
@@ -0,0 +1,821 @@
+package tview
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// parseMnemonic removes the ampersand marking the mnemonic from a menu label,
+// e.g. "&Open". It returns the label without it, the index of the mnemonic
+// rune in the returned label (-1 if there is none), and the lower-case
+// mnemonic (0 if there is none). "&&" stands for an ampersand.
+func parseMnemonic(label string) (text string, index int, mnemonic rune) {
+	index = -1
+	var (
+		b         strings.Builder
+		ampersand bool
+		runes     int
+	)
+	for _, ch := range label {
+		if ch == '&' && !ampersand {
+			ampersand = true
+			continue
+		}
+		if ampersand && ch != '&' && index < 0 {
+			index, mnemonic = runes, unicode.ToLower(ch)
+		}
+		ampersand = false
+		b.WriteRune(ch)
+		runes++
+	}
+	return b.String(), index, mnemonic
+}
+
+// printMnemonic prints a menu label, drawing the mnemonic rune in a different
+// color. It returns the printed width. (Mnemonics are usually underlined but
+// the styles of ubcell screens only consist of a foreground and a background
+// color.)
+func printMnemonic(screen ubcell.Screen, label string, x, y, maxWidth int, textColor, mnemonicColor ubcell.Style) int {
+	text, mnemonicIndex, _ := parseMnemonic(label)
+	width := 0
+	for index, ch := range []rune(text) {
+		chWidth := StringWidth(string(ch))
+		if width+chWidth > maxWidth {
+			break
+		}
+		style := textColor
+		if index == mnemonicIndex {
+			style = mnemonicColor
+		}
+		screen.SetContent(x+width, y, ch, style)
+		width += chWidth
+	}
+	return width
+}
+
+// MenuItem is an entry of a Menu. It is either an action, a checkable option,
+// a submenu, or a separator.
+type MenuItem struct {
+	// The label. An ampersand marks the following rune as the mnemonic.
+	label string
+
+	// The shortcut hint shown to the right of the label, e.g. "Ctrl-O".
+	shortcut string
+
+	// An optional function which is called when the item is selected.
+	selected func()
+
+	// The submenu opened by this item, if any.
+	submenu *Menu
+
+	// Whether or not the item is checkable and checked.
+	checkable, checked bool
+
+	// Whether or not the item can be selected.
+	disabled bool
+
+	// Whether or not the item is a separator line.
+	separator bool
+}
+
+// NewMenuItem returns a new menu item with the given label. An ampersand marks
+// the following rune as the item's mnemonic, e.g. "&Open". While the menu is
+// open, pressing the mnemonic key selects the item. Use "&&" for an ampersand.
+func NewMenuItem(label string) *MenuItem {
+	return &MenuItem{label: label}
+}
+
+// NewMenuSeparator returns a new menu item which is drawn as a separator line.
+func NewMenuSeparator() *MenuItem {
+	return &MenuItem{separator: true, disabled: true}
+}
+
+// SetLabel sets the item's label.
+func (i *MenuItem) SetLabel(label string) *MenuItem {
+	i.label = label
+	return i
+}
+
+// GetLabel returns the item's label, including the ampersand marking the
+// mnemonic.
+func (i *MenuItem) GetLabel() string {
+	return i.label
+}
+
+// SetShortcut sets the shortcut hint shown to the right of the label, e.g.
+// "Ctrl-O". This is only a hint: the application needs to handle the key
+// itself, e.g. with Application.SetKeyCapture().
+func (i *MenuItem) SetShortcut(shortcut string) *MenuItem {
+	i.shortcut = shortcut
+	return i
+}
+
+// SetSelectedFunc sets a function which is called when the user selects the
+// item. The menu is closed before the function is called.
+func (i *MenuItem) SetSelectedFunc(handler func()) *MenuItem {
+	i.selected = handler
+	return i
+}
+
+// SetSubmenu sets the menu which opens when the user selects the item.
+func (i *MenuItem) SetSubmenu(submenu *Menu) *MenuItem {
+	i.submenu = submenu
+	return i
+}
+
+// SetCheckable sets whether the item is a checkable option. Selecting such an
+// item toggles its check mark before the "selected" function is called. The
+// menu stays open.
+func (i *MenuItem) SetCheckable(checkable bool) *MenuItem {
+	i.checkable = checkable
+	return i
+}
+
+// SetChecked sets whether a checkable item is checked.
+func (i *MenuItem) SetChecked(checked bool) *MenuItem {
+	i.checked = checked
+	return i
+}
+
+// IsChecked returns whether a checkable item is checked.
+func (i *MenuItem) IsChecked() bool {
+	return i.checked
+}
+
+// SetDisabled sets whether the item is disabled. Disabled items are drawn in a
+// different color and cannot be selected.
+func (i *MenuItem) SetDisabled(disabled bool) *MenuItem {
+	i.disabled = disabled
+	return i
+}
+
+// IsDisabled returns whether the item is disabled.
+func (i *MenuItem) IsDisabled() bool {
+	return i.disabled
+}
+
+// Menu is a list of menu items shown in a MenuBar, as a submenu, or as a
+// context menu (see Box.SetContextMenu()).
+type Menu struct {
+	// The title shown in a menu bar. An ampersand marks the mnemonic.
+	title string
+
+	// The menu's items.
+	items []*MenuItem
+}
+
+// NewMenu returns a new, empty menu with the given title. The title is shown
+// in a MenuBar. An ampersand marks the following rune as the menu's mnemonic,
+// e.g. "&File", which opens the menu when pressed together with Alt.
+func NewMenu(title string) *Menu {
+	return &Menu{title: title}
+}
+
+// AddItem adds an item to the end of the menu.
+func (m *Menu) AddItem(item *MenuItem) *Menu {
+	m.items = append(m.items, item)
+	return m
+}
+
+// AddSeparator adds a separator line to the end of the menu.
+func (m *Menu) AddSeparator() *Menu {
+	return m.AddItem(NewMenuSeparator())
+}
+
+// GetItems returns the menu's items.
+func (m *Menu) GetItems() []*MenuItem {
+	return m.items
+}
+
+// Clear removes all items from the menu.
+func (m *Menu) Clear() *Menu {
+	m.items = nil
+	return m
+}
+
+// menuPopup is an open menu. The first popup of a menu is shown as an overlay
+// and draws and handles the popups of the open submenus, too.
+type menuPopup struct {
+	*Box
+
+	// The menu shown.
+	menu *Menu
+
+	// The index of the highlighted item, -1 if there is none.
+	current int
+
+	// The requested position of the popup's top-left corner.
+	x, y int
+
+	// The popup of the open submenu and the popup which opened this one.
+	child, parent *menuPopup
+
+	// The menu bar the menu belongs to, if any.
+	bar *MenuBar
+
+	// Whether or not a mouse button was pressed within the menu and not
+	// released yet. Only releasing such a button selects an item, so that
+	// releasing the button which opened a context menu doesn't.
+	pressed bool
+}
+
+// openMenu shows a menu at the given screen position. If "highlight" is true,
+// the first item is highlighted, e.g. when the menu is opened with the
+// keyboard.
+func openMenu(app *Application, menu *Menu, x, y int, bar *MenuBar, highlight bool) *menuPopup {
+	p := newMenuPopup(menu, x, y, nil, bar, highlight)
+	p.app = app
+	app.showOverlay(p)
+	return p
+}
+
+// newMenuPopup returns a new popup for the given menu.
+func newMenuPopup(menu *Menu, x, y int, parent *menuPopup, bar *MenuBar, highlight bool) *menuPopup {
+	p := &menuPopup{
+		Box:     NewBox().SetBorder(true).SetBackgroundColor(Styles.ContrastBackgroundColor),
+		menu:    menu,
+		current: -1,
+		x:       x,
+		y:       y,
+		parent:  parent,
+		bar:     bar,
+	}
+	if highlight {
+		p.move(1)
+	}
+	p.focus = p
+	return p
+}
+
+// ShowContextMenu opens a menu at the given screen position, e.g. at the mouse
+// pointer. Primitives can open a context menu on a right click by themselves,
+// see Box.SetContextMenu().
+func (a *Application) ShowContextMenu(menu *Menu, x, y int) *Application {
+	a.CloseMenus()
+	openMenu(a, menu, x, y, nil, false)
+	return a
+}
+
+// CloseMenus closes all open menus, including the menus of menu bars.
+func (a *Application) CloseMenus() *Application {
+	for _, overlay := range a.getOverlays() {
+		if p, ok := overlay.(*menuPopup); ok {
+			p.closeAll()
+		}
+	}
+	return a
+}
+
+// showOverlay adds a primitive on top of the overlays, the primitives drawn
+// above all others, e.g. open menus. While they are shown, the topmost one
+// receives all key and mouse events.
+func (a *Application) showOverlay(p Primitive) {
+	a.Lock()
+	defer a.Unlock()
+	a.overlays = append(a.overlays, p)
+}
+
+// hideOverlay removes a primitive from the overlays.
+func (a *Application) hideOverlay(p Primitive) {
+	a.Lock()
+	defer a.Unlock()
+	for index, overlay := range a.overlays {
+		if overlay == p {
+			a.overlays = append(a.overlays[:index], a.overlays[index+1:]...)
+			return
+		}
+	}
+}
+
+// topOverlay returns the topmost overlay or nil if there is none.
+func (a *Application) topOverlay() Primitive {
+	a.RLock()
+	defer a.RUnlock()
+	if len(a.overlays) == 0 {
+		return nil
+	}
+	return a.overlays[len(a.overlays)-1]
+}
+
+// getOverlays returns the overlays, from the bottom to the top.
+func (a *Application) getOverlays() []Primitive {
+	a.RLock()
+	defer a.RUnlock()
+	return append([]Primitive(nil), a.overlays...)
+}
+
+// root returns the first popup of the menu.
+func (p *menuPopup) root() *menuPopup {
+	for p.parent != nil {
+		p = p.parent
+	}
+	return p
+}
+
+// deepest returns the popup of the innermost open submenu.
+func (p *menuPopup) deepest() *menuPopup {
+	for p.child != nil {
+		p = p.child
+	}
+	return p
+}
+
+// closeAll closes the menu and all its submenus.
+func (p *menuPopup) closeAll() {
+	root := p.root()
+	root.child = nil
+	root.app.hideOverlay(root)
+	if root.bar != nil && root.bar.popup == root {
+		root.bar.popup = nil
+		root.bar.open = -1
+	}
+}
+
+// move highlights the next (direction 1) or previous (direction -1) item
+// which can be selected.
+func (p *menuPopup) move(direction int) {
+	count := len(p.menu.items)
+	current := p.current
+	if current < 0 && direction < 0 {
+		current = count
+	}
+	for step := 0; step < count; step++ {
+		current = (current + direction + count) % count
+		if !p.menu.items[current].disabled {
+			p.current = current
+			return
+		}
+	}
+}
+
+// openSubmenu opens the submenu of the highlighted item.
+func (p *menuPopup) openSubmenu(highlight bool) {
+	if p.current < 0 || p.menu.items[p.current].submenu == nil {
+		p.child = nil
+		return
+	}
+	if p.child != nil && p.child.menu == p.menu.items[p.current].submenu {
+		return
+	}
+	x, y, width, _ := p.GetRect()
+	p.child = newMenuPopup(p.menu.items[p.current].submenu, x+width-1, y+p.current, p, p.bar, highlight)
+}
+
+// activate selects the item with the given index.
+func (p *menuPopup) activate(index int) {
+	if index < 0 || index >= len(p.menu.items) {
+		return
+	}
+	item := p.menu.items[index]
+	if item.disabled {
+		return
+	}
+	p.current = index
+	switch {
+	case item.submenu != nil:
+		p.child = nil
+		p.openSubmenu(true)
+	case item.checkable:
+		item.checked = !item.checked
+		if item.selected != nil {
+			item.selected()
+		}
+	default:
+		p.closeAll()
+		if item.selected != nil {
+			item.selected()
+		}
+	}
+}
+
+// handleKey processes a key event for this popup, which is the deepest one.
+func (p *menuPopup) handleKey(ev *pixelgl.KeyEv) {
+	switch ev.Key {
+	case pixelgl.KeyUp:
+		p.move(-1)
+	case pixelgl.KeyDown:
+		p.move(1)
+	case pixelgl.KeyHome:
+		p.current = -1
+		p.move(1)
+	case pixelgl.KeyEnd:
+		p.current = -1
+		p.move(-1)
+	case pixelgl.KeyRight:
+		if p.current >= 0 && p.menu.items[p.current].submenu != nil {
+			p.openSubmenu(true)
+		} else if p.bar != nil {
+			p.bar.switchMenu(1)
+		}
+	case pixelgl.KeyLeft:
+		if p.parent != nil {
+			p.parent.child = nil
+		} else if p.bar != nil {
+			p.bar.switchMenu(-1)
+		}
+	case pixelgl.KeyEnter:
+		p.activate(p.current)
+	case pixelgl.KeyEscape:
+		if p.parent != nil {
+			p.parent.child = nil
+		} else {
+			p.closeAll()
+		}
+	case pixelgl.KeyRune:
+		if ev.Ch == ' ' {
+			p.activate(p.current)
+			return
+		}
+		ch := unicode.ToLower(ev.Ch)
+		for index, item := range p.menu.items {
+			if _, _, mnemonic := parseMnemonic(item.label); mnemonic != 0 && mnemonic == ch {
+				p.activate(index)
+				return
+			}
+		}
+	}
+}
+
+// KeyHandler returns the handler for this primitive. As an overlay, the popup
+// receives all key events while it is shown.
+func (p *menuPopup) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return p.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		if ev, ok := event.(*pixelgl.KeyEv); ok {
+			p.deepest().handleKey(ev)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive. As an overlay,
+// the popup receives all mouse events while it is shown.
+func (p *menuPopup) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return p.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.CursorEvent)
+		if !ok {
+			return
+		}
+
+		// Find the popup below the cursor, starting with the innermost one.
+		var popups []*menuPopup
+		for popup := p; popup != nil; popup = popup.child {
+			popups = append(popups, popup)
+		}
+		for index := len(popups) - 1; index >= 0; index-- {
+			popup := popups[index]
+			if !popup.InRect(ev.X, ev.Y) {
+				continue
+			}
+			pressed := p.pressed
+			switch ev.Act {
+			case pixelgl.PRESS:
+				p.pressed = true
+			case pixelgl.RELEASE:
+				p.pressed = false
+			}
+			_, y, _, height := popup.GetRect()
+			item := ev.Y - y - 1
+			if item < 0 || item >= height-2 || item >= len(popup.menu.items) || popup.menu.items[item].disabled {
+				return
+			}
+			popup.current = item
+			switch {
+			case popup.menu.items[item].submenu != nil:
+				popup.openSubmenu(false)
+			case ev.Act == pixelgl.RELEASE && pressed && (ev.Button == pixelgl.MouseButtonLeft || ev.Button == pixelgl.MouseButtonRight):
+				popup.child = nil
+				popup.activate(item)
+			default:
+				popup.child = nil
+			}
+			return
+		}
+
+		// Events on the menu bar switch menus.
+		if p.bar != nil && p.bar.InRect(ev.X, ev.Y) {
+			p.bar.handleMouse(ev)
+			return
+		}
+
+		// Pressing a button outside the menu closes it.
+		switch ev.Act {
+		case pixelgl.PRESS:
+			p.closeAll()
+		case pixelgl.RELEASE:
+			p.pressed = false
+		}
+	})
+}
+
+// size returns the width and height of the popup, including its border.
+func (p *menuPopup) size() (width, height int) {
+	var labelWidth, shortcutWidth, arrowWidth int
+	for _, item := range p.menu.items {
+		text, _, _ := parseMnemonic(item.label)
+		if w := StringWidth(text); w > labelWidth {
+			labelWidth = w
+		}
+		if w := StringWidth(item.shortcut); w > shortcutWidth {
+			shortcutWidth = w
+		}
+		if item.submenu != nil {
+			arrowWidth = 2
+		}
+	}
+	if shortcutWidth > 0 {
+		shortcutWidth += 2
+	}
+	return labelWidth + shortcutWidth + arrowWidth + 6, len(p.menu.items) + 2
+}
+
+// Draw draws this primitive onto the screen, followed by the open submenus.
+func (p *menuPopup) Draw(screen ubcell.Screen) {
+	// Keep the popup on the screen.
+	screenWidth, screenHeight := screen.Size()
+	width, height := p.size()
+	x, y := p.x, p.y
+	if x+width > screenWidth {
+		if p.parent != nil {
+			x = p.parent.x - width + 1 // Open the submenu to the left.
+		} else {
+			x = screenWidth - width
+		}
+	}
+	if y+height > screenHeight {
+		y = screenHeight - height
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	p.SetRect(x, y, width, height)
+	p.Box.Draw(screen)
+
+	// Draw the items.
+	textStyle := ubcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor)
+	mnemonicStyle := textStyle.Foreground(Styles.TertiaryTextColor)
+	disabledStyle := textStyle.Foreground(Styles.PrimitiveBackgroundColor)
+	selectedStyle := ubcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.InverseTextColor)
+	for index, item := range p.menu.items {
+		row := y + 1 + index
+		if row >= y+height-1 {
+			break
+		}
+		if item.separator {
+			borderStyle := textStyle.Foreground(p.borderColor)
+			screen.SetContent(x, row, GraphicsLeftT, borderStyle)
+			for column := x + 1; column < x+width-1; column++ {
+				screen.SetContent(column, row, GraphicsHoriBar, borderStyle)
+			}
+			screen.SetContent(x+width-1, row, GraphicsRightT, borderStyle)
+			continue
+		}
+		style, mnemonic := textStyle, mnemonicStyle
+		switch {
+		case item.disabled:
+			style, mnemonic = disabledStyle, disabledStyle
+		case index == p.current:
+			style, mnemonic = selectedStyle, selectedStyle
+		}
+		for column := x + 1; column < x+width-1; column++ {
+			screen.SetContent(column, row, ' ', style)
+		}
+		if item.checkable && item.checked {
+			screen.SetContent(x+2, row, '✓', style)
+		}
+		printMnemonic(screen, item.label, x+4, row, width-6, style, mnemonic)
+		right := x + width - 2
+		if item.submenu != nil {
+			screen.SetContent(right, row, '▸', style)
+			right -= 2
+		}
+		if item.shortcut != "" {
+			shortcutWidth := StringWidth(item.shortcut)
+			for offset, ch := range []rune(item.shortcut) {
+				screen.SetContent(right-shortcutWidth+1+offset, row, ch, style)
+			}
+		}
+	}
+
+	// Draw the open submenu.
+	if p.child != nil {
+		p.child.Draw(screen)
+	}
+}
+
+// MenuBar is a one-line bar of menu titles, typically at the top of the
+// screen. Selecting a title opens its menu as a drop-down below it. Menus are
+// drawn above all other primitives and may extend beyond the menu bar's box.
+//
+// Menus are opened with the mouse, with F10 (the first menu), or with Alt and a
+// menu's mnemonic (e.g. Alt-F for "&File"). As key events are only sent to the
+// primitive which has focus, install CaptureShortcuts() as the application's
+// key capture (see Application.SetKeyCapture()) to make these keys work.
+//
+// While a menu is open, it receives all key events:
+//
+//   - Up, Down, Home, End: Highlight an item.
+//   - Enter, Space: Select the highlighted item.
+//   - Right: Open the highlighted submenu or the next menu of the bar.
+//   - Left: Close the submenu or open the previous menu of the bar.
+//   - Escape: Close the submenu or the menu.
+//   - A mnemonic key: Select the item with this mnemonic.
+//
+// Mnemonics are drawn in a different color (TertiaryTextColor, see Styles)
+// instead of being underlined, as the cells of the screen have no underline
+// attribute.
+type MenuBar struct {
+	*Box
+
+	// The menus of the bar.
+	menus []*Menu
+
+	// The screen positions and widths of the titles as of the last call to
+	// Draw().
+	titleX, titleWidth []int
+
+	// The index of the open menu, -1 if none is open, and its popup.
+	open  int
+	popup *menuPopup
+}
+
+// NewMenuBar returns a new menu bar without menus.
+func NewMenuBar() *MenuBar {
+	m := &MenuBar{
+		Box:  NewBox().SetBackgroundColor(Styles.ContrastBackgroundColor),
+		open: -1,
+	}
+	m.focus = m
+	return m
+}
+
+// AddMenu adds a menu to the right end of the bar.
+func (m *MenuBar) AddMenu(menu *Menu) *MenuBar {
+	m.menus = append(m.menus, menu)
+	return m
+}
+
+// GetMenus returns the menus of the bar.
+func (m *MenuBar) GetMenus() []*Menu {
+	return m.menus
+}
+
+// OpenMenu opens the menu with the given index, highlighting its first item.
+// Menus can only be opened after an application drew the menu bar.
+func (m *MenuBar) OpenMenu(index int) *MenuBar {
+	m.openMenu(index, true)
+	return m
+}
+
+// CloseMenu closes the open menu, if any.
+func (m *MenuBar) CloseMenu() *MenuBar {
+	if m.popup != nil {
+		m.popup.closeAll()
+	}
+	return m
+}
+
+// openMenu opens the menu with the given index.
+func (m *MenuBar) openMenu(index int, highlight bool) {
+	m.CloseMenu()
+	if m.app == nil || index < 0 || index >= len(m.menus) {
+		return
+	}
+	x, y, _, _ := m.GetInnerRect()
+	if index < len(m.titleX) {
+		x = m.titleX[index]
+	}
+	m.app.CloseMenus()
+	m.open = index
+	m.popup = openMenu(m.app, m.menus[index], x, y+1, m, highlight)
+}
+
+// switchMenu opens the next (direction 1) or previous (direction -1) menu.
+func (m *MenuBar) switchMenu(direction int) {
+	if len(m.menus) == 0 {
+		return
+	}
+	m.openMenu((m.open+direction+len(m.menus))%len(m.menus), true)
+}
+
+// titleAt returns the index of the menu title at the given screen column, -1
+// if there is none.
+func (m *MenuBar) titleAt(x int) int {
+	for index := range m.titleX {
+		if x >= m.titleX[index] && x < m.titleX[index]+m.titleWidth[index] {
+			return index
+		}
+	}
+	return -1
+}
+
+// handleMouse opens or closes menus when the bar is clicked or, while a menu
+// is open, when the cursor moves to another title.
+func (m *MenuBar) handleMouse(ev *pixelgl.CursorEvent) {
+	index := m.titleAt(ev.X)
+	switch {
+	case index < 0:
+		if ev.Act == pixelgl.PRESS {
+			m.CloseMenu()
+		}
+	case ev.Act == pixelgl.PRESS && ev.Button == pixelgl.MouseButtonLeft:
+		if index == m.open {
+			m.CloseMenu()
+		} else {
+			m.openMenu(index, false)
+		}
+	case m.open >= 0 && index != m.open && ev.Act != pixelgl.RELEASE:
+		m.openMenu(index, false)
+	}
+}
+
+// CaptureShortcuts opens a menu if the given event is F10 or Alt together with
+// a menu's mnemonic, returning nil in that case. Other events are returned
+// unchanged. The function has the signature of an input capture, so it can be
+// used like this:
+//
+//   app.SetKeyCapture(menuBar.CaptureShortcuts)
+func (m *MenuBar) CaptureShortcuts(event pixelgl.Event) pixelgl.Event {
+	ev, ok := event.(*pixelgl.KeyEv)
+	if !ok {
+		return event
+	}
+	if ev.Key == pixelgl.KeyF10 && ev.Mods == 0 {
+		if m.open >= 0 {
+			m.CloseMenu()
+		} else {
+			m.OpenMenu(0)
+		}
+		return nil
+	}
+	if ev.Key == pixelgl.KeyRune && ev.Mods&pixelgl.ModAlt != 0 {
+		for index, menu := range m.menus {
+			if _, _, mnemonic := parseMnemonic(menu.title); mnemonic != 0 && altKey(ev, mnemonic) {
+				m.OpenMenu(index)
+				return nil
+			}
+		}
+	}
+	return event
+}
+
+// KeyHandler returns the handler for this primitive.
+func (m *MenuBar) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return m.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		if ev, ok := event.(*pixelgl.KeyEv); ok && (ev.Key == pixelgl.KeyEnter || ev.Key == pixelgl.KeyDown) {
+			m.OpenMenu(0)
+			return
+		}
+		m.CaptureShortcuts(event)
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (m *MenuBar) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return m.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		if ev, ok := event.(*pixelgl.CursorEvent); ok {
+			m.handleMouse(ev)
+		}
+	})
+}
+
+// Draw draws this primitive onto the screen. Open menus are drawn by the
+// application, above all other primitives.
+func (m *MenuBar) Draw(screen ubcell.Screen) {
+	m.Box.Draw(screen)
+	x, y, width, height := m.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	textStyle := ubcell.StyleDefault.Background(m.backgroundColor).Foreground(Styles.PrimaryTextColor)
+	mnemonicStyle := textStyle.Foreground(Styles.TertiaryTextColor)
+	selectedStyle := ubcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.InverseTextColor)
+	m.titleX, m.titleWidth = m.titleX[:0], m.titleWidth[:0]
+	pos := x
+	for index, menu := range m.menus {
+		text, _, _ := parseMnemonic(menu.title)
+		titleWidth := StringWidth(text) + 2
+		if pos+titleWidth > x+width {
+			titleWidth = x + width - pos
+		}
+		if titleWidth <= 0 {
+			break
+		}
+		style, mnemonic := textStyle, mnemonicStyle
+		if index == m.open {
+			style, mnemonic = selectedStyle, selectedStyle
+		}
+		for column := pos; column < pos+titleWidth; column++ {
+			screen.SetContent(column, y, ' ', style)
+		}
+		printMnemonic(screen, menu.title, pos+1, y, titleWidth-1, style, mnemonic)
+		m.titleX = append(m.titleX, pos)
+		m.titleWidth = append(m.titleWidth, titleWidth)
+		pos += titleWidth
+	}
+}
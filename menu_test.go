@@ -0,0 +1,36 @@
+package tview
+
+import "testing"
+
+func TestParseMnemonic(t *testing.T) {
+	tests := []struct {
+		label, text string
+		index       int
+		mnemonic    rune
+	}{
+		{"", "", -1, 0},
+		{"Open", "Open", -1, 0},
+		{"&Open", "Open", 0, 'o'},
+		{"Save &As", "Save As", 5, 'a'},
+		{"E&xit", "Exit", 1, 'x'},
+		{"Ö&ffnen", "Öffnen", 1, 'f'},
+		{"&Über", "Über", 0, 'ü'},
+
+		// "&&" is an ampersand.
+		{"Copy && Paste", "Copy & Paste", -1, 0},
+		{"&&Open", "&Open", -1, 0},
+		{"Copy && &Paste", "Copy & Paste", 7, 'p'},
+		{"&&&Open", "&Open", 1, 'o'},
+		{"&&&&", "&&", -1, 0},
+
+		// Only the first mnemonic counts, a trailing ampersand is dropped.
+		{"&Open &File", "Open File", 0, 'o'},
+		{"Open&", "Open", -1, 0},
+	}
+	for _, test := range tests {
+		text, index, mnemonic := parseMnemonic(test.label)
+		if text != test.text || index != test.index || mnemonic != test.mnemonic {
+			t.Errorf("parseMnemonic(%q) = %q, %d, %q, expected %q, %d, %q", test.label, text, index, mnemonic, test.text, test.index, test.mnemonic)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package tview
 
 import (
 	"image/color"
+	"time"
+	"unicode"
+
+	"github.com/nowakf/pixel/pixelgl"
 
 	"github.com/nowakf/ubcell"
 )
@@ -10,6 +14,18 @@ import (
 // for an immediate decision. It needs to have at least one button (added via
 // AddButtons()) or it will never disappear.
 //
+// The message is shown in a TextView which scrolls if the text is too long for
+// the window. The message may be replaced by any primitive, see SetBody(). The
+// window's size is calculated from its contents unless it is set with
+// SetSize(), and it may be limited with SetMinSize() and SetMaxSize().
+//
+// The buttons are selected with the Tab key and Enter, with keyboard shortcuts
+// (see SetButtonShortcut()), or with the mouse. Escape selects the button set
+// with SetEscapeButton(). While a button has focus, the arrow and page keys
+// scroll the message.
+//
+// The window may fade or slide in when it appears, see SetTransition().
+//
 // See https://github.com/rivo/tview/wiki/Modal for an example.
 type Modal struct {
 	*Box
@@ -17,32 +33,82 @@ type Modal struct {
 	// The framed embedded in the modal.
 	frame *Frame
 
-	// The form embedded in the modal's frame.
+	// The form embedded in the modal's frame. Its first item is the body, the
+	// buttons follow.
 	form *Form
 
+	// The text view showing the message.
+	textView *TextView
+
+	// The primitive shown above the buttons, the text view by default, and the
+	// form item wrapping it.
+	body     Primitive
+	bodyItem *FormPrimitive
+
 	// The message text (original, not word-wrapped).
 	text string
 
 	// The text color.
 	textColor color.RGBA
 
+	// The fixed, minimum, and maximum size of the window. 0 means no
+	// restriction.
+	width, height       int
+	minWidth, minHeight int
+	maxWidth, maxHeight int
+
+	// The keyboard shortcuts of the buttons, mapped to the button indices.
+	shortcuts map[rune]int
+
+	// The index of the button selected with the Escape key, or -1 if Escape
+	// does not select a button.
+	escapeButton int
+
 	// The optional callback for when the user clicked one of the buttons. It
 	// receives the index of the clicked button and the button's label.
 	done func(buttonIndex int, buttonLabel string)
+
+	// How the window appears, see SetTransition().
+	transitionKind      int
+	transitionDirection int
+	transitionDuration  time.Duration
+	transitionEasing    EasingFunc
+
+	// Whether or not the window was drawn since it was last hidden.
+	appeared bool
+
+	// The animator running the appearance, its animation, and its progress.
+	animator  *Animator
+	animation *Animation
+	progress  float64
 }
 
 // NewModal returns a new modal message window.
 func NewModal() *Modal {
 	m := &Modal{
-		Box:       NewBox(),
-		textColor: Styles.PrimaryTextColor,
+		Box:          NewBox(),
+		textColor:    Styles.PrimaryTextColor,
+		shortcuts:    make(map[rune]int),
+		escapeButton: -1,
+		animator:     NewAnimator(),
 	}
+	m.textView = NewTextView().
+		SetWordWrap(true).
+		SetTextAlign(AlignCenter).
+		SetDynamicColors(true).
+		SetTextColor(m.textColor)
+	m.textView.SetBackgroundColor(Styles.ContrastBackgroundColor)
+	m.body = m.textView
+	m.bodyItem = NewFormPrimitive(m.textView)
+	m.bodyItem.SetInputCapture(m.captureKey(true))
 	m.form = NewForm().
 		SetButtonsAlign(AlignCenter).
 		SetButtonBackgroundColor(Styles.PrimitiveBackgroundColor).
-		SetButtonTextColor(Styles.PrimaryTextColor)
+		SetButtonTextColor(Styles.PrimaryTextColor).
+		AddFormItem(m.bodyItem).
+		SetCancelFunc(m.escape)
 	m.form.SetBackgroundColor(Styles.ContrastBackgroundColor).SetBorderPadding(0, 0, 0, 0)
-	m.frame = NewFrame(m.form).SetBorders(0, 0, 1, 0, 0, 0)
+	m.frame = NewFrame(m.form).SetBorders(0, 0, 0, 0, 0, 0)
 	m.frame.SetBorder(true).
 		SetBackgroundColor(Styles.ContrastBackgroundColor).
 		SetBorderPadding(1, 1, 1, 1)
@@ -53,43 +119,197 @@ func NewModal() *Modal {
 // SetTextColor sets the color of the message text.
 func (m *Modal) SetTextColor(color color.RGBA) *Modal {
 	m.textColor = color
+	m.textView.SetTextColor(color)
+	return m
+}
+
+// SetTransition sets how the window appears when it is drawn for the first time
+// after it was hidden (e.g. with Pages.HidePage()): "kind" is TransitionFade
+// for fading in, TransitionSlide or TransitionPush for sliding in, or
+// TransitionNone (the default) for appearing instantly. "direction" is the
+// direction in which a sliding window moves, e.g. a window moving up with
+// TransitionUp enters from the bottom of the screen. The easing function maps
+// the linear progress to the shown progress (EaseOut if nil). User input is
+// discarded while the window appears.
+func (m *Modal) SetTransition(kind, direction int, duration time.Duration, easing EasingFunc) *Modal {
+	if easing == nil {
+		easing = EaseOut
+	}
+	m.transitionKind = kind
+	m.transitionDirection = direction
+	m.transitionDuration = duration
+	m.transitionEasing = easing
 	return m
 }
 
 // SetDoneFunc sets a handler which is called when one of the buttons was
 // pressed. It receives the index of the button as well as its label text. The
 // handler is also called when the user presses the Escape key. The index will
-// then be negative and the label text an emptry string.
+// then be negative and the label text an emptry string, unless Escape selects
+// a button (see SetEscapeButton()).
 func (m *Modal) SetDoneFunc(handler func(buttonIndex int, buttonLabel string)) *Modal {
 	m.done = handler
 	return m
 }
 
 // SetText sets the message text of the window. The text may contain line
-// breaks. Note that words are wrapped, too, based on the final size of the
-// window.
+// breaks and color tags. Note that words are wrapped, too, based on the final
+// size of the window.
 func (m *Modal) SetText(text string) *Modal {
 	m.text = text
+	m.textView.SetText(text)
+	return m
+}
+
+// GetTextView returns the text view which shows the message text, e.g. to
+// change its alignment.
+func (m *Modal) GetTextView() *TextView {
+	return m.textView
+}
+
+// SetBody sets the primitive shown above the buttons instead of the message
+// text. The user moves between the body and the buttons with the Tab key. The
+// buttons have focus when the window receives focus. Provide nil to show the
+// message text again.
+func (m *Modal) SetBody(body Primitive) *Modal {
+	if body == nil {
+		body = m.textView
+	}
+	if m.body != body {
+		detach(m.body)
+	}
+	m.body = body
+	m.bodyItem.primitive = body
+	return m
+}
+
+// GetBody returns the primitive shown above the buttons.
+func (m *Modal) GetBody() Primitive {
+	return m.body
+}
+
+// SetSize sets the fixed size of the window, including its border. A value of
+// 0 means that the size is calculated: the width from the screen width and the
+// buttons, the height from the message text or, for other bodies, from the
+// screen height.
+func (m *Modal) SetSize(width, height int) *Modal {
+	m.width, m.height = width, height
+	return m
+}
+
+// SetMinSize sets the minimum size of the window, including its border. A value
+// of 0 means no minimum. The window never exceeds the screen.
+func (m *Modal) SetMinSize(width, height int) *Modal {
+	m.minWidth, m.minHeight = width, height
+	return m
+}
+
+// SetMaxSize sets the maximum size of the window, including its border. A value
+// of 0 means no maximum other than the screen size.
+func (m *Modal) SetMaxSize(width, height int) *Modal {
+	m.maxWidth, m.maxHeight = width, height
+	return m
+}
+
+// SetButtonsAlign sets how the buttons are aligned horizontally, one of
+// AlignLeft, AlignCenter (the default), or AlignRight.
+func (m *Modal) SetButtonsAlign(align int) *Modal {
+	m.form.SetButtonsAlign(align)
+	return m
+}
+
+// SetButtonShortcut sets a key which selects the button with the given index.
+// The key selects the button while any button has focus. Pressed together with
+// Alt, it also selects the button while the body has focus. Letters are not
+// case-sensitive. The key is not shown, so the label should mention it, e.g.
+// "Yes (y)".
+func (m *Modal) SetButtonShortcut(buttonIndex int, shortcut rune) *Modal {
+	for key, index := range m.shortcuts {
+		if index == buttonIndex {
+			delete(m.shortcuts, key)
+		}
+	}
+	m.shortcuts[unicode.ToLower(shortcut)] = buttonIndex
+	return m
+}
+
+// SetEscapeButton sets the index of the button which is selected when the user
+// presses Escape. With a negative index (the default), Escape calls the "done"
+// handler with a negative index and an empty label.
+func (m *Modal) SetEscapeButton(buttonIndex int) *Modal {
+	m.escapeButton = buttonIndex
 	return m
 }
 
 // AddButtons adds buttons to the window. There must be at least one button and
 // a "done" handler so the window can be closed again.
 func (m *Modal) AddButtons(labels []string) *Modal {
-	for index, label := range labels {
+	for _, label := range labels {
 		func(i int, l string) {
-			m.form.AddButton(label, func() {
+			m.form.AddButton(l, func() {
 				if m.done != nil {
 					m.done(i, l)
 				}
 			})
-		}(index, label)
+			m.form.buttons[len(m.form.buttons)-1].SetInputCapture(m.captureKey(false))
+		}(len(m.form.buttons), label)
 	}
 	return m
 }
 
-// Focus is called when this primitive receives focus.
+// selectButton selects the button with the given index.
+func (m *Modal) selectButton(buttonIndex int) {
+	if buttonIndex < 0 || buttonIndex >= len(m.form.buttons) {
+		return
+	}
+	if button := m.form.buttons[buttonIndex]; button.selected != nil {
+		button.selected()
+	}
+}
+
+// escape is called when the user presses Escape.
+func (m *Modal) escape() {
+	if m.escapeButton >= 0 && m.escapeButton < len(m.form.buttons) {
+		m.selectButton(m.escapeButton)
+	} else if m.done != nil {
+		m.done(-1, "")
+	}
+}
+
+// captureKey returns the input capture of the buttons or, if "body" is true,
+// of the body, which handles the button shortcuts and scrolls the message
+// text.
+func (m *Modal) captureKey(body bool) func(event pixelgl.Event) pixelgl.Event {
+	return func(event pixelgl.Event) pixelgl.Event {
+		ev, ok := event.(*pixelgl.KeyEv)
+		if !ok {
+			return event
+		}
+		if ev.Key == pixelgl.KeyRune && (!body && ev.Mods&^pixelgl.ModShift == 0 || ev.Mods&pixelgl.ModAlt != 0) {
+			if index, ok := m.shortcuts[unicode.ToLower(ev.Ch)]; ok {
+				m.selectButton(index)
+				return nil
+			}
+		}
+		if !body && m.body == m.textView {
+			switch ev.Key {
+			case pixelgl.KeyUp, pixelgl.KeyDown, pixelgl.KeyPageUp, pixelgl.KeyPageDown, pixelgl.KeyHome, pixelgl.KeyEnd:
+				if handler := m.textView.KeyHandler(); handler != nil {
+					handler(event, func(p Primitive) {})
+				}
+				return nil
+			}
+		}
+		return event
+	}
+}
+
+// Focus is called when this primitive receives focus. The focus goes to the
+// first button.
 func (m *Modal) Focus(delegate func(p Primitive)) {
+	if len(m.form.buttons) > 0 {
+		m.form.focusedElement = 1
+	}
 	delegate(m.form)
 }
 
@@ -98,8 +318,51 @@ func (m *Modal) HasFocus() bool {
 	return m.form.HasFocus()
 }
 
+// Detach passes the call on to the window's contents. The window appears again
+// (see SetTransition()) when it is drawn next.
+func (m *Modal) Detach() {
+	m.appeared = false
+	if m.animation != nil {
+		m.animation.Cancel()
+		m.animation = nil
+	}
+	detach(m.frame)
+}
+
+// MouseHandler returns the mouse handler for this primitive. The mouse wheel
+// scrolls the message text.
+func (m *Modal) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return m.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		if scroll, ok := event.(*pixelgl.ScrollEvent); ok && m.body == m.textView {
+			if scroll.Y > 0 {
+				m.textView.trackEnd = false
+				m.textView.lineOffset--
+			} else if scroll.Y < 0 {
+				m.textView.lineOffset++
+			}
+			return
+		}
+		passMouse(m.frame, event, setFocus)
+	})
+}
+
 // Draw draws this primitive onto the screen.
 func (m *Modal) Draw(screen ubcell.Screen) {
+	m.app = screenApp(screen) // The modal's box is not drawn.
+
+	// Start or advance the appearance.
+	m.animator.Step(screen)
+	if !m.appeared {
+		m.appeared = true
+		if m.transitionKind != TransitionNone && m.transitionDuration > 0 {
+			m.progress = 0
+			m.animation = m.animator.Animate(m.transitionDuration, m.transitionEasing, func(progress float64) {
+				m.progress = progress
+			})
+			blockInput(screen, time.Now().Add(m.transitionDuration))
+		}
+	}
+
 	// Calculate the width of this modal.
 	buttonsWidth := 0
 	for _, button := range m.form.buttons {
@@ -107,27 +370,81 @@ func (m *Modal) Draw(screen ubcell.Screen) {
 	}
 	buttonsWidth -= 2
 	screenWidth, screenHeight := screen.Size()
-	width := screenWidth / 3
-	if width < buttonsWidth {
-		width = buttonsWidth
+	width := m.width
+	if width <= 0 {
+		width = screenWidth / 3
+		if width < buttonsWidth {
+			width = buttonsWidth
+		}
+		width += 4 // The border and the padding.
 	}
-	// width is now without the box border.
+	width = clampModalSize(width, m.minWidth, m.maxWidth, screenWidth)
 
-	// Reset the text and find out how wide it is.
-	m.frame.Clear()
-	lines := WordWrap(m.text, width)
-	for _, line := range lines {
-		m.frame.AddText(line, true, AlignCenter, m.textColor)
+	// The rows below the body: an empty row and the buttons.
+	buttonsHeight := 0
+	if len(m.form.buttons) > 0 {
+		buttonsHeight = 2
 	}
 
+	// Calculate the height. The body is one column narrower than the window's
+	// inner width as the form reserves a column for its empty label.
+	height := m.height
+	if height <= 0 {
+		if m.body == m.textView {
+			height = len(WordWrap(m.text, width-5)) + buttonsHeight + 4
+		} else {
+			height = screenHeight / 2
+		}
+	}
+	height = clampModalSize(height, m.minHeight, m.maxHeight, screenHeight)
+	bodyHeight := height - buttonsHeight - 4
+	if bodyHeight < 1 {
+		bodyHeight = 1
+	}
+	m.bodyItem.SetFieldHeight(bodyHeight)
+
 	// Set the modal's position and size.
-	height := len(lines) + 6
-	width += 4
 	x := (screenWidth - width) / 2
 	y := (screenHeight - height) / 2
 	m.SetRect(x, y, width, height)
 
 	// Draw the frame.
 	m.frame.SetRect(x, y, width, height)
+	if m.animation == nil || !m.animation.IsRunning() {
+		m.frame.Draw(screen)
+		return
+	}
+	if m.transitionKind == TransitionFade {
+		drawBlended(screen, m.frame, m.progress)
+		return
+	}
+	var dx, dy int
+	switch m.transitionDirection {
+	case TransitionLeft:
+		dx = screenWidth - x
+	case TransitionRight:
+		dx = -x - width
+	case TransitionUp:
+		dy = screenHeight - y
+	case TransitionDown:
+		dy = -y - height
+	}
+	remaining := 1 - m.progress
+	m.frame.SetRect(x+int(remaining*float64(dx)), y+int(remaining*float64(dy)), width, height)
 	m.frame.Draw(screen)
 }
+
+// clampModalSize applies a minimum and a maximum (each ignored if 0) to a size
+// and makes sure it does not exceed the screen size.
+func clampModalSize(size, min, max, screenSize int) int {
+	if max > 0 && size > max {
+		size = max
+	}
+	if min > 0 && size < min {
+		size = min
+	}
+	if size > screenSize {
+		size = screenSize
+	}
+	return size
+}
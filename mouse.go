@@ -0,0 +1,97 @@
+package tview
+
+import (
+	"time"
+
+	"github.com/nowakf/pixel/pixelgl"
+)
+
+// Mouse events are delivered by the application to the MouseHandler() of the
+// root primitive. The coordinates of cursor events (pixelgl.CursorEvent.X and
+// Y) are screen cells, the same coordinates which are used by SetRect() and
+// Draw(). Containers pass cursor events on to the child below the cursor.
+// Scroll events carry no position and are passed on to the child which has
+// focus.
+
+// DoubleClickInterval is the maximum time between two clicks of a double
+// click.
+var DoubleClickInterval = 500 * time.Millisecond
+
+// grabMouse makes the application send all cursor events directly to the given
+// primitive until releaseMouse() is called, e.g. while the user drags a
+// SplitPane's divider, even when the cursor leaves the primitive. Primitives
+// which were not drawn by an application have a nil application, which
+// ignores the grab.
+func (a *Application) grabMouse(p Primitive) {
+	if a == nil {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.mouseGrab = p
+}
+
+// releaseMouse ends a grab started with grabMouse().
+func (a *Application) releaseMouse() {
+	a.grabMouse(nil)
+}
+
+// grabbedMouse returns the primitive which grabbed the mouse or nil if there is
+// none.
+func (a *Application) grabbedMouse() Primitive {
+	a.RLock()
+	defer a.RUnlock()
+	return a.mouseGrab
+}
+
+// InRect returns true if the given screen cell lies within the box's
+// rectangle.
+func (b *Box) InRect(x, y int) bool {
+	return inRect(b, x, y)
+}
+
+// inRect returns true if the given screen cell lies within the primitive's
+// rectangle.
+func inRect(p Primitive, x, y int) bool {
+	rectX, rectY, width, height := p.GetRect()
+	return x >= rectX && x < rectX+width && y >= rectY && y < rectY+height
+}
+
+// leftClick returns the screen cell of a press of the left mouse button. ok is
+// false if the event is something else.
+func leftClick(event pixelgl.Event) (x, y int, ok bool) {
+	ev, isCursor := event.(*pixelgl.CursorEvent)
+	if !isCursor || ev.Button != pixelgl.MouseButtonLeft || ev.Act != pixelgl.PRESS {
+		return 0, 0, false
+	}
+	return ev.X, ev.Y, true
+}
+
+// concernsMouse returns whether a mouse event concerns the given primitive,
+// i.e. whether a cursor event lies within its rectangle or whether a scroll
+// event occurs while it has focus.
+func concernsMouse(p Primitive, event pixelgl.Event) bool {
+	if p == nil {
+		return false
+	}
+	switch ev := event.(type) {
+	case *pixelgl.CursorEvent:
+		return inRect(p, ev.X, ev.Y)
+	case *pixelgl.ScrollEvent:
+		return p.GetFocusable().HasFocus()
+	}
+	return false
+}
+
+// passMouse passes a mouse event on to the given primitive's mouse handler if
+// the event concerns the primitive (see concernsMouse()). It returns whether
+// the event was passed on.
+func passMouse(p Primitive, event pixelgl.Event, setFocus func(p Primitive)) bool {
+	if !concernsMouse(p, event) {
+		return false
+	}
+	if handler := p.MouseHandler(); handler != nil {
+		handler(event, setFocus)
+	}
+	return true
+}
@@ -0,0 +1,370 @@
+package tview
+
+import (
+	"image/color"
+	"sync"
+	"time"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+	"golang.org/x/image/colornames"
+)
+
+// The severities of notifications.
+const (
+	NotifyInfo = iota
+	NotifySuccess
+	NotifyWarning
+	NotifyError
+)
+
+// The corners of the screen in which notifications are stacked.
+const (
+	CornerTopLeft = iota
+	CornerTopRight
+	CornerBottomLeft
+	CornerBottomRight
+)
+
+// NotificationStyle defines how notifications of one severity look.
+type NotificationStyle struct {
+	Title           string     // The title shown in the border.
+	BorderColor     color.RGBA // The color of the border and the title.
+	TextColor       color.RGBA // The color of the text.
+	BackgroundColor color.RGBA // The background color.
+}
+
+// Toast is a notification shown by Notifications.
+type Toast struct {
+	// The notifications the toast belongs to.
+	notifications *Notifications
+
+	// The notification's text and severity.
+	text     string
+	severity int
+
+	// The time at which the toast is dismissed automatically. Zero if it stays
+	// until the user dismisses it.
+	expires time.Time
+
+	// The box used to draw the toast.
+	box *Box
+
+	// An optional function which is called when the toast disappears.
+	dismissed func()
+}
+
+// SetText changes the toast's text. Like all functions of Toast, it may be
+// called from any goroutine. The screen is updated when it is drawn next.
+func (t *Toast) SetText(text string) *Toast {
+	t.notifications.Lock()
+	defer t.notifications.Unlock()
+	t.text = text
+	return t
+}
+
+// SetDuration sets the time after which the toast is dismissed automatically,
+// counted from now. 0 keeps it until the user dismisses it.
+func (t *Toast) SetDuration(duration time.Duration) *Toast {
+	t.notifications.Lock()
+	defer t.notifications.Unlock()
+	if duration > 0 {
+		t.expires = time.Now().Add(duration)
+	} else {
+		t.expires = time.Time{}
+	}
+	return t
+}
+
+// SetDismissedFunc sets a handler which is called when the toast disappears,
+// either automatically or because the user dismissed it.
+func (t *Toast) SetDismissedFunc(handler func()) *Toast {
+	t.notifications.Lock()
+	defer t.notifications.Unlock()
+	t.dismissed = handler
+	return t
+}
+
+// Dismiss removes the toast.
+func (t *Toast) Dismiss() {
+	t.notifications.dismiss(t)
+}
+
+// Notifications manages transient messages ("toasts") which an application
+// draws stacked in a corner of the screen, above the root primitive and after
+// the after-draw handler. They never receive focus. Each application has a
+// Notifications object, see Application.GetNotifications() and
+// Application.Notify().
+//
+// Toasts disappear automatically after a while (see SetDuration()), when the
+// user clicks them, or when the user presses the dismiss key (Alt-X by
+// default, see SetDismissKey()), which removes the newest toast.
+type Notifications struct {
+	sync.Mutex
+
+	// The toasts, from the oldest to the newest.
+	toasts []*Toast
+
+	// The corner in which toasts are stacked.
+	corner int
+
+	// The maximum width of a toast, including its border.
+	width int
+
+	// The default time after which toasts are dismissed. 0 means never.
+	duration time.Duration
+
+	// The styles of the severities.
+	styles map[int]NotificationStyle
+
+	// The key which dismisses the newest toast.
+	dismissKey pixelgl.Key
+	dismissCh  rune
+	dismissMod pixelgl.ModifierKey
+}
+
+// NewNotifications returns a new, empty notifications manager. Applications
+// create their own (see Application.GetNotifications()), so this is rarely
+// needed.
+func NewNotifications() *Notifications {
+	return &Notifications{
+		corner:   CornerBottomRight,
+		width:    40,
+		duration: 4 * time.Second,
+		styles: map[int]NotificationStyle{
+			NotifyInfo:    {Title: "Info", BorderColor: colornames.Steelblue, TextColor: Styles.PrimaryTextColor, BackgroundColor: Styles.ContrastBackgroundColor},
+			NotifySuccess: {Title: "Success", BorderColor: colornames.Green, TextColor: Styles.PrimaryTextColor, BackgroundColor: Styles.ContrastBackgroundColor},
+			NotifyWarning: {Title: "Warning", BorderColor: colornames.Orange, TextColor: Styles.PrimaryTextColor, BackgroundColor: Styles.ContrastBackgroundColor},
+			NotifyError:   {Title: "Error", BorderColor: Styles.ErrorTextColor, TextColor: Styles.PrimaryTextColor, BackgroundColor: Styles.ContrastBackgroundColor},
+		},
+		dismissKey: pixelgl.KeyRune,
+		dismissCh:  'x',
+		dismissMod: pixelgl.ModAlt,
+	}
+}
+
+// SetCorner sets the corner of the screen in which toasts are stacked, one of
+// CornerTopLeft, CornerTopRight, CornerBottomLeft, or CornerBottomRight (the
+// default). The newest toast is closest to the corner.
+func (n *Notifications) SetCorner(corner int) *Notifications {
+	n.Lock()
+	defer n.Unlock()
+	n.corner = corner
+	return n
+}
+
+// SetWidth sets the maximum width of a toast, including its border. It
+// defaults to 40.
+func (n *Notifications) SetWidth(width int) *Notifications {
+	n.Lock()
+	defer n.Unlock()
+	n.width = width
+	return n
+}
+
+// SetDuration sets the time after which new toasts are dismissed
+// automatically. It defaults to 4 seconds. 0 keeps toasts until the user
+// dismisses them.
+func (n *Notifications) SetDuration(duration time.Duration) *Notifications {
+	n.Lock()
+	defer n.Unlock()
+	n.duration = duration
+	return n
+}
+
+// SetStyle sets how toasts of the given severity (e.g. NotifyWarning) look.
+func (n *Notifications) SetStyle(severity int, style NotificationStyle) *Notifications {
+	n.Lock()
+	defer n.Unlock()
+	n.styles[severity] = style
+	return n
+}
+
+// SetDismissKey sets the key which dismisses the newest toast: the key, the
+// character for pixelgl.KeyRune, and the modifier keys which must be held. It
+// defaults to Alt-X.
+func (n *Notifications) SetDismissKey(key pixelgl.Key, ch rune, mods pixelgl.ModifierKey) *Notifications {
+	n.Lock()
+	defer n.Unlock()
+	n.dismissKey, n.dismissCh, n.dismissMod = key, ch, mods
+	return n
+}
+
+// Add shows a new toast with the given severity (e.g. NotifyInfo) and text.
+// The text may contain color tags. It may be called from any goroutine but
+// the toast only appears when the screen is drawn next. Application.Notify()
+// takes care of this.
+func (n *Notifications) Add(severity int, text string) *Toast {
+	n.Lock()
+	defer n.Unlock()
+	toast := &Toast{
+		notifications: n,
+		text:          text,
+		severity:      severity,
+		box:           NewBox().SetBorder(true),
+	}
+	if n.duration > 0 {
+		toast.expires = time.Now().Add(n.duration)
+	}
+	n.toasts = append(n.toasts, toast)
+	return toast
+}
+
+// Clear removes all toasts.
+func (n *Notifications) Clear() *Notifications {
+	n.Lock()
+	toasts := append([]*Toast(nil), n.toasts...)
+	n.Unlock()
+	for _, toast := range toasts {
+		n.dismiss(toast)
+	}
+	return n
+}
+
+// GetCount returns the number of toasts shown.
+func (n *Notifications) GetCount() int {
+	n.Lock()
+	defer n.Unlock()
+	return len(n.toasts)
+}
+
+// dismiss removes a toast and calls its handler.
+func (n *Notifications) dismiss(toast *Toast) {
+	n.Lock()
+	var dismissed func()
+	for index, t := range n.toasts {
+		if t == toast {
+			n.toasts = append(n.toasts[:index], n.toasts[index+1:]...)
+			dismissed = toast.dismissed
+			break
+		}
+	}
+	n.Unlock()
+	if dismissed != nil {
+		dismissed()
+	}
+}
+
+// nextExpiry returns the time at which the next toast expires, or the zero
+// time if no toast expires.
+func (n *Notifications) nextExpiry() (next time.Time) {
+	n.Lock()
+	defer n.Unlock()
+	for _, toast := range n.toasts {
+		if !toast.expires.IsZero() && (next.IsZero() || toast.expires.Before(next)) {
+			next = toast.expires
+		}
+	}
+	return
+}
+
+// expire dismisses the toasts which expired. It returns whether there were
+// any.
+func (n *Notifications) expire() bool {
+	now := time.Now()
+	var expired []*Toast
+	n.Lock()
+	for _, toast := range n.toasts {
+		if !toast.expires.IsZero() && !toast.expires.After(now) {
+			expired = append(expired, toast)
+		}
+	}
+	n.Unlock()
+	for _, toast := range expired {
+		n.dismiss(toast)
+	}
+	return len(expired) > 0
+}
+
+// handleKey dismisses the newest toast if the event is the dismiss key. It
+// returns whether the key was consumed.
+func (n *Notifications) handleKey(ev *pixelgl.KeyEv) bool {
+	n.Lock()
+	if len(n.toasts) == 0 || ev.Key != n.dismissKey || ev.Mods != n.dismissMod || n.dismissKey == pixelgl.KeyRune && ev.Ch != n.dismissCh {
+		n.Unlock()
+		return false
+	}
+	newest := n.toasts[len(n.toasts)-1]
+	n.Unlock()
+	n.dismiss(newest)
+	return true
+}
+
+// handleClick dismisses the toast which was clicked with the left mouse
+// button. It returns whether a toast was clicked.
+func (n *Notifications) handleClick(ev *pixelgl.CursorEvent) bool {
+	n.Lock()
+	var clicked *Toast
+	for _, toast := range n.toasts {
+		if toast.box.InRect(ev.X, ev.Y) {
+			clicked = toast
+		}
+	}
+	n.Unlock()
+	if clicked == nil {
+		return false
+	}
+	if ev.Button == pixelgl.MouseButtonLeft && ev.Act == pixelgl.PRESS {
+		n.dismiss(clicked)
+	}
+	return true
+}
+
+// Draw draws the toasts onto the screen, from the newest (closest to the
+// corner) to the oldest, as many as fit.
+func (n *Notifications) Draw(screen ubcell.Screen) {
+	n.Lock()
+	defer n.Unlock()
+
+	screenWidth, screenHeight := screen.Size()
+	width := n.width
+	if width > screenWidth {
+		width = screenWidth
+	}
+	if width < 5 {
+		return
+	}
+	top := n.corner == CornerTopLeft || n.corner == CornerTopRight
+	left := n.corner == CornerTopLeft || n.corner == CornerBottomLeft
+	x := screenWidth - width
+	if left {
+		x = 0
+	}
+	y := 0
+	if !top {
+		y = screenHeight
+	}
+
+	for index := len(n.toasts) - 1; index >= 0; index-- {
+		toast := n.toasts[index]
+		style := n.styles[toast.severity]
+		lines := WordWrap(toast.text, width-4)
+		height := len(lines) + 2
+		if !top {
+			y -= height
+		}
+		if y < 0 || y+height > screenHeight {
+			// Hide the toasts which don't fit.
+			for ; index >= 0; index-- {
+				n.toasts[index].box.SetRect(0, 0, 0, 0)
+			}
+			break
+		}
+
+		toast.box.SetBorderColor(style.BorderColor).
+			SetTitle(style.Title).
+			SetTitleColor(style.BorderColor).
+			SetBackgroundColor(style.BackgroundColor).
+			SetBorderPadding(0, 0, 1, 1).
+			SetRect(x, y, width, height)
+		toast.box.Draw(screen)
+		innerX, innerY, innerWidth, _ := toast.box.GetInnerRect()
+		for row, line := range lines {
+			Print(screen, line, innerX, innerY+row, innerWidth, AlignLeft, style.TextColor)
+		}
+
+		if top {
+			y += height
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package tview
 
 import (
+	"time"
+
+	"github.com/nowakf/pixel/pixelgl"
 	"github.com/nowakf/ubcell"
 )
 
@@ -12,10 +15,38 @@ type page struct {
 	Visible bool      // Whether or not this page is visible.
 }
 
+// Transitions between pages, see Pages.SetTransition().
+const (
+	TransitionNone  = iota // Pages appear and disappear instantly.
+	TransitionSlide        // The upper of the old and the new page slides in or out.
+	TransitionPush         // The new page pushes the old one out.
+	TransitionFade         // The old page fades into the new one.
+)
+
+// Directions of sliding transitions. They specify the direction in which the
+// pages move, e.g. with TransitionLeft, a new page enters from the right.
+const (
+	TransitionLeft = iota
+	TransitionRight
+	TransitionUp
+	TransitionDown
+)
+
+// pageTransition is a transition between two pages which is currently running.
+type pageTransition struct {
+	from, to        *page // The outgoing and the incoming page. Either may be nil.
+	kind, direction int
+	progress        float64    // The eased progress, from 0 to 1.
+	animation       *Animation // The animation advancing the progress.
+}
+
 // Pages is a container for other primitives often used as the application's
 // root primitive. It allows to easily switch the visibility of the contained
 // primitives.
 //
+// Changes of the visible pages may be animated, see SetTransition(). User input
+// is discarded while a transition is running.
+//
 // See https://github.com/rivo/tview/wiki/Pages for an example.
 type Pages struct {
 	*Box
@@ -30,12 +61,28 @@ type Pages struct {
 	// An optional handler which is called whenever the visibility or the order of
 	// pages changes.
 	changed func()
+
+	// The transition used when the visible pages change. See SetTransition().
+	transitionKind      int
+	transitionDirection int
+	transitionDuration  time.Duration
+	transitionEasing    EasingFunc
+
+	// The transition which is currently running, if any.
+	transition *pageTransition
+
+	// An optional handler which is called when a transition has finished.
+	transitionDone func()
+
+	// The animator running the transitions.
+	animator *Animator
 }
 
 // NewPages returns a new Pages object.
 func NewPages() *Pages {
 	p := &Pages{
-		Box: NewBox(),
+		Box:      NewBox(),
+		animator: NewAnimator(),
 	}
 	p.focus = p
 	return p
@@ -48,6 +95,86 @@ func (p *Pages) SetChangedFunc(handler func()) *Pages {
 	return p
 }
 
+// SetTransition sets how changes of the visible pages are animated by
+// SwitchToPage(), ShowPage(), and HidePage(). "kind" is one of the Transition
+// constants, "direction" is the direction in which sliding pages move (one of
+// TransitionLeft, TransitionRight, TransitionUp, and TransitionDown), and the
+// easing function maps the linear progress of the transition to the shown
+// progress (EaseInOut if nil). For example:
+//
+//   pages.SetTransition(tview.TransitionPush, tview.TransitionLeft, 300*time.Millisecond, tview.EaseOut)
+//
+// A kind of TransitionNone (the default) or a duration of 0 disables
+// transitions.
+func (p *Pages) SetTransition(kind, direction int, duration time.Duration, easing EasingFunc) *Pages {
+	if easing == nil {
+		easing = EaseInOut
+	}
+	p.transitionKind = kind
+	p.transitionDirection = direction
+	p.transitionDuration = duration
+	p.transitionEasing = easing
+	return p
+}
+
+// SetTransitionDoneFunc sets a handler which is called when a transition has
+// finished.
+func (p *Pages) SetTransitionDoneFunc(handler func()) *Pages {
+	p.transitionDone = handler
+	return p
+}
+
+// startTransition starts a transition from the outgoing to the incoming page
+// if transitions are enabled. A transition which is still running is finished
+// first.
+func (p *Pages) startTransition(from, to *page) {
+	p.finishTransition()
+	if p.transitionKind == TransitionNone || p.transitionDuration <= 0 || from == nil && to == nil {
+		return
+	}
+	transition := &pageTransition{
+		from:      from,
+		to:        to,
+		kind:      p.transitionKind,
+		direction: p.transitionDirection,
+	}
+	transition.animation = p.animator.Animate(p.transitionDuration, p.transitionEasing, func(progress float64) {
+		transition.progress = progress
+	}).SetDoneFunc(p.finishTransition)
+	p.transition = transition
+	if p.app != nil {
+		p.app.blockInput(transition.animation.start.Add(p.transitionDuration))
+	}
+}
+
+// finishTransition ends the current transition, if any, and calls the
+// transition handler.
+func (p *Pages) finishTransition() {
+	if p.transition == nil {
+		return
+	}
+	p.transition.animation.Cancel()
+	if from := p.transition.from; from != nil && !from.Visible {
+		detach(from.Item)
+	}
+	p.transition = nil
+	if p.transitionDone != nil {
+		p.transitionDone()
+	}
+}
+
+// frontPage returns the visible page which is drawn last, or nil if no page is
+// visible.
+func (p *Pages) frontPage() *page {
+	var front *page
+	for _, page := range p.pages {
+		if page.Visible {
+			front = page
+		}
+	}
+	return front
+}
+
 // AddPage adds a new page with the given name and primitive. If there was
 // previously a page with the same name, it is overwritten. Leaving the name
 // empty may cause conflicts in other functions.
@@ -60,6 +187,9 @@ func (p *Pages) AddPage(name string, item Primitive, resize, visible bool) *Page
 	for index, pg := range p.pages {
 		if pg.Name == name {
 			p.pages = append(p.pages[:index], p.pages[index+1:]...)
+			if pg.Visible && pg.Item != item {
+				detach(pg.Item)
+			}
 			break
 		}
 	}
@@ -87,8 +217,11 @@ func (p *Pages) RemovePage(name string) *Pages {
 	for index, page := range p.pages {
 		if page.Name == name {
 			p.pages = append(p.pages[:index], p.pages[index+1:]...)
-			if page.Visible && p.changed != nil {
-				p.changed()
+			if page.Visible {
+				detach(page.Item)
+				if p.changed != nil {
+					p.changed()
+				}
 			}
 			break
 		}
@@ -114,6 +247,9 @@ func (p *Pages) HasPage(name string) bool {
 func (p *Pages) ShowPage(name string) *Pages {
 	for _, page := range p.pages {
 		if page.Name == name {
+			if !page.Visible {
+				p.startTransition(nil, page)
+			}
 			page.Visible = true
 			if p.changed != nil {
 				p.changed()
@@ -131,6 +267,12 @@ func (p *Pages) ShowPage(name string) *Pages {
 func (p *Pages) HidePage(name string) *Pages {
 	for _, page := range p.pages {
 		if page.Name == name {
+			if page.Visible {
+				p.startTransition(page, nil)
+				if p.transition == nil {
+					detach(page.Item)
+				}
+			}
 			page.Visible = false
 			if p.changed != nil {
 				p.changed()
@@ -147,10 +289,21 @@ func (p *Pages) HidePage(name string) *Pages {
 // SwitchToPage sets a page's visibility to "true" and all other pages'
 // visibility to "false".
 func (p *Pages) SwitchToPage(name string) *Pages {
+	if front := p.frontPage(); front == nil || front.Name != name {
+		for _, page := range p.pages {
+			if page.Name == name {
+				p.startTransition(front, page)
+				break
+			}
+		}
+	}
 	for _, page := range p.pages {
 		if page.Name == name {
 			page.Visible = true
 		} else {
+			if page.Visible && (p.transition == nil || page != p.transition.from) {
+				detach(page.Item)
+			}
 			page.Visible = false
 		}
 	}
@@ -232,16 +385,109 @@ func (p *Pages) Focus(delegate func(p Primitive)) {
 	}
 }
 
+// MouseHandler returns the mouse handler for this primitive. Events are passed
+// on to the topmost visible page below the cursor.
+func (p *Pages) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return p.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		for index := len(p.pages) - 1; index >= 0; index-- {
+			if page := p.pages[index]; page.Visible && passMouse(page.Item, event, setFocus) {
+				return
+			}
+		}
+	})
+}
+
+// Detach passes the call on to all visible pages.
+func (p *Pages) Detach() {
+	p.finishTransition()
+	for _, page := range p.pages {
+		if page.Visible {
+			detach(page.Item)
+		}
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (p *Pages) Draw(screen ubcell.Screen) {
+	p.app = screenApp(screen) // The pages' box is not drawn.
+	p.animator.Step(screen)
+	x, y, width, height := p.GetInnerRect()
+	transition := p.transition
 	for _, page := range p.pages {
-		if !page.Visible {
-			continue
-		}
-		if page.Resize {
-			x, y, width, height := p.GetInnerRect()
+		outgoing := transition != nil && page == transition.from
+		if page.Resize && (page.Visible || outgoing) {
 			page.Item.SetRect(x, y, width, height)
 		}
+		switch {
+		case transition.animates(page):
+			p.drawTransition(screen, page)
+		case page.Visible:
+			page.Item.Draw(screen)
+		}
+	}
+}
+
+// animates returns whether the given page is drawn by the transition: the
+// outgoing page while it is hidden and the incoming page while it is visible.
+func (t *pageTransition) animates(page *page) bool {
+	return t != nil && page != nil &&
+		(page == t.from && !page.Visible || page == t.to && page.Visible)
+}
+
+// drawTransition draws a page of the running transition. Of the outgoing and
+// the incoming page, the one above the other moves (or fades) while the one
+// below stays in place, unless both are pushed.
+func (p *Pages) drawTransition(screen ubcell.Screen, page *page) {
+	transition := p.transition
+	other := transition.to
+	if page == transition.to {
+		other = transition.from
+	}
+	above := true
+	if transition.animates(other) {
+		for _, pg := range p.pages {
+			if pg == page {
+				above = false // The other page is drawn later.
+				break
+			}
+			if pg == other {
+				break
+			}
+		}
+	}
+
+	_, _, width, height := p.GetInnerRect()
+	dx, dy := 0, 0
+	switch transition.direction {
+	case TransitionLeft:
+		dx = -width
+	case TransitionRight:
+		dx = width
+	case TransitionUp:
+		dy = -height
+	case TransitionDown:
+		dy = height
+	}
+	shown, offset := transition.progress, transition.progress-1 // The incoming page.
+	if page == transition.from {
+		shown, offset = 1-transition.progress, transition.progress
+	}
+	switch {
+	case !above && transition.kind != TransitionPush:
 		page.Item.Draw(screen)
+	case transition.kind == TransitionFade:
+		drawBlended(screen, page.Item, shown)
+	default:
+		p.drawMoved(screen, page.Item, offset*float64(dx), offset*float64(dy))
 	}
 }
+
+// drawMoved draws a page's primitive moved by the given number of cells,
+// clipped to the inner rectangle of the pages.
+func (p *Pages) drawMoved(screen ubcell.Screen, item Primitive, dx, dy float64) {
+	x, y, width, height := item.GetRect()
+	item.SetRect(x+int(dx), y+int(dy), width, height)
+	innerX, innerY, innerWidth, innerHeight := p.GetInnerRect()
+	drawClipped(screen, item, innerX, innerY, innerWidth, innerHeight)
+	item.SetRect(x, y, width, height)
+}
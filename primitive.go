@@ -50,3 +50,22 @@ type Primitive interface {
 	// GetFocusable returns the item's Focusable.
 	GetFocusable() Focusable
 }
+
+// Detachable is implemented by primitives which keep working in the background
+// while they are displayed, e.g. a VideoPlayer's playback goroutine. Containers
+// call Detach() when such a primitive is hidden or removed from them so that it
+// can release its resources. It will be drawn again if it becomes visible
+// again.
+//
+// Containers implement Detachable themselves, passing the call on to the
+// primitives they contain.
+type Detachable interface {
+	Detach()
+}
+
+// detach calls Detach() on the given primitive if it implements Detachable.
+func detach(p Primitive) {
+	if d, ok := p.(Detachable); ok {
+		d.Detach()
+	}
+}
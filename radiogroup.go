@@ -0,0 +1,299 @@
+package tview
+
+import (
+	"image/color"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// RadioGroup is a set of mutually exclusive options of which exactly one is
+// selected. The options are shown next to each other (horizontal) or below each
+// other (vertical).
+//
+// The arrow keys select the previous or next option. Clicking an option with
+// the left mouse button selects it.
+type RadioGroup struct {
+	*Box
+
+	// The options from which the user can choose.
+	options []string
+
+	// The index of the selected option.
+	currentOption int
+
+	// Whether the options are arranged horizontally or vertically.
+	horizontal bool
+
+	// The text to be displayed before the options.
+	label string
+
+	// The label color.
+	labelColor color.RGBA
+
+	// The background color of the options.
+	fieldBackgroundColor color.RGBA
+
+	// The text color of the options.
+	fieldTextColor color.RGBA
+
+	// An optional function which is called when the user selects a different
+	// option.
+	changed func(option string, optionIndex int)
+
+	// An optional function which is called when the user leaves the group.
+	done func(*pixelgl.KeyEv)
+}
+
+// NewRadioGroup returns a new, vertical radio group with the given options.
+// The first option is selected.
+func NewRadioGroup(options ...string) *RadioGroup {
+	r := &RadioGroup{
+		Box:                  NewBox(),
+		options:              options,
+		labelColor:           Styles.SecondaryTextColor,
+		fieldBackgroundColor: Styles.ContrastBackgroundColor,
+		fieldTextColor:       Styles.PrimaryTextColor,
+	}
+	r.focus = r
+	return r
+}
+
+// SetOptions replaces the group's options. The selection is kept if possible.
+func (r *RadioGroup) SetOptions(options ...string) *RadioGroup {
+	r.options = options
+	if r.currentOption >= len(options) {
+		r.currentOption = 0
+	}
+	return r
+}
+
+// GetOptionCount returns the number of options.
+func (r *RadioGroup) GetOptionCount() int {
+	return len(r.options)
+}
+
+// SetCurrentOption selects the option with the given index. The changed
+// handler is not called.
+func (r *RadioGroup) SetCurrentOption(index int) *RadioGroup {
+	if index >= 0 && index < len(r.options) {
+		r.currentOption = index
+	}
+	return r
+}
+
+// GetCurrentOption returns the index of the selected option and its text. If
+// the group has no options, -1 and an empty string are returned.
+func (r *RadioGroup) GetCurrentOption() (int, string) {
+	if r.currentOption >= len(r.options) {
+		return -1, ""
+	}
+	return r.currentOption, r.options[r.currentOption]
+}
+
+// SetHorizontal sets the direction in which the options are arranged: next to
+// each other (true) or below each other (false).
+func (r *RadioGroup) SetHorizontal(horizontal bool) *RadioGroup {
+	r.horizontal = horizontal
+	return r
+}
+
+// SetLabel sets the text to be displayed before the options.
+func (r *RadioGroup) SetLabel(label string) *RadioGroup {
+	r.label = label
+	return r
+}
+
+// GetLabel returns the text to be displayed before the options.
+func (r *RadioGroup) GetLabel() string {
+	return r.label
+}
+
+// SetLabelColor sets the color of the label.
+func (r *RadioGroup) SetLabelColor(color color.RGBA) *RadioGroup {
+	r.labelColor = color
+	return r
+}
+
+// SetFieldBackgroundColor sets the background color of the options.
+func (r *RadioGroup) SetFieldBackgroundColor(color color.RGBA) *RadioGroup {
+	r.fieldBackgroundColor = color
+	return r
+}
+
+// SetFieldTextColor sets the text color of the options.
+func (r *RadioGroup) SetFieldTextColor(color color.RGBA) *RadioGroup {
+	r.fieldTextColor = color
+	return r
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (r *RadioGroup) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor color.RGBA) FormItem {
+	r.label = label
+	r.labelColor = labelColor
+	r.backgroundColor = bgColor
+	r.fieldTextColor = fieldTextColor
+	r.fieldBackgroundColor = fieldBgColor
+	return r
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (r *RadioGroup) GetFieldWidth() int {
+	width := 0
+	for index, option := range r.options {
+		optionWidth := StringWidth(option) + 4
+		if r.horizontal {
+			if index > 0 {
+				width += 2
+			}
+			width += optionWidth
+		} else if optionWidth > width {
+			width = optionWidth
+		}
+	}
+	return width
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (r *RadioGroup) GetFieldHeight() int {
+	if r.horizontal || len(r.options) == 0 {
+		return 1
+	}
+	return len(r.options)
+}
+
+// SetChangedFunc sets a handler which is called when the user selects a
+// different option. The handler receives the option's text and index.
+func (r *RadioGroup) SetChangedFunc(handler func(option string, optionIndex int)) *RadioGroup {
+	r.changed = handler
+	return r
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the group.
+// The callback function is provided with the key that was pressed, which is
+// one of the following:
+//
+//   - KeyEscape: Leave the group with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyEnter: Confirm the selection and move to the next field.
+func (r *RadioGroup) SetDoneFunc(handler func(key *pixelgl.KeyEv)) *RadioGroup {
+	r.done = handler
+	return r
+}
+
+// SetFinishedFunc calls SetDoneFunc().
+func (r *RadioGroup) SetFinishedFunc(handler func(key *pixelgl.KeyEv)) FormItem {
+	return r.SetDoneFunc(handler)
+}
+
+// optionPositions returns the screen position and width of each option.
+func (r *RadioGroup) optionPositions() []formPosition {
+	x, y, width, _ := r.GetInnerRect()
+	x += StringWidth(r.label)
+	positions := make([]formPosition, len(r.options))
+	for index, option := range r.options {
+		optionWidth := StringWidth(option) + 4
+		if optionWidth > width {
+			optionWidth = width
+		}
+		positions[index] = formPosition{x: x, y: y, width: optionWidth, height: 1}
+		if r.horizontal {
+			x += optionWidth + 2
+		} else {
+			y++
+		}
+	}
+	return positions
+}
+
+// Draw draws this primitive onto the screen.
+func (r *RadioGroup) Draw(screen ubcell.Screen) {
+	r.Box.Draw(screen)
+
+	// Prepare
+	x, y, width, height := r.GetInnerRect()
+	rightLimit := x + width
+	bottomLimit := y + height
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	Print(screen, r.label, x, y, rightLimit-x, AlignLeft, r.labelColor)
+
+	// Draw options.
+	fieldStyle := ubcell.StyleDefault.Background(r.fieldBackgroundColor).Foreground(r.fieldTextColor)
+	for index, position := range r.optionPositions() {
+		if position.x >= rightLimit || position.y >= bottomLimit {
+			break
+		}
+		marker := "( )"
+		if index == r.currentOption {
+			marker = "(•)"
+		}
+		Print(screen, marker+" "+r.options[index], position.x, position.y, rightLimit-position.x, AlignLeft, r.fieldTextColor)
+		style := fieldStyle
+		if index == r.currentOption && r.focus.HasFocus() {
+			style = ubcell.StyleDefault.Background(r.fieldTextColor).Foreground(r.fieldBackgroundColor)
+		}
+		for cx := position.x; cx < position.x+3 && cx < rightLimit; cx++ {
+			ch, _ := screen.GetContent(cx, position.y)
+			screen.SetContent(cx, position.y, ch, style)
+		}
+	}
+}
+
+// selectOption selects the option with the given index and calls the changed
+// handler if the selection changed.
+func (r *RadioGroup) selectOption(index int) {
+	if index < 0 || index >= len(r.options) || index == r.currentOption {
+		return
+	}
+	r.currentOption = index
+	if r.changed != nil {
+		r.changed(r.options[index], index)
+	}
+}
+
+// KeyHandler returns the handler for this primitive.
+func (r *RadioGroup) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return r.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.KeyEv)
+		if !ok {
+			return
+		}
+		switch ev.Key {
+		case pixelgl.KeyLeft, pixelgl.KeyUp: // Previous option.
+			r.selectOption(r.currentOption - 1)
+		case pixelgl.KeyRight, pixelgl.KeyDown: // Next option.
+			r.selectOption(r.currentOption + 1)
+		case pixelgl.KeyHome:
+			r.selectOption(0)
+		case pixelgl.KeyEnd:
+			r.selectOption(len(r.options) - 1)
+		case pixelgl.KeyTab, pixelgl.KeyEscape, pixelgl.KeyEnter: // We're done.
+			if r.done != nil {
+				r.done(ev)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (r *RadioGroup) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return r.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		x, y, ok := leftClick(event)
+		if !ok || !r.InRect(x, y) {
+			return
+		}
+		if !r.focus.HasFocus() {
+			setFocus(r)
+		}
+		for index, position := range r.optionPositions() {
+			if y == position.y && x >= position.x && x < position.x+position.width {
+				r.selectOption(index)
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,484 @@
+package tview
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// Panes of a SplitPane, see SplitPane.SetCollapsed().
+const (
+	SplitPaneNone = iota
+	SplitPaneFirst
+	SplitPaneSecond
+)
+
+// SplitPane is a container which shows two primitives next to each other
+// (FlexColumn) or above each other (FlexRow), separated by a divider which the
+// user can move to resize them.
+//
+// The divider is dragged with the left mouse button. Double-clicking it resets
+// the ratio to the initial value. Clicking it also gives it keyboard focus, as
+// do FocusDivider() and the shortcut Alt-D (see CaptureShortcuts()), after
+// which the following keys are supported:
+//
+//   - Left arrow, Up arrow: Move the divider left or up by one cell.
+//   - Right arrow, Down arrow: Move the divider right or down by one cell.
+//   - Page Up, Page Down: Move the divider by a tenth of the available space.
+//   - Home: Collapse the first pane (or restore it if it is collapsed).
+//   - End: Collapse the second pane (or restore it if it is collapsed).
+//   - Enter: Reset the ratio to the initial value.
+//   - Tab, Escape: Move the focus back to the panes.
+type SplitPane struct {
+	*Box
+
+	// The two panes. Either may be nil.
+	first, second Primitive
+
+	// FlexColumn (side by side) or FlexRow (above each other).
+	direction int
+
+	// The share of the available space given to the first pane, between 0 and
+	// 1.
+	ratio float64
+
+	// The ratio restored by a double click on the divider.
+	initialRatio float64
+
+	// The minimum sizes of the two panes.
+	minFirst, minSecond int
+
+	// One of the SplitPane constants indicating which pane is collapsed.
+	collapsed int
+
+	// The color of the divider, and its color while it has focus.
+	dividerColor, dividerFocusColor color.RGBA
+
+	// The screen position of the divider along the split direction, and the
+	// position of the split area, as of the last call to Draw().
+	dividerPos, start, available int
+
+	// Whether or not the divider has keyboard focus.
+	dividerFocus bool
+
+	// The function which moved the focus to the split pane the last time, to
+	// move it to the divider later.
+	setFocus func(p Primitive)
+
+	// Whether or not the user is dragging the divider.
+	dragging bool
+
+	// The time of the last click on the divider, to detect double clicks.
+	lastClick time.Time
+
+	// An optional function which is called when the user changed the ratio.
+	changed func(ratio float64)
+}
+
+// NewSplitPane returns a new split pane showing the two given primitives side
+// by side, each taking half of the space.
+func NewSplitPane(first, second Primitive) *SplitPane {
+	s := &SplitPane{
+		Box:               NewBox(),
+		first:             first,
+		second:            second,
+		direction:         FlexColumn,
+		ratio:             0.5,
+		initialRatio:      0.5,
+		dividerColor:      Styles.BorderColor,
+		dividerFocusColor: Styles.SecondaryTextColor,
+	}
+	s.focus = s
+	return s
+}
+
+// SetPanes replaces the two primitives. Either may be nil.
+func (s *SplitPane) SetPanes(first, second Primitive) *SplitPane {
+	for _, p := range []Primitive{s.first, s.second} {
+		if p != nil && p != first && p != second {
+			detach(p)
+		}
+	}
+	s.first, s.second = first, second
+	return s
+}
+
+// GetPanes returns the two primitives.
+func (s *SplitPane) GetPanes() (first, second Primitive) {
+	return s.first, s.second
+}
+
+// SetDirection sets the direction in which the panes are arranged: FlexColumn
+// (side by side, the default) or FlexRow (above each other).
+func (s *SplitPane) SetDirection(direction int) *SplitPane {
+	s.direction = direction
+	return s
+}
+
+// SetRatio sets the share of the available space given to the first pane,
+// between 0 and 1. This also becomes the ratio restored by a double click on
+// the divider.
+func (s *SplitPane) SetRatio(ratio float64) *SplitPane {
+	s.ratio = math.Max(0, math.Min(1, ratio))
+	s.initialRatio = s.ratio
+	return s
+}
+
+// GetRatio returns the share of the available space given to the first pane.
+func (s *SplitPane) GetRatio() float64 {
+	return s.ratio
+}
+
+// ResetRatio restores the ratio set with SetRatio() (0.5 by default) and
+// expands a collapsed pane.
+func (s *SplitPane) ResetRatio() *SplitPane {
+	s.collapsed = SplitPaneNone
+	s.setRatio(s.initialRatio)
+	return s
+}
+
+// SetMinSizes sets the minimum sizes of the two panes along the split
+// direction. They are ignored if the space is too small for both.
+func (s *SplitPane) SetMinSizes(first, second int) *SplitPane {
+	s.minFirst, s.minSecond = first, second
+	return s
+}
+
+// SetCollapsed collapses one of the panes (SplitPaneFirst or SplitPaneSecond),
+// giving all of the space to the other one, or restores the panes
+// (SplitPaneNone). The ratio is kept for when the panes are restored.
+func (s *SplitPane) SetCollapsed(pane int) *SplitPane {
+	s.collapsed = pane
+	return s
+}
+
+// GetCollapsed returns which pane is collapsed, one of the SplitPane
+// constants.
+func (s *SplitPane) GetCollapsed() int {
+	return s.collapsed
+}
+
+// MoveDivider moves the divider by the given number of cells. Negative values
+// move it left or up. A collapsed pane is expanded.
+func (s *SplitPane) MoveDivider(cells int) *SplitPane {
+	s.collapsed = SplitPaneNone
+	s.moveTo(s.dividerPos + cells)
+	return s
+}
+
+// SetDividerColor sets the color of the divider and its color while it has
+// keyboard focus or is dragged.
+func (s *SplitPane) SetDividerColor(color, focusColor color.RGBA) *SplitPane {
+	s.dividerColor = color
+	s.dividerFocusColor = focusColor
+	return s
+}
+
+// SetChangedFunc sets a handler which is called when the user changed the
+// ratio by moving the divider or resetting it. The handler receives the new
+// ratio, which may be passed to SetRatio() later, e.g. to restore the layout
+// when the application is started again.
+func (s *SplitPane) SetChangedFunc(handler func(ratio float64)) *SplitPane {
+	s.changed = handler
+	return s
+}
+
+// setRatio sets the ratio and calls the changed handler if it changed.
+func (s *SplitPane) setRatio(ratio float64) {
+	ratio = math.Max(0, math.Min(1, ratio))
+	if ratio == s.ratio {
+		return
+	}
+	s.ratio = ratio
+	if s.changed != nil {
+		s.changed(ratio)
+	}
+}
+
+// moveTo moves the divider to the given screen position along the split
+// direction.
+func (s *SplitPane) moveTo(pos int) {
+	if s.available <= 0 {
+		return
+	}
+	size := pos - s.start
+	if size < s.minFirst {
+		size = s.minFirst
+	}
+	if size > s.available-s.minSecond {
+		size = s.available - s.minSecond
+	}
+	s.setRatio(float64(size) / float64(s.available))
+}
+
+// firstSize returns the size of the first pane for the given available space.
+func (s *SplitPane) firstSize(available int) int {
+	switch s.collapsed {
+	case SplitPaneFirst:
+		return 0
+	case SplitPaneSecond:
+		return available
+	}
+	size := int(math.Round(s.ratio * float64(available)))
+	if size > available-s.minSecond {
+		size = available - s.minSecond
+	}
+	if size < s.minFirst {
+		size = s.minFirst
+	}
+	if size > available {
+		size = available
+	}
+	if size < 0 {
+		size = 0
+	}
+	return size
+}
+
+// Detach passes the call on to both panes.
+func (s *SplitPane) Detach() {
+	for _, p := range []Primitive{s.first, s.second} {
+		if p != nil {
+			detach(p)
+		}
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (s *SplitPane) Draw(screen ubcell.Screen) {
+	s.Box.Draw(screen)
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	s.start, s.available = x, width-1
+	if s.direction == FlexRow {
+		s.start, s.available = y, height-1
+	}
+	if s.available < 0 {
+		s.available = 0
+	}
+	size := s.firstSize(s.available)
+	s.dividerPos = s.start + size
+
+	// Position the panes.
+	var visible []Primitive
+	if s.direction == FlexRow {
+		if s.first != nil {
+			s.first.SetRect(x, y, width, size)
+		}
+		if s.second != nil {
+			s.second.SetRect(x, s.dividerPos+1, width, s.available-size)
+		}
+	} else {
+		if s.first != nil {
+			s.first.SetRect(x, y, size, height)
+		}
+		if s.second != nil {
+			s.second.SetRect(s.dividerPos+1, y, s.available-size, height)
+		}
+	}
+	if s.first != nil && s.collapsed != SplitPaneFirst {
+		visible = append(visible, s.first)
+	} else if s.first != nil {
+		detach(s.first)
+	}
+	if s.second != nil && s.collapsed != SplitPaneSecond {
+		visible = append(visible, s.second)
+	} else if s.second != nil {
+		detach(s.second)
+	}
+
+	// Draw the divider.
+	dividerColor := s.dividerColor
+	if s.dragging || s.dividerFocus && s.hasFocus {
+		dividerColor = s.dividerFocusColor
+	}
+	style := ubcell.StyleDefault.Background(s.backgroundColor).Foreground(dividerColor)
+	if s.direction == FlexRow {
+		for cx := x; cx < x+width; cx++ {
+			screen.SetContent(cx, s.dividerPos, GraphicsHoriBar, style)
+		}
+	} else {
+		for cy := y; cy < y+height; cy++ {
+			screen.SetContent(s.dividerPos, cy, GraphicsVertBar, style)
+		}
+	}
+
+	// Draw the panes, the focused one last.
+	for _, p := range visible {
+		if p.GetFocusable().HasFocus() {
+			defer p.Draw(screen)
+		} else {
+			p.Draw(screen)
+		}
+	}
+}
+
+// onDivider returns whether the given screen cell lies on the divider.
+func (s *SplitPane) onDivider(x, y int) bool {
+	if !s.InRect(x, y) {
+		return false
+	}
+	if s.direction == FlexRow {
+		return y == s.dividerPos
+	}
+	return x == s.dividerPos
+}
+
+// FocusDivider gives the divider keyboard focus if the split pane or one of
+// its panes has focus. Otherwise, the divider receives focus the next time the
+// split pane does, e.g. with Application.SetFocus().
+func (s *SplitPane) FocusDivider() *SplitPane {
+	s.dividerFocus = true
+	if !s.hasFocus && s.setFocus != nil && s.HasFocus() {
+		s.setFocus(s)
+	}
+	return s
+}
+
+// CaptureShortcuts gives the divider keyboard focus, or moves the focus from
+// the divider back to the panes, if the given event is Alt-D and the split pane
+// or one of its panes has focus. It returns nil in that case. Other events are
+// returned unchanged. As key events are only sent to the primitive which has
+// focus, the shortcut only works if this function is installed as an input
+// capture of the application:
+//
+//   app.SetKeyCapture(splitPane.CaptureShortcuts)
+func (s *SplitPane) CaptureShortcuts(event pixelgl.Event) pixelgl.Event {
+	ev, ok := event.(*pixelgl.KeyEv)
+	if !ok || !altKey(ev, 'd') || !s.HasFocus() {
+		return event
+	}
+	if s.dividerFocus && s.hasFocus {
+		s.dividerFocus = false
+		if s.setFocus != nil {
+			s.setFocus(s)
+		}
+	} else {
+		s.FocusDivider()
+	}
+	return nil
+}
+
+// Focus is called when this primitive receives focus. The focus is passed on
+// to the first visible pane unless the divider has focus.
+func (s *SplitPane) Focus(delegate func(p Primitive)) {
+	s.setFocus = delegate
+	if !s.dividerFocus {
+		if s.first != nil && s.collapsed != SplitPaneFirst {
+			delegate(s.first)
+			return
+		}
+		if s.second != nil && s.collapsed != SplitPaneSecond {
+			delegate(s.second)
+			return
+		}
+	}
+	s.Box.Focus(delegate)
+}
+
+// Blur is called when this primitive loses focus.
+func (s *SplitPane) Blur() {
+	s.Box.Blur()
+	s.dividerFocus = false
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (s *SplitPane) HasFocus() bool {
+	if s.hasFocus {
+		return true
+	}
+	for _, p := range []Primitive{s.first, s.second} {
+		if p != nil && p.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyHandler returns the handler for this primitive. It receives keys while
+// the divider has focus.
+func (s *SplitPane) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return s.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.KeyEv)
+		if !ok {
+			return
+		}
+		switch ev.Key {
+		case pixelgl.KeyLeft, pixelgl.KeyUp:
+			s.MoveDivider(-1)
+		case pixelgl.KeyRight, pixelgl.KeyDown:
+			s.MoveDivider(1)
+		case pixelgl.KeyPageUp:
+			s.MoveDivider(-s.available / 10)
+		case pixelgl.KeyPageDown:
+			s.MoveDivider(s.available / 10)
+		case pixelgl.KeyHome:
+			if s.collapsed == SplitPaneFirst {
+				s.collapsed = SplitPaneNone
+			} else {
+				s.collapsed = SplitPaneFirst
+			}
+		case pixelgl.KeyEnd:
+			if s.collapsed == SplitPaneSecond {
+				s.collapsed = SplitPaneNone
+			} else {
+				s.collapsed = SplitPaneSecond
+			}
+		case pixelgl.KeyEnter:
+			s.ResetRatio()
+		case pixelgl.KeyTab, pixelgl.KeyEscape:
+			s.dividerFocus = false
+			setFocus(s)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *SplitPane) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return s.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.CursorEvent)
+		if !ok || !s.dragging && !s.onDivider(ev.X, ev.Y) {
+			// The event is for one of the panes.
+			if s.collapsed != SplitPaneFirst && passMouse(s.first, event, setFocus) {
+				return
+			}
+			if s.collapsed != SplitPaneSecond {
+				passMouse(s.second, event, setFocus)
+			}
+			return
+		}
+
+		pos := ev.X
+		if s.direction == FlexRow {
+			pos = ev.Y
+		}
+		switch {
+		case s.dragging && ev.Act == pixelgl.RELEASE: // Stop dragging.
+			s.dragging = false
+			s.app.releaseMouse()
+		case s.dragging: // Follow the cursor.
+			s.collapsed = SplitPaneNone
+			s.moveTo(pos)
+		case ev.Button == pixelgl.MouseButtonLeft && ev.Act == pixelgl.PRESS:
+			if time.Since(s.lastClick) < DoubleClickInterval {
+				// Double click.
+				s.lastClick = time.Time{}
+				s.ResetRatio()
+				return
+			}
+			s.lastClick = time.Now()
+			s.dragging = true
+			s.app.grabMouse(s)
+			s.dividerFocus = true
+			if !s.hasFocus {
+				setFocus(s) // Blur() would reset the divider focus.
+			}
+		}
+	})
+}
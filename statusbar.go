@@ -0,0 +1,264 @@
+package tview
+
+import (
+	"image/color"
+	"strings"
+	"sync"
+
+	"github.com/nowakf/ubcell"
+)
+
+// StatusSegment is a piece of text shown in a StatusBar. Its text is either
+// set with SetText() or bound to a function which is called each time the
+// status bar is drawn, see StatusBar.AddSegmentFunc().
+type StatusSegment struct {
+	// The status bar the segment belongs to.
+	bar *StatusBar
+
+	// The segment's text and the optional function which provides it.
+	text  string
+	value func() string
+
+	// The text color.
+	color color.RGBA
+
+	// The minimum width of the segment, to keep changing values from moving
+	// other segments.
+	minWidth int
+}
+
+// SetText sets the segment's text, which may contain color tags. Like all
+// functions of StatusSegment, it may be called from any goroutine. The screen
+// is updated when it is drawn next, e.g. after Application.QueueUpdateDraw().
+func (s *StatusSegment) SetText(text string) *StatusSegment {
+	s.bar.Lock()
+	defer s.bar.Unlock()
+	s.text = text
+	return s
+}
+
+// GetText returns the segment's current text.
+func (s *StatusSegment) GetText() string {
+	s.bar.Lock()
+	defer s.bar.Unlock()
+	return s.currentText()
+}
+
+// SetColor sets the segment's text color.
+func (s *StatusSegment) SetColor(color color.RGBA) *StatusSegment {
+	s.bar.Lock()
+	defer s.bar.Unlock()
+	s.color = color
+	return s
+}
+
+// SetMinWidth sets the minimum width of the segment. Shorter texts are padded
+// with spaces.
+func (s *StatusSegment) SetMinWidth(width int) *StatusSegment {
+	s.bar.Lock()
+	defer s.bar.Unlock()
+	s.minWidth = width
+	return s
+}
+
+// currentText returns the segment's text, calling its value function if it has
+// one.
+func (s *StatusSegment) currentText() string {
+	if s.value != nil {
+		return s.value()
+	}
+	return s.text
+}
+
+// StatusBar is a one-line bar, typically at the bottom of the screen, which
+// shows segments of text aligned to its left, its center, and its right. The
+// segments of each group are separated by a separator (" │ " by default).
+//
+// Segments either have a fixed text which may be updated at any time, even
+// from other goroutines, or they are bound to a function which returns the
+// current value when the bar is drawn:
+//
+//   bar := tview.NewStatusBar()
+//   mode := bar.AddSegment(tview.AlignLeft, "NORMAL")
+//   bar.AddSegmentFunc(tview.AlignRight, func() string {
+//     return time.Now().Format("15:04")
+//   })
+//   mode.SetText("INSERT")
+//
+// If the segments don't fit, the centered segments are hidden first, then the
+// right segments are truncated.
+type StatusBar struct {
+	*Box
+	sync.Mutex
+
+	// The segments aligned to the left, to the center, and to the right.
+	segments map[int][]*StatusSegment
+
+	// The text between segments of the same group.
+	separator string
+
+	// The default text color and the color of the separators.
+	textColor, separatorColor color.RGBA
+}
+
+// NewStatusBar returns a new status bar without segments.
+func NewStatusBar() *StatusBar {
+	s := &StatusBar{
+		Box:            NewBox().SetBackgroundColor(Styles.ContrastBackgroundColor).SetBorderPadding(0, 0, 1, 1),
+		segments:       make(map[int][]*StatusSegment),
+		separator:      " │ ",
+		textColor:      Styles.PrimaryTextColor,
+		separatorColor: Styles.BorderColor,
+	}
+	s.focus = s
+	return s
+}
+
+// SetSeparator sets the text between the segments of the same group.
+func (s *StatusBar) SetSeparator(separator string) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	s.separator = separator
+	return s
+}
+
+// SetTextColor sets the default text color of the segments.
+func (s *StatusBar) SetTextColor(color color.RGBA) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	s.textColor = color
+	return s
+}
+
+// SetSeparatorColor sets the color of the separators.
+func (s *StatusBar) SetSeparatorColor(color color.RGBA) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	s.separatorColor = color
+	return s
+}
+
+// AddSegment adds a segment with the given text to the group of segments with
+// the given alignment (AlignLeft, AlignCenter, or AlignRight). Left and
+// centered segments are added to the right of their group, right segments are
+// added to the left of theirs, i.e. the first segment added with AlignRight is
+// the rightmost one.
+func (s *StatusBar) AddSegment(align int, text string) *StatusSegment {
+	s.Lock()
+	defer s.Unlock()
+	segment := &StatusSegment{
+		bar:   s,
+		text:  text,
+		color: s.textColor,
+	}
+	s.segments[align] = append(s.segments[align], segment)
+	return segment
+}
+
+// AddSegmentFunc works like AddSegment() but the segment's text is returned by
+// the given function, which is called each time the status bar is drawn.
+func (s *StatusBar) AddSegmentFunc(align int, value func() string) *StatusSegment {
+	segment := s.AddSegment(align, "")
+	s.Lock()
+	defer s.Unlock()
+	segment.value = value
+	return segment
+}
+
+// RemoveSegment removes a segment from the status bar.
+func (s *StatusBar) RemoveSegment(segment *StatusSegment) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	for align, segments := range s.segments {
+		for index, seg := range segments {
+			if seg == segment {
+				s.segments[align] = append(segments[:index], segments[index+1:]...)
+				return s
+			}
+		}
+	}
+	return s
+}
+
+// Clear removes all segments.
+func (s *StatusBar) Clear() *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	s.segments = make(map[int][]*StatusSegment)
+	return s
+}
+
+// statusPiece is a text printed by the status bar.
+type statusPiece struct {
+	text  string
+	color color.RGBA
+}
+
+// pieces returns the texts of a group of segments, including the separators,
+// and their total width.
+func (s *StatusBar) pieces(align int) (pieces []statusPiece, width int) {
+	segments := s.segments[align]
+	for index := range segments {
+		segment := segments[index]
+		if align == AlignRight {
+			segment = segments[len(segments)-1-index]
+		}
+		if index > 0 {
+			pieces = append(pieces, statusPiece{text: s.separator, color: s.separatorColor})
+			width += StringWidth(s.separator)
+		}
+		text := segment.currentText()
+		if padding := segment.minWidth - StringWidth(text); padding > 0 {
+			text += strings.Repeat(" ", padding)
+		}
+		pieces = append(pieces, statusPiece{text: text, color: segment.color})
+		width += StringWidth(text)
+	}
+	return
+}
+
+// printPieces prints texts starting at the given position, not exceeding the
+// given right limit.
+func printPieces(screen ubcell.Screen, pieces []statusPiece, x, y, rightLimit int) {
+	for _, piece := range pieces {
+		if x >= rightLimit {
+			return
+		}
+		_, width := Print(screen, piece.text, x, y, rightLimit-x, AlignLeft, piece.color)
+		x += width
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (s *StatusBar) Draw(screen ubcell.Screen) {
+	s.Box.Draw(screen)
+
+	s.Lock()
+	defer s.Unlock()
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	y += height / 2
+
+	left, leftWidth := s.pieces(AlignLeft)
+	center, centerWidth := s.pieces(AlignCenter)
+	right, rightWidth := s.pieces(AlignRight)
+
+	// The left segments have priority.
+	printPieces(screen, left, x, y, x+width)
+
+	// Right segments are truncated if there is not enough space.
+	rightX := x + width - rightWidth
+	if rightX < x+leftWidth+1 {
+		rightX = x + leftWidth + 1
+	}
+	printPieces(screen, right, rightX, y, x+width)
+
+	// Centered segments are only shown if they fit between the others.
+	centerX := x + (width-centerWidth)/2
+	if centerX > x+leftWidth && centerX+centerWidth < rightX {
+		printPieces(screen, center, centerX, y, rightX)
+	}
+}
@@ -23,6 +23,7 @@ var Styles = struct {
 	TertiaryTextColor           color.RGBA // Tertiary text (e.g. subtitles, notes).
 	InverseTextColor            color.RGBA // Text on primary-colored backgrounds.
 	ContrastSecondaryTextColor  color.RGBA // Secondary text on ContrastBackgroundColor-colored backgrounds.
+	ErrorTextColor              color.RGBA // Error text (e.g. validation messages).
 }{
 	PrimitiveBackgroundColor:    colornames.Dimgray,
 	ContrastBackgroundColor:     colornames.Grey,
@@ -35,4 +36,5 @@ var Styles = struct {
 	TertiaryTextColor:           colornames.Lightgoldenrodyellow,
 	InverseTextColor:            colornames.Yellow,
 	ContrastSecondaryTextColor:  colornames.Pink,
+	ErrorTextColor:              colornames.Tomato,
 }
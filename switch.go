@@ -0,0 +1,234 @@
+package tview
+
+import (
+	"image/color"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// Switch is a toggle for boolean values. Its field shows the "on" label followed
+// by a knob on the right when the switch is on, and a knob on the left followed
+// by the "off" label when it is off.
+//
+// The switch is toggled with the Enter or the space key or by clicking it with
+// the left mouse button.
+type Switch struct {
+	*Box
+
+	// Whether or not the switch is on.
+	on bool
+
+	// The texts shown in the field for the two states.
+	onLabel, offLabel string
+
+	// The text to be displayed before the field.
+	label string
+
+	// The label color.
+	labelColor color.RGBA
+
+	// The background color of the field.
+	fieldBackgroundColor color.RGBA
+
+	// The text color of the field.
+	fieldTextColor color.RGBA
+
+	// An optional function which is called when the user toggles the switch.
+	changed func(on bool)
+
+	// An optional function which is called when the user leaves the switch.
+	done func(*pixelgl.KeyEv)
+}
+
+// NewSwitch returns a new switch which is off.
+func NewSwitch() *Switch {
+	s := &Switch{
+		Box:                  NewBox(),
+		onLabel:              "On",
+		offLabel:             "Off",
+		labelColor:           Styles.SecondaryTextColor,
+		fieldBackgroundColor: Styles.ContrastBackgroundColor,
+		fieldTextColor:       Styles.PrimaryTextColor,
+	}
+	s.focus = s
+	return s
+}
+
+// SetOn turns the switch on (true) or off (false). The changed handler is not
+// called.
+func (s *Switch) SetOn(on bool) *Switch {
+	s.on = on
+	return s
+}
+
+// IsOn returns whether or not the switch is on.
+func (s *Switch) IsOn() bool {
+	return s.on
+}
+
+// SetStateLabels sets the texts shown in the field when the switch is on and
+// when it is off. They default to "On" and "Off".
+func (s *Switch) SetStateLabels(on, off string) *Switch {
+	s.onLabel, s.offLabel = on, off
+	return s
+}
+
+// SetLabel sets the text to be displayed before the field.
+func (s *Switch) SetLabel(label string) *Switch {
+	s.label = label
+	return s
+}
+
+// GetLabel returns the text to be displayed before the field.
+func (s *Switch) GetLabel() string {
+	return s.label
+}
+
+// SetLabelColor sets the color of the label.
+func (s *Switch) SetLabelColor(color color.RGBA) *Switch {
+	s.labelColor = color
+	return s
+}
+
+// SetFieldBackgroundColor sets the background color of the field.
+func (s *Switch) SetFieldBackgroundColor(color color.RGBA) *Switch {
+	s.fieldBackgroundColor = color
+	return s
+}
+
+// SetFieldTextColor sets the text color of the field.
+func (s *Switch) SetFieldTextColor(color color.RGBA) *Switch {
+	s.fieldTextColor = color
+	return s
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (s *Switch) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor color.RGBA) FormItem {
+	s.label = label
+	s.labelColor = labelColor
+	s.backgroundColor = bgColor
+	s.fieldTextColor = fieldTextColor
+	s.fieldBackgroundColor = fieldBgColor
+	return s
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (s *Switch) GetFieldWidth() int {
+	width := StringWidth(s.onLabel)
+	if offWidth := StringWidth(s.offLabel); offWidth > width {
+		width = offWidth
+	}
+	return width + 4
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (s *Switch) GetFieldHeight() int {
+	return 1
+}
+
+// SetChangedFunc sets a handler which is called when the user toggles the
+// switch. The handler receives the new state.
+func (s *Switch) SetChangedFunc(handler func(on bool)) *Switch {
+	s.changed = handler
+	return s
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the switch.
+// The callback function is provided with the key that was pressed, which is
+// one of the following:
+//
+//   - KeyEscape: Leave the switch with no specific direction.
+//   - KeyTab: Move to the next field.
+func (s *Switch) SetDoneFunc(handler func(key *pixelgl.KeyEv)) *Switch {
+	s.done = handler
+	return s
+}
+
+// SetFinishedFunc calls SetDoneFunc().
+func (s *Switch) SetFinishedFunc(handler func(key *pixelgl.KeyEv)) FormItem {
+	return s.SetDoneFunc(handler)
+}
+
+// Draw draws this primitive onto the screen.
+func (s *Switch) Draw(screen ubcell.Screen) {
+	s.Box.Draw(screen)
+
+	// Prepare
+	x, y, width, height := s.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	_, drawnWidth := Print(screen, s.label, x, y, rightLimit-x, AlignLeft, s.labelColor)
+	x += drawnWidth
+
+	// Draw the field.
+	fieldWidth := s.GetFieldWidth()
+	if x+fieldWidth > rightLimit {
+		fieldWidth = rightLimit - x
+	}
+	if fieldWidth <= 0 {
+		return
+	}
+	background, foreground := s.fieldBackgroundColor, s.fieldTextColor
+	if s.focus.HasFocus() {
+		background, foreground = foreground, background
+	}
+	fieldStyle := ubcell.StyleDefault.Background(background).Foreground(foreground)
+	for index := 0; index < fieldWidth; index++ {
+		screen.SetContent(x+index, y, ' ', fieldStyle)
+	}
+	if s.on {
+		Print(screen, s.onLabel, x+1, y, fieldWidth-3, AlignLeft, foreground)
+		screen.SetContent(x+fieldWidth-1, y, '●', fieldStyle)
+	} else {
+		screen.SetContent(x, y, '●', fieldStyle)
+		Print(screen, s.offLabel, x+2, y, fieldWidth-3, AlignRight, foreground)
+	}
+}
+
+// toggle flips the switch and calls the changed handler.
+func (s *Switch) toggle() {
+	s.on = !s.on
+	if s.changed != nil {
+		s.changed(s.on)
+	}
+}
+
+// KeyHandler returns the handler for this primitive.
+func (s *Switch) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return s.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.KeyEv)
+		if !ok {
+			return
+		}
+		switch key := ev.Key; key {
+		case pixelgl.KeyRune, pixelgl.KeyEnter: // Toggle.
+			if key == pixelgl.KeyRune && ev.Ch != ' ' {
+				break
+			}
+			s.toggle()
+		case pixelgl.KeyTab, pixelgl.KeyEscape: // We're done.
+			if s.done != nil {
+				s.done(ev)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *Switch) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return s.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		x, y, ok := leftClick(event)
+		if !ok || !s.InRect(x, y) {
+			return
+		}
+		if !s.focus.HasFocus() {
+			setFocus(s)
+		}
+		s.toggle()
+	})
+}
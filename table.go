@@ -3,6 +3,7 @@ package tview
 import (
 	"image/color"
 	"sort"
+	"time"
 
 	colorful "github.com/lucasb-eyer/go-colorful"
 	"github.com/nowakf/pixel/pixelgl"
@@ -211,9 +212,15 @@ type Table struct {
 	// If set to true, the table's last row will always be visible.
 	trackEnd bool
 
+	// Animates changes of the row offset, see SetSmoothScrolling().
+	scroll smoothScroll
+
 	// The number of visible rows the last time the table was drawn.
 	visibleRows int
 
+	// The background color of the selectable cells below the mouse cursor.
+	hoverBackgroundColor color.RGBA
+
 	// An optional function which gets called when the user presses Enter on a
 	// selected cell. If entire rows selected, the column value is undefined.
 	// Likewise for entire columns.
@@ -232,10 +239,11 @@ type Table struct {
 // NewTable returns a new table.
 func NewTable() *Table {
 	return &Table{
-		Box:          NewBox(),
-		bordersColor: Styles.GraphicsColor,
-		separator:    ' ',
-		lastColumn:   -1,
+		Box:                  NewBox(),
+		bordersColor:         Styles.GraphicsColor,
+		separator:            ' ',
+		lastColumn:           -1,
+		hoverBackgroundColor: Styles.ContrastBackgroundColor,
 	}
 }
 
@@ -259,6 +267,14 @@ func (t *Table) SetBordersColor(color color.RGBA) *Table {
 	return t
 }
 
+// SetHoverBackgroundColor sets the background color of the cells below the
+// mouse cursor, i.e. the cell, row, or column which would be selected there
+// (see SetSelectable()). Nothing is highlighted if nothing can be selected.
+func (t *Table) SetHoverBackgroundColor(color color.RGBA) *Table {
+	t.hoverBackgroundColor = color
+	return t
+}
+
 // SetSeparator sets the character used to fill the space between two
 // neighboring cells. This is a space character ' ' per default but you may
 // want to set it to GraphicsVertBar (or any other rune) if the column
@@ -312,6 +328,13 @@ func (t *Table) Select(row, column int) *Table {
 	return t
 }
 
+// SetSmoothScrolling sets the duration of the animation with which the table
+// scrolls to a new row offset. 0 (the default) scrolls instantly.
+func (t *Table) SetSmoothScrolling(duration time.Duration) *Table {
+	t.scroll.duration = duration
+	return t
+}
+
 // SetOffset sets how many rows and columns should be skipped when drawing the
 // table. This is useful for large tables that do not fit on the screen.
 // Navigating a selection can change these values.
@@ -547,7 +570,7 @@ func (t *Table) Draw(screen ubcell.Screen) {
 			break
 		}
 	}
-	for row := t.fixedRows + t.rowOffset; row < len(t.cells); row++ { // Then the remaining rows.
+	for row := t.fixedRows + t.scroll.offset(screen, t.rowOffset); row < len(t.cells); row++ { // Then the remaining rows.
 		if !indexRow(row) {
 			break
 		}
@@ -760,6 +783,21 @@ ColumnLoop:
 		selected   bool
 	})
 	var backgroundColors []color.RGBA
+
+	// Determine the cell below the mouse cursor.
+	hoveredRow, hoveredColumn := -1, -1
+	if t.hovered && (t.rowsSelectable || t.columnsSelectable) {
+		for _, row := range rows {
+			for _, column := range columns {
+				cell := getCell(row, column)
+				if cell != nil && !cell.NotSelectable && cell.y == t.hoverY && t.hoverX >= cell.x && t.hoverX < cell.x+cell.width {
+					hoveredRow, hoveredColumn = row, column
+				}
+			}
+		}
+	}
+	var hoveredCells [][4]int
+
 	for rowY, row := range rows {
 		columnX := 0
 		rowSelected := t.rowsSelectable && !t.columnsSelectable && row == t.selectedRow
@@ -777,6 +815,13 @@ ColumnLoop:
 			}
 			columnSelected := t.columnsSelectable && !t.rowsSelectable && column == t.selectedColumn
 			cellSelected := !cell.NotSelectable && (columnSelected || rowSelected || t.rowsSelectable && t.columnsSelectable && column == t.selectedColumn && row == t.selectedRow)
+			cellHovered := !cell.NotSelectable && hoveredRow >= 0 &&
+				(t.rowsSelectable && !t.columnsSelectable && row == hoveredRow ||
+					t.columnsSelectable && !t.rowsSelectable && column == hoveredColumn ||
+					t.rowsSelectable && t.columnsSelectable && row == hoveredRow && column == hoveredColumn)
+			if cellHovered && !cellSelected {
+				hoveredCells = append(hoveredCells, [4]int{bx, by, bw, bh})
+			}
 			entries, ok := cellsByBackgroundColor[cell.BackgroundColor]
 			cellsByBackgroundColor[cell.BackgroundColor] = append(entries, &struct {
 				x, y, w, h int
@@ -816,6 +861,17 @@ ColumnLoop:
 			}
 		}
 	}
+
+	// Highlight the cells below the mouse cursor.
+	for _, cell := range hoveredCells {
+		for by := cell[1]; by < cell[1]+cell[3] && by < y+height; by++ {
+			for bx := cell[0]; bx < cell[0]+cell[2] && bx < x+width; bx++ {
+				m, style := screen.GetContent(bx, by)
+				fg, _ := style.Decompose()
+				screen.SetContent(bx, by, m, ubcell.StyleDefault.Background(t.hoverBackgroundColor).Foreground(fg))
+			}
+		}
+	}
 }
 
 // KeyHandler returns the handler for this primitive.
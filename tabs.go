@@ -0,0 +1,698 @@
+package tview
+
+import (
+	"image/color"
+
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// Positions of the tab strip, see Tabs.SetTabPosition().
+const (
+	TabsTop = iota
+	TabsBottom
+)
+
+// tab represents one tab of a Tabs object.
+type tab struct {
+	Name     string    // The tab's name.
+	Title    string    // The text shown in the tab strip. May contain color tags.
+	Item     Primitive // The tab's primitive. May be nil.
+	Closable bool      // Whether or not the tab has a close button.
+
+	visible  bool // Whether or not the tab was shown in the strip the last time it was drawn.
+	x, width int  // The screen column and the drawn width of the tab. Undefined if visible is false.
+}
+
+// fullWidth returns the width of the tab in the strip when it is not cut off.
+func (tb *tab) fullWidth() int {
+	width := StringWidth(tb.Title) + 2
+	if tb.Closable {
+		width += 2
+	}
+	return width
+}
+
+// Tabs is a container which shows one of several primitives, together with a
+// strip of tabs to switch between them. The strip is located above (TabsTop,
+// the default) or below (TabsBottom) the primitives. Like Pages, the primitives
+// are identified by names.
+//
+// Tab titles may contain color tags. Closable tabs have a close button ("×").
+// If not all tabs fit into the strip, arrows at its ends indicate that there
+// are more tabs and scroll the strip when clicked.
+//
+// The mouse is used as follows:
+//
+//   - Left click on a tab: Switch to the tab.
+//   - Left click on the close button, middle click on a tab: Close the tab.
+//   - Drag a tab with the left mouse button: Move the tab.
+//   - Left click on the empty part of the strip: Give the strip keyboard focus.
+//
+// The following keys switch tabs:
+//
+//   - Ctrl-Tab: Switch to the next tab.
+//   - Ctrl-Shift-Tab: Switch to the previous tab.
+//   - Alt-1 to Alt-9: Switch to the first to ninth tab.
+//
+// As key events are only sent to the primitive which has focus, these keys
+// only reach the Tabs object itself while its strip has focus. To make them
+// work while one of the tabs' primitives has focus, install CaptureShortcuts()
+// as the application's key capture (see Application.SetKeyCapture()) or as the
+// input capture of the primitives. While the strip has focus, the following
+// keys are supported as well:
+//
+//   - Left arrow, Right arrow: Switch to the previous or next tab.
+//   - Home, End: Switch to the first or last tab.
+//   - Shift-Left arrow, Shift-Right arrow: Move the current tab left or right.
+//   - Delete: Close the current tab if it is closable.
+//   - Enter, Tab, Down arrow (Up arrow if the strip is at the bottom): Move the
+//     focus to the current tab's primitive.
+type Tabs struct {
+	*Box
+
+	// The tabs, in the order they are shown.
+	tabs []*tab
+
+	// The index of the current tab, -1 if there are no tabs.
+	current int
+
+	// TabsTop or TabsBottom.
+	position int
+
+	// The index of the leftmost tab shown in the strip.
+	firstTab int
+
+	// Whether the strip needs to be scrolled such that the current tab is
+	// visible the next time it is drawn.
+	showCurrent bool
+
+	// Whether or not there are hidden tabs left or right of the strip, as of the
+	// last call to Draw().
+	moreLeft, moreRight bool
+
+	// The screen position of the strip as of the last call to Draw().
+	stripX, stripY, stripWidth int
+
+	// The strip's background color.
+	stripColor color.RGBA
+
+	// The colors of the tabs and of the current tab.
+	tabTextColor, tabBackgroundColor       color.RGBA
+	activeTextColor, activeBackgroundColor color.RGBA
+
+	// Whether or not the strip has keyboard focus (rather than the current tab's
+	// primitive).
+	stripFocus bool
+
+	// The index of the tab which is being dragged with the mouse, -1 if none.
+	dragging int
+
+	// We keep a reference to the function which allows us to set the focus to
+	// the primitive of a newly selected tab.
+	setFocus func(p Primitive)
+
+	// An optional handler which is called when a tab becomes the current tab.
+	changed func(name string, index int)
+
+	// An optional handler which is called when a tab was closed.
+	closed func(name string, item Primitive)
+}
+
+// NewTabs returns a new Tabs object without any tabs.
+func NewTabs() *Tabs {
+	t := &Tabs{
+		Box:                   NewBox(),
+		current:               -1,
+		dragging:              -1,
+		stripColor:            Styles.ContrastBackgroundColor,
+		tabTextColor:          Styles.SecondaryTextColor,
+		tabBackgroundColor:    Styles.ContrastBackgroundColor,
+		activeTextColor:       Styles.PrimaryTextColor,
+		activeBackgroundColor: Styles.PrimitiveBackgroundColor,
+	}
+	t.focus = t
+	return t
+}
+
+// SetTabPosition sets where the tab strip is located: TabsTop (the default) or
+// TabsBottom.
+func (t *Tabs) SetTabPosition(position int) *Tabs {
+	t.position = position
+	return t
+}
+
+// SetStripColor sets the background color of the tab strip.
+func (t *Tabs) SetStripColor(color color.RGBA) *Tabs {
+	t.stripColor = color
+	return t
+}
+
+// SetTabColors sets the text and background colors of the tabs and of the
+// current tab.
+func (t *Tabs) SetTabColors(textColor, backgroundColor, activeTextColor, activeBackgroundColor color.RGBA) *Tabs {
+	t.tabTextColor, t.tabBackgroundColor = textColor, backgroundColor
+	t.activeTextColor, t.activeBackgroundColor = activeTextColor, activeBackgroundColor
+	return t
+}
+
+// SetChangedFunc sets a handler which is called when a tab becomes the current
+// tab, either by user interaction or programmatically. The handler receives the
+// tab's name and index.
+func (t *Tabs) SetChangedFunc(handler func(name string, index int)) *Tabs {
+	t.changed = handler
+	return t
+}
+
+// SetClosedFunc sets a handler which is called when a tab was closed with
+// CloseTab(), e.g. when the user clicked its close button. The handler receives
+// the tab's name and primitive. It is not called for RemoveTab().
+func (t *Tabs) SetClosedFunc(handler func(name string, item Primitive)) *Tabs {
+	t.closed = handler
+	return t
+}
+
+// AddTab adds a new tab with the given name, title, and primitive at the end of
+// the strip. If there was previously a tab with the same name, it is replaced
+// in place. The title may contain color tags. If "closable" is true, the tab
+// has a close button.
+//
+// The first tab which is added becomes the current tab.
+func (t *Tabs) AddTab(name, title string, item Primitive, closable bool) *Tabs {
+	newTab := &tab{Name: name, Title: title, Item: item, Closable: closable}
+	for index, tb := range t.tabs {
+		if tb.Name == name {
+			hasFocus := t.HasFocus()
+			if index == t.current && tb.Item != nil && tb.Item != item {
+				detach(tb.Item)
+			}
+			t.tabs[index] = newTab
+			if index == t.current && hasFocus && t.setFocus != nil {
+				t.Focus(t.setFocus)
+			}
+			return t
+		}
+	}
+	t.tabs = append(t.tabs, newTab)
+	if t.current < 0 {
+		t.setCurrent(0)
+	}
+	return t
+}
+
+// AddAndSwitchToTab calls AddTab(), then SwitchToTab() on that newly added
+// tab.
+func (t *Tabs) AddAndSwitchToTab(name, title string, item Primitive, closable bool) *Tabs {
+	t.AddTab(name, title, item, closable)
+	t.SwitchToTab(name)
+	return t
+}
+
+// RemoveTab removes the tab with the given name. If it was the current tab,
+// the next tab becomes the current tab.
+func (t *Tabs) RemoveTab(name string) *Tabs {
+	if index := t.indexOf(name); index >= 0 {
+		t.removeTab(index)
+	}
+	return t
+}
+
+// CloseTab removes the tab with the given name like RemoveTab() and then calls
+// the handler set with SetClosedFunc().
+func (t *Tabs) CloseTab(name string) *Tabs {
+	if index := t.indexOf(name); index >= 0 {
+		t.closeTab(index)
+	}
+	return t
+}
+
+// HasTab returns true if a tab with the given name exists.
+func (t *Tabs) HasTab(name string) bool {
+	return t.indexOf(name) >= 0
+}
+
+// GetTab returns the primitive of the tab with the given name or nil if there
+// is no such tab.
+func (t *Tabs) GetTab(name string) Primitive {
+	if index := t.indexOf(name); index >= 0 {
+		return t.tabs[index].Item
+	}
+	return nil
+}
+
+// GetTabCount returns the number of tabs.
+func (t *Tabs) GetTabCount() int {
+	return len(t.tabs)
+}
+
+// GetTabNames returns the names of all tabs in the order they are shown.
+func (t *Tabs) GetTabNames() []string {
+	names := make([]string, len(t.tabs))
+	for index, tb := range t.tabs {
+		names[index] = tb.Name
+	}
+	return names
+}
+
+// SetTabTitle changes the title of the tab with the given name.
+func (t *Tabs) SetTabTitle(name, title string) *Tabs {
+	if index := t.indexOf(name); index >= 0 {
+		t.tabs[index].Title = title
+	}
+	return t
+}
+
+// SwitchToTab makes the tab with the given name the current tab.
+func (t *Tabs) SwitchToTab(name string) *Tabs {
+	if index := t.indexOf(name); index >= 0 {
+		t.setCurrent(index)
+	}
+	return t
+}
+
+// SetCurrentTab makes the tab with the given index the current tab. Indices
+// out of range are ignored.
+func (t *Tabs) SetCurrentTab(index int) *Tabs {
+	t.setCurrent(index)
+	return t
+}
+
+// GetCurrentTab returns the index and the name of the current tab. The index is
+// -1 if there are no tabs.
+func (t *Tabs) GetCurrentTab() (index int, name string) {
+	if t.current < 0 {
+		return -1, ""
+	}
+	return t.current, t.tabs[t.current].Name
+}
+
+// MoveTab moves the tab with the given name to the given index, shifting the
+// tabs in between.
+func (t *Tabs) MoveTab(name string, index int) *Tabs {
+	if from := t.indexOf(name); from >= 0 {
+		t.moveTab(from, index)
+	}
+	return t
+}
+
+// CaptureShortcuts switches tabs when the given event is one of the tab
+// switching keys (Ctrl-Tab, Ctrl-Shift-Tab, Alt-1 to Alt-9), returning nil in
+// that case. Other events are returned unchanged. The function has the
+// signature of an input capture, so it can be used like this:
+//
+//   app.SetKeyCapture(tabs.CaptureShortcuts)
+func (t *Tabs) CaptureShortcuts(event pixelgl.Event) pixelgl.Event {
+	ev, ok := event.(*pixelgl.KeyEv)
+	if !ok || len(t.tabs) == 0 {
+		return event
+	}
+	ctrl := ev.Mods&pixelgl.ModControl != 0
+	shift := ev.Mods&pixelgl.ModShift != 0
+	switch {
+	case ev.Key == pixelgl.KeyTab && ctrl && shift, ev.Key == pixelgl.KeyBacktab && ctrl:
+		t.setCurrent((t.current + len(t.tabs) - 1) % len(t.tabs))
+	case ev.Key == pixelgl.KeyTab && ctrl:
+		t.setCurrent((t.current + 1) % len(t.tabs))
+	case ev.Key == pixelgl.KeyRune && ev.Mods&pixelgl.ModAlt != 0 && ev.Ch >= '1' && ev.Ch <= '9':
+		t.setCurrent(int(ev.Ch - '1'))
+	default:
+		return event
+	}
+	return nil
+}
+
+// indexOf returns the index of the tab with the given name or -1 if there is
+// none.
+func (t *Tabs) indexOf(name string) int {
+	for index, tb := range t.tabs {
+		if tb.Name == name {
+			return index
+		}
+	}
+	return -1
+}
+
+// setCurrent makes the tab with the given index the current tab, moving the
+// focus along if the tabs have focus.
+func (t *Tabs) setCurrent(index int) {
+	if index < 0 || index >= len(t.tabs) || index == t.current {
+		return
+	}
+	hasFocus := t.HasFocus()
+	if t.current >= 0 && t.tabs[t.current].Item != nil {
+		detach(t.tabs[t.current].Item)
+	}
+	t.current = index
+	t.showCurrent = true
+	if t.changed != nil {
+		t.changed(t.tabs[index].Name, index)
+	}
+	if hasFocus && t.setFocus != nil {
+		t.Focus(t.setFocus)
+	}
+}
+
+// removeTab removes the tab with the given index.
+func (t *Tabs) removeTab(index int) {
+	hasFocus := t.HasFocus()
+	tb := t.tabs[index]
+	t.tabs = append(t.tabs[:index], t.tabs[index+1:]...)
+	if index < t.current {
+		t.current--
+	} else if index == t.current {
+		if tb.Item != nil {
+			detach(tb.Item)
+		}
+		if t.current >= len(t.tabs) {
+			t.current = len(t.tabs) - 1
+		}
+		t.showCurrent = true
+		if t.current >= 0 && t.changed != nil {
+			t.changed(t.tabs[t.current].Name, t.current)
+		}
+	}
+	if hasFocus && t.setFocus != nil {
+		t.Focus(t.setFocus)
+	}
+}
+
+// closeTab removes the tab with the given index and calls the closed handler.
+func (t *Tabs) closeTab(index int) {
+	tb := t.tabs[index]
+	t.removeTab(index)
+	if t.closed != nil {
+		t.closed(tb.Name, tb.Item)
+	}
+}
+
+// moveTab moves the tab at index "from" to index "to".
+func (t *Tabs) moveTab(from, to int) {
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(t.tabs) {
+		to = len(t.tabs) - 1
+	}
+	if from == to {
+		return
+	}
+	var current *tab
+	if t.current >= 0 {
+		current = t.tabs[t.current]
+	}
+	tb := t.tabs[from]
+	t.tabs = append(t.tabs[:from], t.tabs[from+1:]...)
+	t.tabs = append(t.tabs[:to], append([]*tab{tb}, t.tabs[to:]...)...)
+	for index, tb := range t.tabs {
+		if tb == current {
+			t.current = index
+		}
+	}
+	t.showCurrent = true
+}
+
+// tabAt returns the index of the tab drawn at the given screen column of the
+// strip or -1 if there is none.
+func (t *Tabs) tabAt(x int) int {
+	for index, tb := range t.tabs {
+		if tb.visible && x >= tb.x && x < tb.x+tb.width {
+			return index
+		}
+	}
+	return -1
+}
+
+// onCloseButton returns whether the given screen column of the strip lies on
+// the close button of the tab with the given index.
+func (t *Tabs) onCloseButton(index, x int) bool {
+	tb := t.tabs[index]
+	return tb.Closable && tb.visible && tb.width == tb.fullWidth() && x >= tb.x+tb.width-2
+}
+
+// Focus is called when this primitive receives focus. The focus is passed on
+// to the current tab's primitive unless the strip has focus.
+func (t *Tabs) Focus(delegate func(p Primitive)) {
+	if delegate == nil {
+		return // We cannot delegate so we cannot focus.
+	}
+	t.setFocus = delegate
+	if !t.stripFocus && t.current >= 0 && t.tabs[t.current].Item != nil {
+		delegate(t.tabs[t.current].Item)
+		return
+	}
+	t.Box.Focus(delegate)
+}
+
+// Blur is called when this primitive loses focus.
+func (t *Tabs) Blur() {
+	t.Box.Blur()
+	t.stripFocus = false
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (t *Tabs) HasFocus() bool {
+	if t.hasFocus {
+		return true
+	}
+	for _, tb := range t.tabs {
+		if tb.Item != nil && tb.Item.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// Detach passes the call on to the current tab's primitive.
+func (t *Tabs) Detach() {
+	if t.current >= 0 && t.tabs[t.current].Item != nil {
+		detach(t.tabs[t.current].Item)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (t *Tabs) Draw(screen ubcell.Screen) {
+	t.Box.Draw(screen)
+
+	x, y, width, height := t.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	stripY, contentY := y, y+1
+	if t.position == TabsBottom {
+		stripY, contentY = y+height-1, y
+	}
+	t.drawStrip(screen, x, stripY, width)
+
+	if t.current >= 0 {
+		if item := t.tabs[t.current].Item; item != nil {
+			item.SetRect(x, contentY, width, height-1)
+			item.Draw(screen)
+		}
+	}
+}
+
+// drawStrip draws the tab strip at the given position.
+func (t *Tabs) drawStrip(screen ubcell.Screen, x, y, width int) {
+	t.stripX, t.stripY, t.stripWidth = x, y, width
+	stripStyle := ubcell.StyleDefault.Background(t.stripColor).Foreground(t.tabTextColor)
+	for cx := x; cx < x+width; cx++ {
+		screen.SetContent(cx, y, ' ', stripStyle)
+	}
+
+	// Which tabs fit into the strip? If not all of them do, we reserve a cell on
+	// each side for the arrows.
+	total := 0
+	for _, tb := range t.tabs {
+		total += tb.fullWidth()
+	}
+	start, end := x, x+width
+	if total > width {
+		start++
+		end--
+	} else {
+		t.firstTab = 0
+	}
+	widthOf := func(from, to int) (width int) {
+		for index := from; index <= to && index < len(t.tabs); index++ {
+			width += t.tabs[index].fullWidth()
+		}
+		return
+	}
+	if t.showCurrent && t.current >= 0 {
+		if t.current < t.firstTab {
+			t.firstTab = t.current
+		}
+		for t.firstTab < t.current && widthOf(t.firstTab, t.current) > end-start {
+			t.firstTab++
+		}
+	}
+	t.showCurrent = false
+	for t.firstTab > 0 && widthOf(t.firstTab-1, len(t.tabs)-1) <= end-start {
+		t.firstTab-- // Don't waste space on the right.
+	}
+	if t.firstTab >= len(t.tabs) {
+		t.firstTab = len(t.tabs) - 1
+	}
+	if t.firstTab < 0 {
+		t.firstTab = 0
+	}
+
+	// Draw the tabs.
+	pos := start
+	t.moreRight = false
+	for index, tb := range t.tabs {
+		tb.visible = false
+		if index < t.firstTab {
+			continue
+		}
+		tabWidth := tb.fullWidth()
+		if pos+tabWidth > end {
+			t.moreRight = true
+			tabWidth = end - pos
+		}
+		if tabWidth <= 0 {
+			continue
+		}
+		tb.visible, tb.x, tb.width = true, pos, tabWidth
+
+		textColor, backgroundColor := t.tabTextColor, t.tabBackgroundColor
+		if index == t.current {
+			textColor, backgroundColor = t.activeTextColor, t.activeBackgroundColor
+			if t.stripFocus && t.hasFocus {
+				textColor, backgroundColor = backgroundColor, textColor
+			}
+		}
+		tabStyle := ubcell.StyleDefault.Background(backgroundColor).Foreground(textColor)
+		for cx := pos; cx < pos+tabWidth; cx++ {
+			screen.SetContent(cx, y, ' ', tabStyle)
+		}
+		titleWidth := tabWidth - 1
+		if tb.Closable {
+			titleWidth -= 2
+		}
+		Print(screen, tb.Title, pos+1, y, titleWidth, AlignLeft, textColor)
+		if tb.Closable && tabWidth == tb.fullWidth() {
+			screen.SetContent(pos+tabWidth-2, y, '×', tabStyle)
+		}
+		pos += tabWidth
+	}
+
+	// Draw the arrows.
+	t.moreLeft = total > width && t.firstTab > 0
+	if t.moreLeft {
+		screen.SetContent(x, y, '◀', stripStyle)
+	}
+	if t.moreRight {
+		screen.SetContent(x+width-1, y, '▶', stripStyle)
+	}
+}
+
+// KeyHandler returns the handler for this primitive. It receives keys while
+// the strip has focus.
+func (t *Tabs) KeyHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return t.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		if t.CaptureShortcuts(event) == nil {
+			return
+		}
+		ev, ok := event.(*pixelgl.KeyEv)
+		if !ok || len(t.tabs) == 0 {
+			return
+		}
+		shift := ev.Mods&pixelgl.ModShift != 0
+		switch ev.Key {
+		case pixelgl.KeyLeft:
+			if shift {
+				t.moveTab(t.current, t.current-1)
+			} else {
+				t.setCurrent(t.current - 1)
+			}
+		case pixelgl.KeyRight:
+			if shift {
+				t.moveTab(t.current, t.current+1)
+			} else {
+				t.setCurrent(t.current + 1)
+			}
+		case pixelgl.KeyHome:
+			t.setCurrent(0)
+		case pixelgl.KeyEnd:
+			t.setCurrent(len(t.tabs) - 1)
+		case pixelgl.KeyDelete:
+			if t.tabs[t.current].Closable {
+				t.closeTab(t.current)
+			}
+		case pixelgl.KeyEnter, pixelgl.KeyTab, pixelgl.KeyDown, pixelgl.KeyUp:
+			if ev.Key == pixelgl.KeyDown && t.position == TabsBottom || ev.Key == pixelgl.KeyUp && t.position != TabsBottom {
+				break
+			}
+			t.stripFocus = false
+			setFocus(t)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *Tabs) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return t.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		ev, ok := event.(*pixelgl.CursorEvent)
+
+		// Move a dragged tab.
+		if ok && t.dragging >= 0 {
+			if ev.Act == pixelgl.RELEASE {
+				t.dragging = -1
+				t.app.releaseMouse()
+				return
+			}
+			if index := t.tabAt(ev.X); index >= 0 && index != t.dragging {
+				t.moveTab(t.dragging, index)
+				t.dragging = index
+			}
+			return
+		}
+
+		// Scrolling while the strip has focus switches tabs.
+		if scroll, isScroll := event.(*pixelgl.ScrollEvent); isScroll && t.stripFocus && t.hasFocus {
+			if scroll.Y > 0 {
+				t.setCurrent(t.current - 1)
+			} else if scroll.Y < 0 {
+				t.setCurrent(t.current + 1)
+			}
+			return
+		}
+
+		// Events outside of the strip are for the current tab's primitive.
+		if !ok || ev.Y != t.stripY || ev.X < t.stripX || ev.X >= t.stripX+t.stripWidth {
+			if t.current >= 0 {
+				passMouse(t.tabs[t.current].Item, event, setFocus)
+			}
+			return
+		}
+		if ev.Act != pixelgl.PRESS {
+			return
+		}
+		index := t.tabAt(ev.X)
+		switch {
+		case ev.Button != pixelgl.MouseButtonLeft && ev.Button != pixelgl.MouseButtonMiddle:
+		case ev.Button == pixelgl.MouseButtonLeft && t.moreLeft && ev.X == t.stripX: // Scroll left.
+			t.firstTab--
+		case ev.Button == pixelgl.MouseButtonLeft && t.moreRight && ev.X == t.stripX+t.stripWidth-1: // Scroll right.
+			t.firstTab++
+		case index < 0: // Empty part of the strip.
+			if ev.Button == pixelgl.MouseButtonLeft {
+				t.stripFocus = true
+				if !t.hasFocus {
+					setFocus(t)
+				}
+			}
+		case ev.Button == pixelgl.MouseButtonMiddle || t.onCloseButton(index, ev.X):
+			if t.tabs[index].Closable {
+				t.closeTab(index)
+			}
+		default: // Switch to the tab and start dragging it.
+			t.stripFocus = false
+			t.setCurrent(index)
+			setFocus(t)
+			t.dragging = index
+			t.app.grabMouse(t)
+		}
+	})
+}
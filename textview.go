@@ -6,6 +6,7 @@ import (
 	"image/color"
 	"regexp"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	runewidth "github.com/mattn/go-runewidth"
@@ -117,6 +118,9 @@ type TextView struct {
 	// If set to true, the text view will always remain at the end of the content.
 	trackEnd bool
 
+	// Animates changes of the line offset, see SetSmoothScrolling().
+	scroll smoothScroll
+
 	// The number of characters to be skipped on each line (not in wrap mode).
 	columnOffset int
 
@@ -183,6 +187,14 @@ func (t *TextView) SetScrollable(scrollable bool) *TextView {
 	return t
 }
 
+// SetSmoothScrolling sets the duration of the animation with which the text
+// view scrolls to a new line offset. 0 (the default) scrolls instantly. This has
+// no effect if the text view is not scrollable.
+func (t *TextView) SetSmoothScrolling(duration time.Duration) *TextView {
+	t.scroll.duration = duration
+	return t
+}
+
 // SetWrap sets the flag that, if true, leads to lines that are longer than the
 // available width being wrapped onto the next line. If false, any characters
 // beyond the available width are not displayed.
@@ -714,9 +726,13 @@ func (t *TextView) Draw(screen ubcell.Screen) {
 	}
 
 	// Draw the buffer.
-	for line := t.lineOffset; line < len(t.index); line++ {
+	lineOffset := t.lineOffset
+	if t.scrollable {
+		lineOffset = t.scroll.offset(screen, lineOffset)
+	}
+	for line := lineOffset; line < len(t.index); line++ {
 		// Are we done?
-		if line-t.lineOffset >= height {
+		if line-lineOffset >= height {
 			break
 		}
 
@@ -823,7 +839,7 @@ func (t *TextView) Draw(screen ubcell.Screen) {
 
 			// Draw the character.
 			for offset := 0; offset < chWidth; offset++ {
-				screen.SetContent(x+posX+offset, y+line-t.lineOffset, ch, style)
+				screen.SetContent(x+posX+offset, y+line-lineOffset, ch, style)
 			}
 
 			// Advance.
@@ -105,11 +105,12 @@ var joints = map[string]rune{
 
 // Common regular expressions.
 var (
-	colorPattern    = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-zA-Z]{6})\]`)
-	regionPattern   = regexp.MustCompile(`\["([a-zA-Z0-9_,;: \-\.]*)"\]`)
-	escapePattern   = regexp.MustCompile(`\[("[a-zA-Z0-9_,;: \-\.]*"|[a-zA-Z]+|#[0-9a-zA-Z]{6})\[(\[*)\]`)
-	boundaryPattern = regexp.MustCompile("([[:punct:]]\\s*|\\s+)")
-	spacePattern    = regexp.MustCompile(`\s+`)
+	colorPattern     = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-zA-Z]{6})\]`)
+	regionPattern    = regexp.MustCompile(`\["([a-zA-Z0-9_,;: \-\.]*)"\]`)
+	escapePattern    = regexp.MustCompile(`\[("[a-zA-Z0-9_,;: \-\.]*"|[a-zA-Z]+|#[0-9a-zA-Z]{6})\[(\[*)\]`)
+	nonEscapePattern = regexp.MustCompile(`(\[("[a-zA-Z0-9_,;: \-\.]*"|[a-zA-Z]+|#[0-9a-zA-Z]{6})\[*)\]`)
+	boundaryPattern  = regexp.MustCompile("([[:punct:]]\\s*|\\s+)")
+	spacePattern     = regexp.MustCompile(`\s+`)
 )
 
 // Predefined InputField acceptance functions.
@@ -301,6 +302,21 @@ func Print(screen ubcell.Screen, text string, x, y, maxWidth, align int, col col
 	return drawn, drawnWidth
 }
 
+// Escape escapes the given text such that color and region tags are not
+// recognized and substituted by the print functions of this package. For
+// example, to include a tag-like string in a box title or in a TextView:
+//
+//   box.SetTitle(tview.Escape("[squarebrackets]"))
+func Escape(text string) string {
+	return nonEscapePattern.ReplaceAllString(text, "$1[]")
+}
+
+// stripTags returns the given text without color tags, as it's printed.
+// Escaped tags are unescaped.
+func stripTags(text string) string {
+	return escapePattern.ReplaceAllString(colorPattern.ReplaceAllString(text, ""), "[$1$2]")
+}
+
 // PrintSimple prints white text to the screen at the given position.
 func PrintSimple(screen ubcell.Screen, text string, x, y int) {
 	Print(screen, text, x, y, math.MaxInt32, AlignLeft, Styles.PrimaryTextColor)
@@ -1,9 +1,14 @@
 package tview
 
 import (
+	"errors"
 	"image/color"
 	"image/gif"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/nowakf/pixel"
@@ -11,114 +16,541 @@ import (
 	"github.com/nowakf/ubcell"
 )
 
+// VideoPlayer is a box which plays an animation within its inner rect. The
+// frames are provided by a FrameSource (see SetSource() and Load()) and drawn
+// into the window as sprites, like Image.
+//
+// Playback starts when the player is drawn for the first time (unless Pause()
+// was called before) and runs in its own goroutine which requests frames from
+// the source and redraws the current frame on its own, without redrawing the
+// rest of the screen. The player's state is guarded by its own lock so all
+// functions may be called from any goroutine.
+//
+// When the player is hidden or removed from a container (see Detachable), the
+// playback goroutine is stopped. It is resumed the next time the player is
+// drawn.
 type VideoPlayer struct {
+	sync.Mutex
 	*Box
-	sequence *gif.GIF
-	mask     color.Color
-	control  chan controlSig
-	delay    int
 
-	finished func(ubcell.Screen)
-}
-type controlSig int
+	// The source of the frames. Nil if no animation was loaded.
+	source *videoSource
 
-const (
-	redraw controlSig = iota
-	complete
-)
+	// The frame currently shown. Nil if none was loaded yet.
+	picture *pixel.PictureData
 
-func NewVideoPlayer() *VideoPlayer {
-	return &VideoPlayer{
-		Box:   NewBox(),
-		delay: 60,
-	}
-}
-func (v *VideoPlayer) play(screen ubcell.Screen, delay int) {
+	// The display duration of the current frame, 0 for the default delay.
+	pictureDelay time.Duration
 
-	if v.sequence == nil {
-		panic("you didn't call load")
-	}
+	// The sprite used to draw the frames.
+	sprite *pixel.Sprite
 
-	v.control = make(chan controlSig)
+	// A color which is multiplied with the frames' colors.
+	mask color.Color
 
-	pic := pixel.PictureDataFromImage(v.sequence.Image[0])
+	// The default display duration of a frame in milliseconds.
+	delay int
 
-	sprite := pixel.NewSprite(pic, pic.Bounds())
+	// The playback speed, 1 being normal speed.
+	rate float64
 
-	step := time.NewTicker(time.Millisecond * time.Duration(delay))
+	// The number of times the animation is restarted, using the semantics of
+	// gif.GIF.LoopCount (0 loops forever, -1 plays once).
+	loopCount int
 
-	x, y, w, h := v.GetRect()
+	// The number of times the animation was restarted so far.
+	loops int
 
-	for i := 0; i < len(v.sequence.Image); i++ {
+	// The index of the frame currently shown.
+	current int
 
-		pic = pixel.PictureDataFromImage(v.sequence.Image[i])
+	// Whether or not the animation is supposed to be playing.
+	playing bool
 
-		sprite.Set(pic, pic.Bounds())
+	// Whether or not playback has finished.
+	ended bool
 
-		<-step.C
+	// Closed to stop the playback goroutine. Nil if it isn't running.
+	stop chan struct{}
+
+	// The screen and inner rect the player was last drawn to.
+	screen              ubcell.Screen
+	x, y, width, height int
+
+	// An optional function which is called when a different frame is shown.
+	frameChanged func(frame int)
+
+	// An optional function which is called when playback has finished.
+	finished func()
+}
+
+// videoCacheSize is the maximum number of bytes of decoded frames a
+// VideoPlayer keeps in memory. When the limit is reached, the frames which
+// were shown least recently are discarded and decoded again when needed.
+const videoCacheSize = 128 << 20
+
+// videoSource wraps the FrameSource of a VideoPlayer. Its lock serializes
+// access to the source and is held while a frame is loaded (which may block)
+// so that the player's own lock doesn't need to be held.
+type videoSource struct {
+	sync.Mutex
+	FrameSource
+
+	// The frames loaded recently, for sources with a known number of frames.
+	cache map[int]videoFrame
+
+	// The indices of the cached frames, the least recently used one first,
+	// and the number of bytes they occupy.
+	cacheOrder []int
+	cacheBytes int
+}
 
-		screen.Call(func(win *pixelgl.Window) {
-			sprite.DrawColorMask(win, v.GetTransform(screen, x, y, w, h), v.mask)
-		})
+// videoFrame is a frame loaded from a FrameSource.
+type videoFrame struct {
+	picture *pixel.PictureData
+	delay   time.Duration
+}
 
+// load returns the frame with the given index as a picture, along with its
+// display duration.
+func (s *videoSource) load(index int) (*pixel.PictureData, time.Duration, error) {
+	s.Lock()
+	defer s.Unlock()
+	if frame, ok := s.cache[index]; ok {
+		s.touch(index)
+		return frame.picture, frame.delay, nil
+	}
+	img, delay, err := s.Frame(index)
+	if err != nil {
+		return nil, 0, err
+	}
+	picture := pixel.PictureDataFromImage(img)
+	if s.FrameCount() >= 0 {
+		s.store(index, videoFrame{picture: picture, delay: delay})
 	}
-	v.finished(screen)
+	return picture, delay, nil
+}
 
+// touch marks a cached frame as the most recently used one.
+func (s *videoSource) touch(index int) {
+	for position, cached := range s.cacheOrder {
+		if cached == index {
+			s.cacheOrder = append(append(s.cacheOrder[:position], s.cacheOrder[position+1:]...), index)
+			return
+		}
+	}
 }
-func (v *VideoPlayer) GetTransform(screen ubcell.Screen, x, y, w, h int) pixel.Matrix {
-	return screen.GetMatrix(x, y, w, h)
+
+// store adds a frame to the cache, discarding the least recently used frames
+// if the cache would exceed videoCacheSize.
+func (s *videoSource) store(index int, frame videoFrame) {
+	size := len(frame.picture.Pix) * 4
+	if size > videoCacheSize {
+		return
+	}
+	for s.cacheBytes+size > videoCacheSize && len(s.cacheOrder) > 0 {
+		oldest := s.cacheOrder[0]
+		s.cacheOrder = s.cacheOrder[1:]
+		s.cacheBytes -= len(s.cache[oldest].picture.Pix) * 4
+		delete(s.cache, oldest)
+	}
+	s.cache[index] = frame
+	s.cacheOrder = append(s.cacheOrder, index)
+	s.cacheBytes += size
 }
-func (v *VideoPlayer) Load(path string) (*VideoPlayer, error) {
-	var err error
 
-	file, err := os.Open(path)
+// NewVideoPlayer returns a new video player.
+func NewVideoPlayer() *VideoPlayer {
+	return &VideoPlayer{
+		Box:     NewBox(),
+		delay:   60,
+		rate:    1,
+		playing: true,
+	}
+}
 
+// Load reads an animation from the given path, replacing the animation
+// previously loaded. GIF files (".gif"), animated PNG files (".png",
+// ".apng"), and directories of numbered PNG or JPEG files are supported. The
+// frame delays, disposal methods, and loop counts defined in GIF and APNG files
+// are honored. See SetSource() for other kinds of animations.
+func (v *VideoPlayer) Load(path string) (*VideoPlayer, error) {
+	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
 
-	defer file.Close()
-
-	v.sequence, err = gif.DecodeAll(file)
+	if info.IsDir() {
+		source, err := NewSequenceSource(path, 0)
+		if err != nil {
+			return nil, err
+		}
+		return v.SetSource(source), nil
+	}
 
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gif":
+		sequence, err := gif.DecodeAll(file)
+		if err != nil {
+			return nil, err
+		}
+		return v.SetSource(NewGIFSource(sequence)), nil
+	case ".png", ".apng":
+		source, err := DecodeAPNG(file)
+		if err != nil {
+			return nil, err
+		}
+		return v.SetSource(source), nil
+	}
 
-	return v, nil
+	return nil, errors.New("unsupported animation format")
 }
 
-//because overlaying videos is sometimes desirable, videoplayer can sometimes
-//be visible even if draw is not called.
-func (v *VideoPlayer) Draw(screen ubcell.Screen) {
+// SetSource sets the source of the frames to be played, replacing the
+// animation previously loaded, and rewinds to the first frame. If the source
+// defines a loop count (like GIFSource and APNGSource do), it replaces the
+// one set with SetLoopCount().
+func (v *VideoPlayer) SetSource(source FrameSource) *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
+	v.halt()
+	v.source = nil
+	if source != nil {
+		v.source = &videoSource{
+			FrameSource: source,
+			cache:       make(map[int]videoFrame),
+		}
+	}
+	if counter, ok := source.(loopCounter); ok {
+		v.loopCount = counter.LoopCount()
+	}
+	v.current, v.loops, v.ended = 0, 0, false
+	v.picture, v.sprite = nil, nil
+	v.start()
+	return v
+}
 
-	if v.finished == nil {
-		panic("finish func not set")
+// GetSource returns the source of the frames, nil if no animation was loaded.
+func (v *VideoPlayer) GetSource() FrameSource {
+	v.Lock()
+	defer v.Unlock()
+	if v.source == nil {
+		return nil
 	}
+	return v.source.FrameSource
+}
 
-	v.Box.Draw(screen)
+// Play starts or resumes playback. If playback had finished, it starts over.
+func (v *VideoPlayer) Play() *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
+	if v.ended {
+		v.current, v.loops, v.ended = 0, 0, false
+		v.picture = nil
+	}
+	v.playing = true
+	v.start()
+	return v
+}
+
+// Pause pauses playback, keeping the current frame.
+func (v *VideoPlayer) Pause() *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
+	v.playing = false
+	v.halt()
+	return v
+}
+
+// Stop stops playback and rewinds to the first frame. Sources which can't go
+// back (e.g. RawSource) keep showing the current frame.
+func (v *VideoPlayer) Stop() *VideoPlayer {
+	v.Lock()
+	v.playing = false
+	v.halt()
+	v.loops = 0
+	v.Unlock()
+	return v.Seek(0)
+}
 
-	if v.control == nil {
-		println("play!", v.delay)
-		go v.play(screen, v.delay)
+// IsPlaying returns whether or not the animation is playing (or will start
+// playing once it's drawn).
+func (v *VideoPlayer) IsPlaying() bool {
+	v.Lock()
+	defer v.Unlock()
+	return v.playing
+}
+
+// Seek shows the frame with the given index. The frame is shown for its full
+// duration if the animation is playing. This function blocks until the frame
+// was loaded from the source. Sources which can't go back (e.g. RawSource)
+// ignore indices lower than the current one.
+func (v *VideoPlayer) Seek(frame int) *VideoPlayer {
+	v.Lock()
+	source := v.source
+	if source == nil {
+		v.Unlock()
+		return v
+	}
+	if count := source.FrameCount(); count >= 0 && frame >= count {
+		frame = count - 1
+	}
+	if frame < 0 {
+		frame = 0
+	}
+	v.halt()
+	v.Unlock()
+
+	picture, delay, err := source.load(frame)
+
+	v.Lock()
+	defer v.Unlock()
+	if err == nil && v.source == source {
+		v.current, v.picture, v.pictureDelay = frame, picture, delay
+		v.ended = false
+	}
+	v.start()
+	return v
+}
+
+// GetFrame returns the index of the frame currently shown.
+func (v *VideoPlayer) GetFrame() int {
+	v.Lock()
+	defer v.Unlock()
+	return v.current
+}
+
+// GetFrameCount returns the number of frames of the animation, -1 if it isn't
+// known (e.g. for streams).
+func (v *VideoPlayer) GetFrameCount() int {
+	v.Lock()
+	defer v.Unlock()
+	if v.source == nil {
+		return 0
+	}
+	return v.source.FrameCount()
+}
+
+// SetRate sets the playback speed. A rate of 2 plays twice as fast, a rate of
+// 0.5 half as fast as the frame delays demand. Values less or equal 0 are
+// ignored.
+func (v *VideoPlayer) SetRate(rate float64) *VideoPlayer {
+	if rate <= 0 {
+		return v
 	}
+	v.Lock()
+	defer v.Unlock()
+	v.rate = rate
+	v.restart()
+	return v
+}
 
+// SetLoopCount sets the number of times the animation is restarted when it
+// reaches its last frame. A value of 0 loops forever, -1 plays the animation
+// only once. Sources which define a loop count (e.g. GIF and APNG files)
+// replace this value when they are set.
+func (v *VideoPlayer) SetLoopCount(count int) *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
+	v.loopCount = count
+	v.loops = 0
+	return v
 }
-func (v *VideoPlayer) SetDelay(delay int) {
+
+// SetDelay sets the display duration in milliseconds for frames which don't
+// define their own delay.
+func (v *VideoPlayer) SetDelay(delay int) *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
 	v.delay = delay
+	return v
 }
 
-func (v *VideoPlayer) SetMask(m color.RGBA) {
+// SetMask sets a color which is multiplied with the colors of the frames.
+func (v *VideoPlayer) SetMask(m color.RGBA) *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
 	v.mask = m
+	return v
+}
+
+// SetFrameChangedFunc sets a handler which is called whenever a different
+// frame is shown during playback. It receives the index of the new frame.
+func (v *VideoPlayer) SetFrameChangedFunc(handler func(frame int)) *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
+	v.frameChanged = handler
+	return v
+}
+
+// SetFinishedFunc sets a handler which is called when playback has finished,
+// i.e. when the last frame was shown and there are no loops left. It is called
+// from the playback goroutine.
+func (v *VideoPlayer) SetFinishedFunc(handler func()) *VideoPlayer {
+	v.Lock()
+	defer v.Unlock()
+	v.finished = handler
+	return v
+}
+
+// Detach stops the playback goroutine. It is resumed the next time the player
+// is drawn if the animation is still playing.
+func (v *VideoPlayer) Detach() {
+	v.Lock()
+	defer v.Unlock()
+	v.halt()
+	v.screen = nil
+}
+
+// start starts the playback goroutine if the animation is playing and the
+// player was drawn. The lock must be held.
+func (v *VideoPlayer) start() {
+	if v.stop != nil || !v.playing || v.screen == nil || v.source == nil {
+		return
+	}
+	v.stop = make(chan struct{})
+	go v.play(v.stop)
 }
 
-//this is kind of kludgy, but since there's no way to control
-//application focus from this level, it will have to do.
-func (v *VideoPlayer) SetFinishedFunc(f func()) {
-	v.finished = func(screen ubcell.Screen) {
-		close(v.control)
-		v.control = nil
-		f()
-		screen.Show()
+// halt stops the playback goroutine if it is running. The lock must be held.
+func (v *VideoPlayer) halt() {
+	if v.stop != nil {
+		close(v.stop)
+		v.stop = nil
 	}
 }
+
+// restart restarts the playback goroutine (if it is running) so that the
+// current frame's delay starts over. The lock must be held.
+func (v *VideoPlayer) restart() {
+	if v.stop != nil {
+		v.halt()
+		v.start()
+	}
+}
+
+// frameDelay returns the time the current frame is shown. The lock must be
+// held.
+func (v *VideoPlayer) frameDelay() time.Duration {
+	delay := v.pictureDelay
+	if delay <= 0 {
+		delay = time.Duration(v.delay) * time.Millisecond
+	}
+	return time.Duration(float64(delay) / v.rate)
+}
+
+// play advances the frames until playback has finished or until "stop" is
+// closed. Frames are loaded without holding the player's lock.
+func (v *VideoPlayer) play(stop chan struct{}) {
+	v.Lock()
+	source, index, loaded := v.source, v.current, v.picture != nil
+	v.Unlock()
+
+	for {
+		if loaded {
+			// Show the current frame for its duration.
+			v.Lock()
+			timer := time.NewTimer(v.frameDelay())
+			v.Unlock()
+
+			select {
+			case <-stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			index++
+		}
+
+		// Load the next frame, starting over if the animation has ended.
+		picture, delay, err := source.load(index)
+		if err == io.EOF && index > 0 {
+			v.Lock()
+			loop := v.loopCount == 0 || v.loops < v.loopCount
+			v.Unlock()
+			if loop {
+				index = 0
+				picture, delay, err = source.load(index)
+			}
+		}
+
+		v.Lock()
+		select {
+		case <-stop:
+			// We were stopped while loading the frame or waiting for the lock.
+			v.Unlock()
+			return
+		default:
+		}
+
+		if err != nil {
+			v.playing, v.ended = false, true
+			v.stop = nil
+			finished := v.finished
+			v.Unlock()
+			if finished != nil {
+				finished()
+			}
+			return
+		}
+
+		if index == 0 && loaded {
+			v.loops++
+		}
+		v.current, v.picture, v.pictureDelay = index, picture, delay
+		v.drawFrame()
+		screen, changed := v.screen, v.frameChanged
+		v.Unlock()
+		loaded = true
+
+		if screen != nil {
+			screen.Show()
+		}
+		if changed != nil {
+			changed(index)
+		}
+	}
+}
+
+// drawFrame draws the current frame onto the screen the player was last drawn
+// to. The lock must be held.
+func (v *VideoPlayer) drawFrame() {
+	if v.screen == nil || v.picture == nil || v.width <= 0 || v.height <= 0 {
+		return
+	}
+	picture := v.picture
+	if v.sprite == nil {
+		v.sprite = pixel.NewSprite(picture, picture.Bounds())
+	}
+	sprite, mask := v.sprite, v.mask
+	matrix := v.GetTransform(v.screen, v.x, v.y, v.width, v.height)
+	v.screen.Call(func(win *pixelgl.Window) {
+		sprite.Set(picture, picture.Bounds())
+		sprite.DrawColorMask(win, matrix, mask)
+	})
+}
+
+func (v *VideoPlayer) GetTransform(screen ubcell.Screen, x, y, w, h int) pixel.Matrix {
+	return screen.GetMatrix(x, y, w, h)
+}
+
+//because overlaying videos is sometimes desirable, videoplayer can sometimes
+//be visible even if draw is not called.
+func (v *VideoPlayer) Draw(screen ubcell.Screen) {
+
+	v.Box.Draw(screen)
+
+	v.Lock()
+	defer v.Unlock()
+	v.screen = screen
+	v.x, v.y, v.width, v.height = v.GetInnerRect()
+	v.drawFrame()
+	v.start()
+
+}
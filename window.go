@@ -0,0 +1,210 @@
+package tview
+
+import (
+	"github.com/nowakf/pixel/pixelgl"
+	"github.com/nowakf/ubcell"
+)
+
+// Window is a movable, resizable box with a border and a title bar which
+// floats on a Desktop. It shows one primitive, its content, inside its border.
+// The title is set with SetTitle().
+//
+// The title bar has two buttons on the right: one to minimize the window
+// ("_") and one to maximize it or restore its size ("□"). See Desktop for how
+// windows are moved and resized.
+type Window struct {
+	*Box
+
+	// The primitive shown in the window. May be nil.
+	content Primitive
+
+	// The window's rectangle relative to the desktop's inner rectangle when it
+	// is neither maximized nor minimized.
+	winX, winY, winWidth, winHeight int
+
+	// The minimum size of the window.
+	minWidth, minHeight int
+
+	// Whether or not the user may move and resize the window.
+	movable, resizable bool
+
+	// Whether or not the window is maximized or minimized.
+	maximized, minimized bool
+}
+
+// NewWindow returns a new window showing the given primitive, which may be nil.
+func NewWindow(content Primitive) *Window {
+	w := &Window{
+		Box:       NewBox(),
+		content:   content,
+		winWidth:  40,
+		winHeight: 10,
+		minWidth:  10,
+		minHeight: 3,
+		movable:   true,
+		resizable: true,
+	}
+	w.SetBorder(true)
+	w.focus = w
+	return w
+}
+
+// SetContent replaces the primitive shown in the window. It may be nil.
+func (w *Window) SetContent(content Primitive) *Window {
+	if w.content != nil && w.content != content {
+		detach(w.content)
+	}
+	w.content = content
+	return w
+}
+
+// GetContent returns the primitive shown in the window.
+func (w *Window) GetContent() Primitive {
+	return w.content
+}
+
+// SetPlacement sets the position and size of the window relative to the inner
+// rectangle of the desktop it is placed on. The size is not reduced below the
+// window's minimum size.
+func (w *Window) SetPlacement(x, y, width, height int) *Window {
+	w.winX, w.winY = x, y
+	w.winWidth, w.winHeight = width, height
+	w.clampSize()
+	return w
+}
+
+// GetPlacement returns the position and size of the window relative to the
+// inner rectangle of its desktop when it is neither maximized nor minimized.
+func (w *Window) GetPlacement() (x, y, width, height int) {
+	return w.winX, w.winY, w.winWidth, w.winHeight
+}
+
+// SetMinSize sets the minimum size of the window, including its border. It
+// defaults to 10x3.
+func (w *Window) SetMinSize(width, height int) *Window {
+	w.minWidth, w.minHeight = width, height
+	w.clampSize()
+	return w
+}
+
+// SetMovable sets whether or not the user may move the window.
+func (w *Window) SetMovable(movable bool) *Window {
+	w.movable = movable
+	return w
+}
+
+// SetResizable sets whether or not the user may resize the window.
+func (w *Window) SetResizable(resizable bool) *Window {
+	w.resizable = resizable
+	return w
+}
+
+// Maximize makes the window fill its desktop until it is restored.
+func (w *Window) Maximize() *Window {
+	w.maximized, w.minimized = true, false
+	return w
+}
+
+// Minimize hides the window. It is listed in the desktop's task bar from where
+// it can be restored.
+func (w *Window) Minimize() *Window {
+	if !w.minimized && w.content != nil {
+		detach(w.content)
+	}
+	w.minimized = true
+	return w
+}
+
+// Restore undoes Maximize() and Minimize().
+func (w *Window) Restore() *Window {
+	w.maximized, w.minimized = false, false
+	return w
+}
+
+// IsMaximized returns whether the window is maximized.
+func (w *Window) IsMaximized() bool {
+	return w.maximized
+}
+
+// IsMinimized returns whether the window is minimized.
+func (w *Window) IsMinimized() bool {
+	return w.minimized
+}
+
+// clampSize enforces the minimum size.
+func (w *Window) clampSize() {
+	if w.winWidth < w.minWidth {
+		w.winWidth = w.minWidth
+	}
+	if w.winHeight < w.minHeight {
+		w.winHeight = w.minHeight
+	}
+}
+
+// buttonAt returns which title bar button lies at the given screen cell: 'm'
+// for the minimize button, 'M' for the maximize button, or 0 for none.
+func (w *Window) buttonAt(x, y int) rune {
+	rectX, rectY, width, _ := w.GetRect()
+	if y != rectY || width < 8 {
+		return 0
+	}
+	switch x {
+	case rectX + width - 5:
+		return 'm'
+	case rectX + width - 3:
+		return 'M'
+	}
+	return 0
+}
+
+// Focus is called when this primitive receives focus.
+func (w *Window) Focus(delegate func(p Primitive)) {
+	if w.content != nil {
+		delegate(w.content)
+		return
+	}
+	w.Box.Focus(delegate)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (w *Window) HasFocus() bool {
+	return w.hasFocus || w.content != nil && w.content.GetFocusable().HasFocus()
+}
+
+// Detach passes the call on to the window's content.
+func (w *Window) Detach() {
+	if w.content != nil {
+		detach(w.content)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (w *Window) Draw(screen ubcell.Screen) {
+	w.Box.Draw(screen)
+
+	// Draw the title bar buttons.
+	x, y, width, _ := w.GetRect()
+	if width >= 8 {
+		style := ubcell.StyleDefault.Background(w.backgroundColor).Foreground(w.borderColor)
+		maximize := '□'
+		if w.maximized {
+			maximize = '▫'
+		}
+		screen.SetContent(x+width-5, y, '_', style)
+		screen.SetContent(x+width-3, y, maximize, style)
+	}
+
+	// Draw the content.
+	if w.content != nil {
+		w.content.SetRect(w.GetInnerRect())
+		w.content.Draw(screen)
+	}
+}
+
+// MouseHandler returns the mouse handler for this primitive. Events are passed
+// on to the content.
+func (w *Window) MouseHandler() func(event pixelgl.Event, setFocus func(p Primitive)) {
+	return w.WrapHandler(func(event pixelgl.Event, setFocus func(p Primitive)) {
+		passMouse(w.content, event, setFocus)
+	})
+}